@@ -9,6 +9,7 @@ import (
 	balance_decryptor "pandora-pay/cryptography/crypto/balance-decryptor"
 	"pandora-pay/helpers/generics"
 	"runtime"
+	"sync/atomic"
 )
 
 type AddressBalanceDecryptor struct {
@@ -60,6 +61,17 @@ func (decryptor *AddressBalanceDecryptor) DecryptBalance(decryptionName string,
 	return foundWork.result.decryptedBalance, nil
 }
 
+//returns the number of balances still waiting to be decrypted
+func (decryptor *AddressBalanceDecryptor) GetPendingCount() (count int) {
+	decryptor.all.Range(func(key string, work *addressBalanceDecryptorWork) bool {
+		if atomic.LoadInt32(&work.status) != ADDRESS_BALANCE_DECRYPTED_PROCESSED {
+			count += 1
+		}
+		return true
+	})
+	return
+}
+
 func NewAddressBalanceDecryptor() (*AddressBalanceDecryptor, error) {
 
 	threadsCount := config.CPU_THREADS
@@ -0,0 +1,121 @@
+package addresses
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const PAYMENT_REQUEST_URI_SCHEME = "pandorapay"
+
+//payment request shared as pandorapay:<address>?paymentId=&amount=&asset=&memo=&expiry=
+type PaymentRequest struct {
+	Address *Address `json:"address" msgpack:"address"` //PaymentID, PaymentAmount and PaymentAsset are the requested payment
+	Memo    string   `json:"memo,omitempty" msgpack:"memo,omitempty"`
+	Expiry  uint64   `json:"expiry,omitempty" msgpack:"expiry,omitempty"` //unix timestamp. 0 means it never expires
+}
+
+func (req *PaymentRequest) IsExpired(now uint64) bool {
+	return req.Expiry != 0 && now > req.Expiry
+}
+
+func (req *PaymentRequest) EncodeURI() string {
+
+	addr := *req.Address
+	addr.PaymentID, addr.PaymentAmount, addr.PaymentAsset = nil, 0, nil
+
+	values := url.Values{}
+	if len(req.Address.PaymentID) > 0 {
+		values.Set("paymentId", base64.StdEncoding.EncodeToString(req.Address.PaymentID))
+	}
+	if req.Address.PaymentAmount > 0 {
+		values.Set("amount", strconv.FormatUint(req.Address.PaymentAmount, 10))
+	}
+	if len(req.Address.PaymentAsset) > 0 {
+		values.Set("asset", base64.StdEncoding.EncodeToString(req.Address.PaymentAsset))
+	}
+	if req.Memo != "" {
+		values.Set("memo", req.Memo)
+	}
+	if req.Expiry > 0 {
+		values.Set("expiry", strconv.FormatUint(req.Expiry, 10))
+	}
+
+	uri := PAYMENT_REQUEST_URI_SCHEME + ":" + addr.EncodeAddr()
+	if len(values) > 0 {
+		uri += "?" + values.Encode()
+	}
+	return uri
+}
+
+//the payment fields of the URI must match the ones integrated in the address
+func DecodePaymentRequestURI(uri string) (*PaymentRequest, error) {
+
+	if !strings.HasPrefix(uri, PAYMENT_REQUEST_URI_SCHEME+":") {
+		return nil, errors.New("Invalid Payment Request scheme")
+	}
+
+	addressStr, query, _ := strings.Cut(uri[len(PAYMENT_REQUEST_URI_SCHEME)+1:], "?")
+
+	addr, err := DecodeAddr(addressStr)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentID := addr.PaymentID
+	if value := values.Get("paymentId"); value != "" {
+		var decoded []byte
+		if decoded, err = base64.StdEncoding.DecodeString(value); err != nil {
+			return nil, errors.New("Invalid PaymentID")
+		}
+		if len(paymentID) > 0 && !bytes.Equal(paymentID, decoded) {
+			return nil, errors.New("PaymentID is not matching the address")
+		}
+		paymentID = decoded
+	}
+
+	paymentAmount := addr.PaymentAmount
+	if value := values.Get("amount"); value != "" {
+		var decoded uint64
+		if decoded, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return nil, errors.New("Invalid Amount")
+		}
+		if paymentAmount > 0 && paymentAmount != decoded {
+			return nil, errors.New("Amount is not matching the address")
+		}
+		paymentAmount = decoded
+	}
+
+	paymentAsset := addr.PaymentAsset
+	if value := values.Get("asset"); value != "" {
+		var decoded []byte
+		if decoded, err = base64.StdEncoding.DecodeString(value); err != nil {
+			return nil, errors.New("Invalid Asset")
+		}
+		if len(paymentAsset) > 0 && !bytes.Equal(paymentAsset, decoded) {
+			return nil, errors.New("Asset is not matching the address")
+		}
+		paymentAsset = decoded
+	}
+
+	var expiry uint64
+	if value := values.Get("expiry"); value != "" {
+		if expiry, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return nil, errors.New("Invalid Expiry")
+		}
+	}
+
+	if addr, err = newAddr(addr.Network, addr.Version, addr.PublicKey, addr.Staked, addr.SpendPublicKey, addr.Registration, paymentID, paymentAmount, paymentAsset); err != nil {
+		return nil, err
+	}
+
+	return &PaymentRequest{addr, values.Get("memo"), expiry}, nil
+}
@@ -0,0 +1,40 @@
+package addresses
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+	"strings"
+	"testing"
+)
+
+func TestPaymentRequest_EncodeURI(t *testing.T) {
+
+	privateKey := GenerateNewPrivateKey()
+	address, err := privateKey.GenerateAddress(false, nil, false, helpers.RandomBytes(8), 1500, config_coins.NATIVE_ASSET_FULL)
+	assert.NoError(t, err)
+
+	req := &PaymentRequest{address, "order #12 & more", 1700000000}
+	uri := req.EncodeURI()
+	assert.True(t, strings.HasPrefix(uri, PAYMENT_REQUEST_URI_SCHEME+":"))
+
+	decoded, err := DecodePaymentRequestURI(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded.Address.PublicKey, address.PublicKey)
+	assert.Equal(t, decoded.Address.PaymentID, address.PaymentID)
+	assert.Equal(t, decoded.Address.PaymentAmount, address.PaymentAmount)
+	assert.Equal(t, decoded.Address.PaymentAsset, address.PaymentAsset)
+	assert.Equal(t, decoded.Memo, req.Memo)
+	assert.Equal(t, decoded.Expiry, req.Expiry)
+	assert.Equal(t, decoded.Address.EncodeAddr(), address.EncodeAddr())
+
+	//integrated address with the same payment fields
+	_, err = DecodePaymentRequestURI(PAYMENT_REQUEST_URI_SCHEME + ":" + address.EncodeAddr() + "?amount=1500")
+	assert.NoError(t, err)
+
+	_, err = DecodePaymentRequestURI(PAYMENT_REQUEST_URI_SCHEME + ":" + address.EncodeAddr() + "?amount=1501")
+	assert.Error(t, err)
+
+	_, err = DecodePaymentRequestURI("bitcoin:" + address.EncodeAddr())
+	assert.Error(t, err)
+}
@@ -26,10 +26,27 @@ var (
 	TxsBuilder              *txs_builder.TxsBuilder
 )
 
+//the forging is stopped before saving the mempool and the known nodes and closing the stores
 func Close() {
+	if Forging != nil {
+		Forging.Close()
+	}
+	if Mempool != nil {
+		if err := Mempool.Close(); err != nil {
+			gui.GUI.Error("Error saving mempool txs", err)
+		}
+	}
+	if Network != nil {
+		if err := Network.KnownNodes.Close(); err != nil {
+			gui.GUI.Error("Error saving known nodes", err)
+		}
+	}
+	if Chain != nil {
+		Chain.Close()
+	}
+	if Wallet != nil {
+		Wallet.Close()
+	}
 	store.DBClose()
 	gui.GUI.Close()
-	Forging.Close()
-	Chain.Close()
-	Wallet.Close()
 }
@@ -13,6 +13,7 @@ import (
 	"pandora-pay/blockchain/data_storage"
 	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/forging/forging_block_work"
+	"pandora-pay/blockchain/indexer"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
@@ -51,6 +52,7 @@ type Blockchain struct {
 	UpdateSocketsSubscriptionsTransactions  *multicast.MulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate]
 	UpdateSocketsSubscriptionsNotifications *multicast.MulticastChannel[*data_storage.DataStorage]
 	NextBlockCreatedCn                      chan *forging_block_work.ForgingWork
+	benchmark                               *blockchainBenchmark //only used by the replay
 }
 
 func (chain *Blockchain) validateBlocks(blocksComplete []*block_complete.BlockComplete) (err error) {
@@ -76,10 +78,19 @@ func (chain *Blockchain) validateBlocks(blocksComplete []*block_complete.BlockCo
 
 func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplete, calledByForging bool, exceptSocketUUID advanced_connection_types.UUID) (kernelHash []byte, err error) {
 
+	var start time.Time
+	if chain.benchmark != nil {
+		start = time.Now()
+	}
+
 	if err = chain.validateBlocks(blocksComplete); err != nil {
 		return
 	}
 
+	if chain.benchmark != nil {
+		chain.benchmark.proofs += time.Since(start)
+	}
+
 	//avoid processing the same function twice
 	chain.mutex.Lock()
 	defer chain.mutex.Unlock()
@@ -130,6 +141,8 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 
 		chain.mempool.SuspendProcessingCn <- struct{}{}
 
+		var updateEnd time.Time
+
 		err = store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
 
 			defer func() {
@@ -138,6 +151,12 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 				}
 			}()
 
+			if chain.benchmark != nil {
+				defer func() {
+					updateEnd = time.Now()
+				}()
+			}
+
 			savedBlock := false
 
 			dataStorage = data_storage.NewDataStorage(writer)
@@ -167,37 +186,13 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 			firstBlockComplete := blocksComplete[0]
 			if firstBlockComplete.Block.Height < newChainData.Height {
 
-				index := newChainData.Height - 1
-				for {
-
-					removedBlocksHeights = append(removedBlocksHeights, 0)
-					copy(removedBlocksHeights[1:], removedBlocksHeights)
-					removedBlocksHeights[0] = index
-
-					if allTransactionsChanges, err = chain.removeBlockComplete(writer, index, removedTxHashes, allTransactionsChanges, dataStorage); err != nil {
-						return
-					}
-
-					if index > firstBlockComplete.Block.Height {
-						index -= 1
-					} else {
-						break
-					}
-				}
-
 				if firstBlockComplete.Block.Height == 0 {
-					gui.GUI.Info("chain.createGenesisBlockchainData called")
-					newChainData = chain.createGenesisBlockchainData()
 					removedBlocksTransactionsCount = 0
 				} else {
 					removedBlocksTransactionsCount = newChainData.TransactionsCount
-					newChainData = &BlockchainData{}
-					if err = newChainData.loadBlockchainInfo(writer, firstBlockComplete.Block.Height); err != nil {
-						return
-					}
 				}
 
-				if err = dataStorage.CommitChanges(); err != nil {
+				if newChainData, removedBlocksHeights, allTransactionsChanges, err = chain.removeBlocksComplete(writer, newChainData, firstBlockComplete.Block.Height, removedTxHashes, allTransactionsChanges, dataStorage); err != nil {
 					return
 				}
 
@@ -312,6 +307,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return errors.New("Timestamp is too much into the future")
 					}
 
+					if chain.benchmark != nil {
+						start = time.Now()
+					}
+
 					if err = blkComplete.IncludeBlockComplete(dataStorage); err != nil {
 						return fmt.Errorf("Error including block %d into Blockchain: %s", blkComplete.Height, err.Error())
 					}
@@ -320,6 +319,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return errors.New("Error Processing Pending Stakes: " + err.Error())
 					}
 
+					if chain.benchmark != nil {
+						chain.benchmark.dataStorage += time.Since(start)
+					}
+
 					//to detect if the savedBlock was done correctly
 					savedBlock = false
 
@@ -436,6 +439,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 					removeTxsInfo(writer, removedTxHashes)
 				}
 
+				if err = chain.pruneBlocksComplete(writer, newChainData.Height); err != nil {
+					panic(err)
+				}
+
 				if err = chain.saveBlockchainHashmaps(dataStorage); err != nil {
 					panic(err)
 				}
@@ -454,6 +461,11 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 			return
 		})
 
+		//the time spent committing the store transaction
+		if chain.benchmark != nil && !updateEnd.IsZero() {
+			chain.benchmark.storeWrite += time.Since(updateEnd)
+		}
+
 		return
 	}()
 
@@ -510,6 +522,7 @@ func CreateBlockchain(mempool *mempool.Mempool, txsValidator *txs_validator.TxsV
 		multicast.NewMulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate](),
 		multicast.NewMulticastChannel[*data_storage.DataStorage](),
 		make(chan *forging_block_work.ForgingWork),
+		nil,
 	}
 
 	chain.updatesQueue.chain = chain
@@ -517,6 +530,8 @@ func CreateBlockchain(mempool *mempool.Mempool, txsValidator *txs_validator.TxsV
 	chain.updatesQueue.processBlockchainUpdateMempool()
 	chain.updatesQueue.processBlockchainUpdateNotifications()
 
+	chain.initCLI()
+
 	return chain, nil
 }
 
@@ -537,7 +552,9 @@ func (chain *Blockchain) InitializeChain() (err error) {
 	chainData := chain.GetChainData()
 	chainData.updateChainInfo()
 
-	return
+	return store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		return indexer.Init(writer, chainData.Height, config.INDEXER)
+	})
 }
 
 func (chain *Blockchain) Close() {
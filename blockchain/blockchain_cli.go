@@ -0,0 +1,120 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/base64"
+	"pandora-pay/addresses"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"strconv"
+)
+
+func (chain *Blockchain) initCLI() {
+
+	cliExportSnapshot := func(cmd string, ctx context.Context) (err error) {
+
+		chainData := chain.GetChainData()
+
+		height := gui.GUI.OutputReadUint64("Snapshot height. Leave empty for the current height", true, chainData.Height, func(value uint64) bool {
+			return value > 0 && value <= chainData.Height
+		})
+
+		secretKey := gui.GUI.OutputReadBytes("Secret key used to sign the snapshot", func(value []byte) bool {
+			return len(value) == cryptography.PrivateKeySize
+		})
+
+		privateKey, err := addresses.NewPrivateKey(secretKey)
+		if err != nil {
+			return
+		}
+
+		filename := gui.GUI.OutputReadFilename("Path to export", "snapshot")
+
+		if err = chain.ExportSnapshot(height, privateKey, filename); err != nil {
+			return
+		}
+
+		hash, err := chain.OpenLoadBlockHash(height - 1)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Snapshot exported")
+		gui.GUI.OutputWrite("Signer", base64.StdEncoding.EncodeToString(privateKey.GeneratePublicKey()))
+		gui.GUI.OutputWrite("Chain hash", base64.StdEncoding.EncodeToString(hash))
+		return
+	}
+
+	cliVerifyChain := func(cmd string, ctx context.Context) (err error) {
+
+		chainData := chain.GetChainData()
+
+		fromHeight := gui.GUI.OutputReadUint64("From height. Leave empty to verify the entire chain", true, 0, func(value uint64) bool {
+			return value <= chainData.Height
+		})
+
+		rollback := gui.GUI.OutputReadBool("Roll back to the last consistent height? y/n. Leave empty for no", true, false)
+
+		return chain.VerifyChainAndRepair(fromHeight, rollback)
+	}
+
+	cliRollbackChain := func(cmd string, ctx context.Context) (err error) {
+
+		chainData := chain.GetChainData()
+
+		height := gui.GUI.OutputReadUint64("Height to roll back to. The blocks above it will be removed", false, 0, func(value uint64) bool {
+			return value < chainData.Height
+		})
+
+		if !gui.GUI.OutputReadBool("Are you sure? y/n. Leave empty for no", true, false) {
+			return
+		}
+
+		if err = chain.RollbackToHeight(height); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Chain rolled back to " + strconv.FormatUint(height, 10))
+		return
+	}
+
+	cliExportBlocks := func(cmd string, ctx context.Context) (err error) {
+
+		chainData := chain.GetChainData()
+
+		start := gui.GUI.OutputReadUint64("Start height", false, 0, func(value uint64) bool {
+			return value < chainData.Height
+		})
+
+		end := gui.GUI.OutputReadUint64("End height (exclusive). Leave empty for the current height", true, chainData.Height, func(value uint64) bool {
+			return value > start && value <= chainData.Height
+		})
+
+		filename := gui.GUI.OutputReadFilename("Path to export", "blocks")
+
+		if err = chain.ExportBlocks(start, end, filename); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Blocks exported")
+		return
+	}
+
+	cliImportBlocks := func(cmd string, ctx context.Context) (err error) {
+
+		filename := gui.GUI.OutputReadFilename("Path to import", "blocks")
+
+		if err = chain.ImportBlocks(filename); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Blocks imported")
+		return
+	}
+
+	gui.GUI.CommandDefineCallback("Export Snapshot", cliExportSnapshot, true)
+	gui.GUI.CommandDefineCallback("Verify Chain", cliVerifyChain, true)
+	gui.GUI.CommandDefineCallback("Rollback Chain", cliRollbackChain, true)
+	gui.GUI.CommandDefineCallback("Export Blocks", cliExportBlocks, true)
+	gui.GUI.CommandDefineCallback("Import Blocks", cliImportBlocks, true)
+}
@@ -112,6 +112,8 @@ func (chain *Blockchain) initializeNewChain(chainData *BlockchainData, dataStora
 		byte(config_coins.DECIMAL_SEPARATOR),
 		config_coins.MAX_SUPPLY_COINS_UNITS,
 		supply,
+		false,
+		false,
 		config_coins.BURN_PUBLIC_KEY,
 		config_coins.BURN_PUBLIC_KEY,
 		config_coins.NATIVE_ASSET_NAME,
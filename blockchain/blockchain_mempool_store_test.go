@@ -0,0 +1,86 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/helpers"
+	"pandora-pay/mempool"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder"
+	"testing"
+)
+
+func TestMempoolSaveAndLoad(t *testing.T) {
+
+	test := createTestChain(t)
+	test.forgeBlock(t)
+
+	chainData := test.chain.GetChainData()
+	assert.NoError(t, test.chain.mempool.LoadTxsFromStore(chainData.Height))
+
+	recipient, err := addresses.GenerateNewPrivateKey().GenerateAddress(false, nil, true, nil, 0, nil)
+	assert.NoError(t, err)
+
+	tx, err := test.txsBuilder.CreateZetherTx(&txs_builder.TxBuilderCreateZetherTxData{
+		Payloads: []*txs_builder.TxBuilderCreateZetherTxPayload{{
+			Sender:           test.sender.AddressEncoded,
+			Recipient:        recipient.EncodeAddr(),
+			Amount:           10,
+			DecryptedBalance: testSenderBalance,
+		}},
+	}, nil, true, true, false, true, context.Background(), func(string) {})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	//stops the periodic saving and saves the txs
+	assert.NoError(t, test.chain.mempool.Close())
+	assert.NoError(t, test.chain.mempool.Close())
+
+	//invalid txs stored before the restart
+	invalidSignature := append([]byte{}, tx.Bloom.Serialized...)
+	invalidSignature[len(invalidSignature)-1] ^= 1
+
+	assert.NoError(t, store.StoreMempool.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		assert.True(t, writer.Exists("mempoolTx:"+tx.Bloom.HashStr))
+
+		for _, serialized := range [][]byte{invalidSignature, helpers.RandomBytes(20)} {
+			w := helpers.NewBufferWriter()
+			w.WriteVariableBytes(serialized)
+			w.WriteBool(false)
+			writer.Put("mempoolTx:"+string(helpers.RandomBytes(32)), w.Bytes())
+		}
+		writer.Put("mempoolTx:"+string(helpers.RandomBytes(32)), []byte{})
+		return nil
+	}))
+
+	//restart
+	suspendTestMempool()
+	newMempool, err := mempool.CreateMempool(test.chain.txsValidator)
+	assert.NoError(t, err)
+	testMempool = newMempool
+	newMempool.OnBroadcastNewTransaction = func(txs []*transaction.Transaction, justCreated, awaitPropagation bool, exceptSocketUUID advanced_connection_types.UUID, ctx context.Context) []error {
+		return make([]error, len(txs))
+	}
+	newMempool.UpdateWork(chainData.Hash, chainData.Height)
+
+	assert.NoError(t, newMempool.LoadTxsFromStore(chainData.Height))
+	assert.NotNil(t, newMempool.Txs.Get(tx.Bloom.HashStr))
+	assert.Len(t, newMempool.Txs.GetTxsList(), 1)
+
+	//the dropped txs are removed from the store at the next save
+	assert.NoError(t, newMempool.Close())
+	assert.NoError(t, store.StoreMempool.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		count := 0
+		assert.NoError(t, reader.IteratePrefix("mempoolTx:", "", false, func(key string, value []byte) error {
+			count++
+			return nil
+		}))
+		assert.Equal(t, 1, count)
+		return nil
+	}))
+}
@@ -0,0 +1,153 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/genesis"
+	"pandora-pay/cryptography/multisig"
+	"pandora-pay/txs_builder"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_address"
+	"testing"
+)
+
+//runs the key generation of all the co-signers
+func generateTestThresholdKey(t *testing.T, threshold, total int) []*multisig.ThresholdKeyShare {
+
+	gens := make([]*multisig.KeyGeneration, total)
+	commitments := make([]*multisig.KeyGenerationCommitment, total)
+	for i := range gens {
+		var err error
+		gens[i], commitments[i], err = multisig.CreateKeyGeneration(uint64(i+1), threshold, total)
+		assert.NoError(t, err)
+	}
+
+	shares := make([]*multisig.ThresholdKeyShare, total)
+	for i, gen := range gens {
+
+		received := make([]*multisig.KeyGenerationShare, 0)
+		for _, other := range gens {
+			if other != gen {
+				share, err := other.GetShare(gen.Index)
+				assert.NoError(t, err)
+				received = append(received, share)
+			}
+		}
+
+		var err error
+		shares[i], err = multisig.CombineKeyGeneration(gen, commitments, received)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+	}
+
+	return shares
+}
+
+func TestMultisigSpend(t *testing.T) {
+
+	shares := generateTestThresholdKey(t, 2, 3)
+	key := shares[0].Key
+
+	var sender *wallet_address.WalletAddress
+	test := createTestChainWithAirDrops(t, createTestStore(t, "blockchain"), func(w *wallet.Wallet) []*genesis.GenesisDataAirDropType {
+		var err error
+		sender, err = w.AddNewAddressThresholdSpend(true, "multisig", false, key.PublicKey, false)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return []*genesis.GenesisDataAirDropType{{Address: sender.AddressRegistrationEncoded, Amount: testSenderBalance}}
+	})
+	test.forgeBlock(t)
+
+	recipient, err := addresses.GenerateNewPrivateKey().GenerateAddress(false, nil, true, nil, 0, nil)
+	assert.NoError(t, err)
+
+	tx, err := test.txsBuilder.CreateZetherTx(&txs_builder.TxBuilderCreateZetherTxData{
+		Payloads: []*txs_builder.TxBuilderCreateZetherTxPayload{{
+			Sender:           sender.AddressEncoded,
+			Recipient:        recipient.EncodeAddr(),
+			Amount:           10,
+			DecryptedBalance: testSenderBalance,
+		}},
+	}, nil, true, true, false, true, context.Background(), func(string) {})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	//the tx waits for the threshold signature
+	assert.Empty(t, test.chain.mempool.Txs.GetTxsList())
+
+	sessions := txs_builder.GetMultisigSessions(tx)
+	if !assert.Len(t, sessions, 1) {
+		t.FailNow()
+	}
+	session := sessions[0]
+	assert.Equal(t, key.PublicKey, session.PublicKey)
+
+	_, err = session.GetTx(generateTestThresholdKey(t, 2, 3)[0].Key.PublicKey)
+	assert.EqualError(t, err, "Threshold Key is not matching the session")
+
+	sessionTx, err := session.GetTx(key.PublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.HashManual(), sessionTx.HashManual())
+
+	//the last signer signs lastMessage
+	sign := func(signers []int, message, lastMessage []byte) ([]*multisig.Commitment, []*multisig.PartialSignature) {
+
+		nonces := make([]*multisig.Nonce, len(signers))
+		commitments := make([]*multisig.Commitment, len(signers))
+		for i, signer := range signers {
+			nonces[i], commitments[i], err = multisig.CreateNonce(shares[signer])
+			assert.NoError(t, err)
+		}
+
+		partials := make([]*multisig.PartialSignature, len(signers))
+		for i, signer := range signers {
+			if i == len(signers)-1 {
+				message = lastMessage
+			}
+			partials[i], err = multisig.Sign(shares[signer], nonces[i], message, commitments)
+			assert.NoError(t, err)
+		}
+
+		return commitments, partials
+	}
+
+	//any threshold co-signers
+	for _, signers := range [][]int{{0, 1}, {1, 2}, {0, 1, 2}} {
+		commitments, partials := sign(signers, session.Message, session.Message)
+
+		signedTx, err := test.txsBuilder.CombineMultisig(session, key, commitments, partials, false, true, false, context.Background(), func(string) {})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.NoError(t, signedTx.Verify())
+		assert.Empty(t, txs_builder.GetMultisigSessions(signedTx))
+	}
+
+	//below threshold
+	_, commitment, err := multisig.CreateNonce(shares[0])
+	assert.NoError(t, err)
+	_, err = test.txsBuilder.CombineMultisig(session, key, []*multisig.Commitment{commitment}, nil, false, true, false, context.Background(), func(string) {})
+	assert.EqualError(t, err, "Not enough commitments")
+
+	//a share of another threshold key
+	wrongShare := &multisig.ThresholdKeyShare{key, 1, generateTestThresholdKey(t, 2, 3)[0].Share}
+	_, _, err = multisig.CreateNonce(wrongShare)
+	assert.EqualError(t, err, "Share is not matching the share public key")
+
+	//a co-signer signed another message
+	commitments, partials := sign([]int{0, 1}, session.Message, []byte("other message"))
+	_, err = test.txsBuilder.CombineMultisig(session, key, commitments, partials, false, true, false, context.Background(), func(string) {})
+	assert.EqualError(t, err, "Partial signature 2 is invalid")
+
+	//the signed tx is propagated
+	commitments, partials = sign([]int{0, 2}, session.Message, session.Message)
+	signedTx, err := test.txsBuilder.CombineMultisig(session, key, commitments, partials, true, true, false, context.Background(), func(string) {})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NotNil(t, test.chain.mempool.Txs.Get(signedTx.Bloom.HashStr))
+}
@@ -0,0 +1,121 @@
+package blockchain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"os"
+	"pandora-pay/config"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_bolt"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"testing"
+)
+
+func createTestBoltStore(t *testing.T, name string) *store.Store {
+	db, err := store_db_bolt.CreateStoreDBBolt(name)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return &store.Store{Name: name, Opened: true, DB: db}
+}
+
+//the bolt store is created in the working directory
+func chdirTestTemp(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+}
+
+func TestPruneBlocksComplete(t *testing.T) {
+
+	chdirTestTemp(t)
+
+	pruneBlocks, pruneBlocksMaxBatch := config.PRUNE_BLOCKS, config.PRUNE_BLOCKS_MAX_BATCH
+	defer func() {
+		config.PRUNE_BLOCKS, config.PRUNE_BLOCKS_MAX_BATCH = pruneBlocks, pruneBlocksMaxBatch
+	}()
+
+	test := createTestChainWithStore(t, createTestBoltStore(t, "blockchain"))
+
+	blkTxHashes := [][]byte{}
+	for i := 0; i < 10; i++ {
+		blkComplete := test.forgeBlock(t)
+		blkTxHashes = append(blkTxHashes, blkComplete.Txs[0].Bloom.Hash)
+	}
+
+	loadPrunedHeight := func() (prunedHeight uint64) {
+		assert.NoError(t, store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+			prunedHeight = test.chain.LoadPrunedHeight(reader)
+			return nil
+		}))
+		return
+	}
+	assert.Equal(t, uint64(0), loadPrunedHeight())
+
+	//pruning enabled on an existing chain is done in batches
+	config.PRUNE_BLOCKS = 3
+	config.PRUNE_BLOCKS_MAX_BATCH = 2
+
+	test.forgeBlock(t)
+	assert.Equal(t, uint64(2), loadPrunedHeight())
+	test.forgeBlock(t)
+	assert.Equal(t, uint64(4), loadPrunedHeight())
+
+	for loadPrunedHeight()+config.PRUNE_BLOCKS < test.chain.GetChainData().Height {
+		test.forgeBlock(t)
+	}
+
+	chainHeight := test.chain.GetChainData().Height
+	assert.Equal(t, chainHeight-3, loadPrunedHeight())
+
+	kept := 0
+	assert.NoError(t, store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		for height := uint64(0); height < chainHeight; height++ {
+
+			pruned := test.chain.IsBlockPruned(reader, height)
+			assert.Equal(t, height < chainHeight-3, pruned)
+
+			//the headers are kept
+			assert.True(t, reader.Exists("blockHash_ByHeight"+strconv.FormatUint(height, 10)))
+
+			data := reader.Get("blockTxs" + strconv.FormatUint(height, 10))
+			if pruned {
+				assert.Nil(t, data)
+				continue
+			}
+
+			kept++
+			txHashes := [][]byte{}
+			assert.NoError(t, msgpack.Unmarshal(data, &txHashes))
+			for _, txHash := range txHashes {
+				assert.True(t, reader.Exists("tx:"+string(txHash)))
+			}
+		}
+
+		//the txs of the pruned blocks are still known
+		for _, txHash := range blkTxHashes {
+			assert.False(t, reader.Exists("tx:"+string(txHash)))
+			assert.True(t, reader.Exists("txHash:"+string(txHash)))
+		}
+		return nil
+	}))
+	assert.Equal(t, 3, kept)
+
+	assert.EqualError(t, test.chain.RollbackToHeight(chainHeight-4), "Blocks below the pruned height can not be removed")
+
+	//the pruned height is loaded again after the store is reopened
+	suspendTestMempool()
+	assert.NoError(t, store.StoreBlockchain.DB.Close())
+
+	store.StoreBlockchain = createTestBoltStore(t, "blockchain")
+
+	assert.NoError(t, test.chain.loadBlockchain())
+	assert.Equal(t, chainHeight, test.chain.GetChainData().Height)
+	assert.Equal(t, chainHeight-3, loadPrunedHeight())
+
+	assert.NoError(t, store.StoreBlockchain.DB.Close())
+}
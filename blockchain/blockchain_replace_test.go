@@ -0,0 +1,123 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/mempool"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/txs_builder"
+	"pandora-pay/wallet"
+	"sort"
+	"testing"
+)
+
+func TestReplaceTxEndToEnd(t *testing.T) {
+
+	test := createTestChain(t)
+	test.forgeBlock(t)
+
+	createTx := func(decryptedBalance uint64) *transaction.Transaction {
+		recipient, err := addresses.GenerateNewPrivateKey().GenerateAddress(false, nil, true, nil, 0, nil)
+		assert.NoError(t, err)
+
+		tx, err := test.txsBuilder.CreateZetherTx(&txs_builder.TxBuilderCreateZetherTxData{
+			Payloads: []*txs_builder.TxBuilderCreateZetherTxPayload{{
+				Sender:           test.sender.AddressEncoded,
+				Recipient:        recipient.EncodeAddr(),
+				Amount:           10,
+				DecryptedBalance: decryptedBalance,
+			}},
+		}, nil, true, true, false, true, context.Background(), func(string) {})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return tx
+	}
+
+	decrypt := func(tx *transaction.Transaction) *wallet.DecryptZetherPayloadOutput {
+		decrypted, err := test.wallet.DecryptTx(tx, test.sender.PublicKey)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return decrypted.ZetherTx.Payloads[0]
+	}
+
+	zether := func(tx *transaction.Transaction) *transaction_zether.TransactionZether {
+		return tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+	}
+
+	ring := func(tx *transaction.Transaction) []string {
+		out := make([]string, 0)
+		for _, publicKey := range zether(tx).Bloom.PublicKeyLists[0] {
+			out = append(out, string(publicKey))
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	tx := createTx(testSenderBalance)
+	assert.NotNil(t, test.chain.mempool.Txs.Get(tx.Bloom.HashStr))
+
+	//a peer receives the broadcasted txs without knowing which tx is replaced
+	peer, err := mempool.CreateMempool(test.chain.txsValidator)
+	assert.NoError(t, err)
+	defer func() {
+		peer.SuspendProcessingCn <- struct{}{}
+	}()
+	peer.OnBroadcastNewTransaction = func(txs []*transaction.Transaction, justCreated, awaitPropagation bool, exceptSocketUUID advanced_connection_types.UUID, ctx context.Context) []error {
+		return make([]error, len(txs))
+	}
+	chainData := test.chain.GetChainData()
+	peer.UpdateWork(chainData.Hash, chainData.Height)
+	assert.NoError(t, peer.AddTxToMempool(tx, chainData.Height, false, true, false, advanced_connection_types.UUID_ALL, context.Background()))
+
+	//the chain kernel hash changed after the tx was created
+	peer.SuspendProcessingCn <- struct{}{}
+	test.forgeBlock(t)
+	peer.ContinueProcessing(mempool.CONTINUE_PROCESSING_NO_ERROR)
+	chainData = test.chain.GetChainData()
+	peer.UpdateWork(chainData.Hash, chainData.Height)
+
+	replacement, err := test.txsBuilder.CreateZetherReplacementTx(tx.Bloom.Hash, nil, true, true, false, context.Background(), func(string) {})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	//the mempool keeps only the replacement
+	assert.Nil(t, test.chain.mempool.Txs.Get(tx.Bloom.HashStr))
+	assert.NotNil(t, test.chain.mempool.Txs.Get(replacement.Bloom.HashStr))
+	assert.Len(t, test.chain.mempool.Txs.GetTxsList(), 1)
+
+	//the replacement pays the same transfer with a higher fee over the same ring
+	assert.Greater(t, test.chain.mempool.Txs.Get(replacement.Bloom.HashStr).FeePerByte, zether(tx).Payloads[0].Statement.Fee/tx.Bloom.Size)
+	assert.Equal(t, ring(tx), ring(replacement))
+
+	decryptedTx, decryptedReplacement := decrypt(tx), decrypt(replacement)
+	assert.True(t, decryptedReplacement.WhisperSenderValid)
+	assert.Equal(t, zether(tx).Bloom.PublicKeyLists[0][decryptedTx.RecipientIndex], zether(replacement).Bloom.PublicKeyLists[0][decryptedReplacement.RecipientIndex])
+	assert.Equal(t, decryptedTx.SentAmount-zether(tx).Payloads[0].Statement.Fee, decryptedReplacement.SentAmount-zether(replacement).Payloads[0].Statement.Fee)
+
+	//the peer evicts the replaced tx as the replacement spends the same sender ring
+	assert.NotNil(t, peer.Txs.Get(tx.Bloom.HashStr))
+	assert.NoError(t, peer.AddTxToMempool(replacement, chainData.Height, false, true, false, advanced_connection_types.UUID_ALL, context.Background()))
+	assert.Nil(t, peer.Txs.Get(tx.Bloom.HashStr))
+	assert.NotNil(t, peer.Txs.Get(replacement.Bloom.HashStr))
+	assert.Len(t, peer.Txs.GetTxsList(), 1)
+
+	//the replaced tx received again pays less
+	assert.EqualError(t, peer.AddTxToMempool(tx, chainData.Height, false, true, false, advanced_connection_types.UUID_ALL, context.Background()), "Transaction conflicts with a mempool transaction and the fee per byte is not higher")
+	assert.Len(t, peer.Txs.GetTxsList(), 1)
+
+	//the replaced tx is not pending anymore
+	_, err = test.txsBuilder.CreateZetherReplacementTx(tx.Bloom.Hash, nil, true, true, false, context.Background(), func(string) {})
+	assert.EqualError(t, err, "Transaction to be replaced was not found in mempool")
+
+	//an independent payment of the same sender to a new recipient is chained after the replacement
+	chained := createTx(testSenderBalance - decryptedReplacement.SentAmount)
+	assert.NotNil(t, test.chain.mempool.Txs.Get(chained.Bloom.HashStr))
+	assert.NotNil(t, test.chain.mempool.Txs.Get(replacement.Bloom.HashStr))
+	assert.Len(t, test.chain.mempool.Txs.GetTxsList(), 2)
+}
@@ -0,0 +1,298 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"io"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/blocks/blocks_file"
+	"pandora-pay/blockchain/genesis"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"time"
+)
+
+type blockchainBenchmark struct {
+	total       time.Duration
+	proofs      time.Duration //blocks and transactions verification
+	dataStorage time.Duration //including the transactions and committing the DataStorage
+	storeWrite  time.Duration //writing the blocks and committing the store transaction
+}
+
+//writes the blocks [start, end) and the state at start
+func (chain *Blockchain) ExportBlocks(start, end uint64, path string) (err error) {
+
+	if config.CONSENSUS != config.CONSENSUS_TYPE_FULL {
+		return errors.New("Exporting blocks requires a full node")
+	}
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if start >= end || end > chain.GetChainData().Height {
+			return errors.New("Blocks range is invalid")
+		}
+		if chain.IsBlockPruned(reader, start) {
+			return errors.New("Blocks below the pruned height can not be exported")
+		}
+
+		chainData := &BlockchainData{}
+		if err = chainData.loadBlockchainInfo(reader, end); err != nil {
+			return
+		}
+
+		header := &blocks_file.BlocksFileHeader{
+			Version:     blocks_file.BLOCKS_FILE_VERSION,
+			Network:     config.NETWORK_SELECTED,
+			GenesisHash: genesis.GenesisData.Hash,
+			Start:       start,
+			End:         end,
+			Hash:        chainData.Hash,
+			KernelHash:  chainData.KernelHash,
+		}
+
+		if start > 0 {
+			var snapshot *BlockchainSnapshot
+			if snapshot, err = chain.createSnapshot(reader, start); err != nil {
+				return
+			}
+			if header.Snapshot, err = msgpack.Marshal(snapshot); err != nil {
+				return
+			}
+		}
+
+		fileWriter, err := blocks_file.CreateBlocksFileWriter(path, header)
+		if err != nil {
+			return
+		}
+		defer func() {
+			if errClose := fileWriter.Close(); err == nil {
+				err = errClose
+			}
+		}()
+
+		for height := start; height < end; height++ {
+
+			heightStr := strconv.FormatUint(height, 10)
+
+			hash := reader.Get("blockHash_ByHeight" + heightStr)
+			if hash == nil {
+				return errors.New("Block hash was not found")
+			}
+			blockData := reader.Get("block_ByHash" + string(hash))
+			if blockData == nil {
+				return errors.New("Block was not found")
+			}
+			data := reader.Get("blockTxs" + heightStr)
+			if data == nil {
+				return errors.New("Block txs were not found")
+			}
+
+			txHashes := [][]byte{}
+			if err = msgpack.Unmarshal(data, &txHashes); err != nil {
+				return
+			}
+
+			//same serialization as BlockComplete.AdvancedSerialization
+			w := helpers.NewBufferWriter()
+			w.Write(blockData)
+			w.WriteUvarint(uint64(len(txHashes)))
+			for _, txHash := range txHashes {
+				txData := reader.Get("tx:" + string(txHash))
+				if txData == nil {
+					return errors.New("Tx was not found")
+				}
+				w.Write(txData)
+			}
+
+			if err = fileWriter.WriteBlock(w.Bytes()); err != nil {
+				return
+			}
+		}
+
+		gui.GUI.Info("Exported blocks " + strconv.FormatUint(start, 10) + " ... " + strconv.FormatUint(end, 10))
+		return
+	})
+}
+
+func openBlocksFile(path string) (*blocks_file.BlocksFileReader, error) {
+
+	fileReader, err := blocks_file.OpenBlocksFileReader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if fileReader.Header.Network != config.NETWORK_SELECTED {
+		err = errors.New("Blocks file network is different")
+	} else if !bytes.Equal(fileReader.Header.GenesisHash, genesis.GenesisData.Hash) {
+		err = errors.New("Blocks file genesis is different")
+	}
+	if err != nil {
+		fileReader.Close()
+		return nil, err
+	}
+
+	return fileReader, nil
+}
+
+func readBlocksFileBlock(fileReader *blocks_file.BlocksFileReader, height uint64) (*block_complete.BlockComplete, error) {
+
+	blkComplete, err := fileReader.ReadBlock()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("Blocks file is missing blocks")
+		}
+		return nil, err
+	}
+	if blkComplete.Height != height {
+		return nil, fmt.Errorf("Blocks file has block %d instead of %d", blkComplete.Height, height)
+	}
+
+	return blkComplete, nil
+}
+
+//verifies and adds the blocks in batches. The blocks already stored are skipped
+func (chain *Blockchain) ImportBlocks(path string) (err error) {
+
+	fileReader, err := openBlocksFile(path)
+	if err != nil {
+		return
+	}
+	defer fileReader.Close()
+
+	header := fileReader.Header
+
+	if header.Start > chain.GetChainData().Height {
+		return errors.New("Chain is missing the blocks before the blocks file")
+	}
+
+	gui.GUI.Info("Importing blocks " + strconv.FormatUint(header.Start, 10) + " ... " + strconv.FormatUint(header.End, 10))
+
+	var batch []*block_complete.BlockComplete
+	for height := header.Start; height < header.End; height++ {
+
+		var blkComplete *block_complete.BlockComplete
+		if blkComplete, err = readBlocksFileBlock(fileReader, height); err != nil {
+			return
+		}
+
+		if len(batch) == 0 {
+			if hash, err := chain.OpenLoadBlockHash(height); err == nil && bytes.Equal(hash, blkComplete.Bloom.Hash) {
+				continue
+			}
+		}
+
+		batch = append(batch, blkComplete)
+		if uint64(len(batch)) == config.IMPORT_BLOCKS_BATCH || height == header.End-1 {
+			if _, err = chain.AddBlocks(batch, false, advanced_connection_types.UUID_ALL); err != nil {
+				return fmt.Errorf("Error importing blocks %d ... %d: %s", height+1-uint64(len(batch)), height+1, err.Error())
+			}
+			batch = nil //AddBlocks keeps the blocks for the updates
+		}
+	}
+
+	hash, err := chain.OpenLoadBlockHash(header.End - 1)
+	if err != nil {
+		return
+	}
+	if !bytes.Equal(hash, header.Hash) {
+		return errors.New("Imported chain hash is not matching the blocks file")
+	}
+
+	gui.GUI.Info("Imported blocks " + strconv.FormatUint(header.Start, 10) + " ... " + strconv.FormatUint(header.End, 10))
+	return
+}
+
+//replays the exported blocks through AddBlocks and returns the timings of every block. It must be used with an empty memory store as it will insert the blocks in the store
+func (chain *Blockchain) replayBlocks(path string) (timings []*blockchainBenchmark, err error) {
+
+	fileReader, err := openBlocksFile(path)
+	if err != nil {
+		return
+	}
+	defer fileReader.Close()
+
+	header := fileReader.Header
+
+	if header.Start > 0 {
+		snapshot := &BlockchainSnapshot{}
+		if err = msgpack.Unmarshal(header.Snapshot, snapshot); err != nil {
+			return
+		}
+		if snapshot.Height != header.Start {
+			return nil, errors.New("Blocks file snapshot height is not matching")
+		}
+		if err = chain.importSnapshot(snapshot); err != nil {
+			return
+		}
+	}
+
+	if err = chain.InitializeChain(); err != nil {
+		return
+	}
+	if chain.GetChainData().Height != header.Start {
+		return nil, errors.New("Chain store is not empty")
+	}
+
+	gui.GUI.Info("Replaying blocks " + strconv.FormatUint(header.Start, 10) + " ... " + strconv.FormatUint(header.End, 10))
+
+	defer func() {
+		chain.benchmark = nil
+	}()
+
+	for height := header.Start; height < header.End; height++ {
+
+		var blkComplete *block_complete.BlockComplete
+		if blkComplete, err = readBlocksFileBlock(fileReader, height); err != nil {
+			return
+		}
+
+		chain.benchmark = &blockchainBenchmark{}
+
+		start := time.Now()
+		if _, err = chain.AddBlocks([]*block_complete.BlockComplete{blkComplete}, false, advanced_connection_types.UUID_SKIP_ALL); err != nil {
+			return nil, fmt.Errorf("Error replaying block %d: %s", height, err.Error())
+		}
+		chain.benchmark.total = time.Since(start)
+
+		gui.GUI.Log(fmt.Sprintf("Replayed block %d txs %d total %s proofs %s dataStorage %s storeWrite %s", height, len(blkComplete.Txs), chain.benchmark.total, chain.benchmark.proofs, chain.benchmark.dataStorage, chain.benchmark.storeWrite))
+
+		timings = append(timings, chain.benchmark)
+	}
+
+	chainData := chain.GetChainData()
+	if !bytes.Equal(chainData.Hash, header.Hash) || !bytes.Equal(chainData.KernelHash, header.KernelHash) {
+		return nil, errors.New("Replayed chain hash is not matching the blocks file")
+	}
+
+	return
+}
+
+func (chain *Blockchain) ReplayBlocks(path string) error {
+
+	timings, err := chain.replayBlocks(path)
+	if err != nil {
+		return err
+	}
+
+	sum := &blockchainBenchmark{}
+	for _, timing := range timings {
+		sum.total += timing.total
+		sum.proofs += timing.proofs
+		sum.dataStorage += timing.dataStorage
+		sum.storeWrite += timing.storeWrite
+	}
+
+	count := time.Duration(len(timings))
+	gui.GUI.Info(fmt.Sprintf("Replayed %d blocks. Final hash matches", len(timings)))
+	gui.GUI.Info(fmt.Sprintf("Total %s proofs %s dataStorage %s storeWrite %s", sum.total, sum.proofs, sum.dataStorage, sum.storeWrite))
+	gui.GUI.Info(fmt.Sprintf("Per block %s proofs %s dataStorage %s storeWrite %s", sum.total/count, sum.proofs/count, sum.dataStorage/count, sum.storeWrite/count))
+
+	return nil
+}
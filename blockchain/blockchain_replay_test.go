@@ -0,0 +1,139 @@
+package blockchain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"pandora-pay/blockchain/blocks/blocks_file"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/mempool"
+	"pandora-pay/store"
+	"pandora-pay/txs_validator"
+	"path/filepath"
+	"testing"
+)
+
+//creates a new chain with an empty store and the same genesis
+func createTestReplayChain(t *testing.T) *Blockchain {
+
+	suspendTestMempool()
+
+	store.StoreBlockchain = createTestStore(t, "replay")
+
+	txsValidator, err := txs_validator.NewTxsValidator()
+	assert.NoError(t, err)
+	mempool, err := mempool.CreateMempool(txsValidator)
+	assert.NoError(t, err)
+	testMempool = mempool
+	chain, err := CreateBlockchain(mempool, txsValidator)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return chain
+}
+
+func TestReplayBlocks(t *testing.T) {
+
+	test := createTestChain(t)
+	for i := 0; i < 5; i++ {
+		test.forgeBlock(t)
+	}
+	chainData := test.chain.GetChainData()
+
+	path := filepath.Join(t.TempDir(), "blocks")
+	assert.NoError(t, test.chain.ExportBlocks(0, 5, path))
+
+	chain := createTestReplayChain(t)
+	timings, err := chain.replayBlocks(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, chainData.Height, chain.GetChainData().Height)
+	assert.Equal(t, chainData.Hash, chain.GetChainData().Hash)
+	assert.Equal(t, chainData.KernelHash, chain.GetChainData().KernelHash)
+
+	assert.Len(t, timings, 5)
+	for _, timing := range timings {
+		assert.Positive(t, timing.proofs)
+		assert.Positive(t, timing.dataStorage)
+		assert.Positive(t, timing.storeWrite)
+		assert.LessOrEqual(t, timing.proofs+timing.dataStorage+timing.storeWrite, timing.total)
+	}
+
+	//the file starting inside the chain includes the state
+	test = createTestChain(t)
+	for i := 0; i < 5; i++ {
+		test.forgeBlock(t)
+	}
+	assert.NoError(t, test.chain.ExportBlocks(2, 5, path))
+
+	chain = createTestReplayChain(t)
+	timings, err = chain.replayBlocks(path)
+	assert.NoError(t, err)
+	assert.Len(t, timings, 3)
+	assert.Equal(t, test.chain.GetChainData().Hash, chain.GetChainData().Hash)
+}
+
+func TestReplayBlocksHashMismatch(t *testing.T) {
+
+	test := createTestChain(t)
+	for i := 0; i < 3; i++ {
+		test.forgeBlock(t)
+	}
+
+	path := filepath.Join(t.TempDir(), "blocks")
+	assert.NoError(t, test.chain.ExportBlocks(0, 3, path))
+
+	//the same blocks with a different final hash
+	tampered := filepath.Join(t.TempDir(), "tampered")
+
+	fileReader, err := blocks_file.OpenBlocksFileReader(path)
+	assert.NoError(t, err)
+
+	header := *fileReader.Header
+	header.Hash = helpers.RandomBytes(cryptography.HashSize)
+
+	fileWriter, err := blocks_file.CreateBlocksFileWriter(tampered, &header)
+	assert.NoError(t, err)
+	for {
+		blkComplete, err := fileReader.ReadBlock()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		assert.NoError(t, fileWriter.WriteBlock(blkComplete.SerializeToBytes()))
+	}
+	assert.NoError(t, fileWriter.Close())
+	assert.NoError(t, fileReader.Close())
+
+	_, err = createTestReplayChain(t).replayBlocks(tampered)
+	assert.EqualError(t, err, "Replayed chain hash is not matching the blocks file")
+}
+
+func TestImportBlocks(t *testing.T) {
+
+	test := createTestChain(t)
+	for i := 0; i < 6; i++ {
+		test.forgeBlock(t)
+	}
+	chainData := test.chain.GetChainData()
+
+	path := filepath.Join(t.TempDir(), "blocks")
+	assert.NoError(t, test.chain.ExportBlocks(0, 6, path))
+
+	//the blocks 0 ... 2 are already stored and they are skipped
+	assert.NoError(t, test.chain.RollbackToHeight(3))
+	assert.NoError(t, test.chain.ImportBlocks(path))
+	assert.Equal(t, chainData.Height, test.chain.GetChainData().Height)
+	assert.Equal(t, chainData.Hash, test.chain.GetChainData().Hash)
+
+	//all the blocks are already stored
+	assert.NoError(t, test.chain.ImportBlocks(path))
+	assert.Equal(t, chainData.Hash, test.chain.GetChainData().Hash)
+
+	//the file must start inside the chain
+	assert.NoError(t, test.chain.ExportBlocks(4, 6, path))
+	assert.NoError(t, test.chain.RollbackToHeight(2))
+	assert.EqualError(t, test.chain.ImportBlocks(path), "Chain is missing the blocks before the blocks file")
+}
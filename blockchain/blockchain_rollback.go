@@ -0,0 +1,101 @@
+package blockchain
+
+import (
+	"errors"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/mempool"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+//removes the blocks [height, chain height) and reverts the DataStorage using the stored transitions
+func (chain *Blockchain) RollbackToHeight(height uint64) (err error) {
+
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	chainData := chain.GetChainData()
+	if height >= chainData.Height {
+		return errors.New("Height must be lower than the chain height")
+	}
+
+	gui.GUI.Warning("Rolling back the chain from " + strconv.FormatUint(chainData.Height, 10) + " to " + strconv.FormatUint(height, 10))
+
+	var newChainData *BlockchainData
+	var dataStorage *data_storage.DataStorage
+	var removedTxsList [][]byte
+	removedTxHashes := make(map[string][]byte)
+	allTransactionsChanges := []*blockchain_types.BlockchainTransactionUpdate{}
+
+	chain.mempool.SuspendProcessingCn <- struct{}{}
+
+	err = store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if chain.IsBlockPruned(writer, height) {
+			return errors.New("Blocks below the pruned height can not be removed")
+		}
+
+		dataStorage = data_storage.NewDataStorage(writer)
+		defer dataStorage.SetTx(nil)
+
+		var removedBlocksHeights []uint64
+		if newChainData, removedBlocksHeights, allTransactionsChanges, err = chain.removeBlocksComplete(writer, chainData, height, removedTxHashes, allTransactionsChanges, dataStorage); err != nil {
+			return
+		}
+		newChainData.ConsecutiveSelfForged = 0
+
+		for _, removedBlock := range removedBlocksHeights {
+			if err = chain.deleteUnusedBlocksComplete(writer, removedBlock, dataStorage); err != nil {
+				return
+			}
+		}
+
+		if config.SEED_WALLET_NODES_INFO {
+			removeUnusedTransactions(writer, newChainData.TransactionsCount, chainData.TransactionsCount)
+		}
+
+		removedTxsList = make([][]byte, 0, len(allTransactionsChanges))
+		for _, change := range allTransactionsChanges {
+			removedTxsList = append(removedTxsList, writer.Get("tx:"+change.TxHashStr))
+			writer.Delete("tx:" + change.TxHashStr)
+			writer.Delete("txHash:" + change.TxHashStr)
+			writer.Delete("txBlock:" + change.TxHashStr)
+		}
+
+		if config.SEED_WALLET_NODES_INFO {
+			removeTxsInfo(writer, removedTxHashes)
+		}
+
+		if err = chain.saveBlockchainHashmaps(dataStorage); err != nil {
+			return
+		}
+
+		newChainData.AssetsCount = dataStorage.Asts.Count
+		newChainData.AccountsCount = dataStorage.Regs.Count + dataStorage.PlainAccs.Count
+
+		newChainData.saveBlockchainHeight(writer)
+		return newChainData.saveBlockchain(writer)
+	})
+
+	if err != nil {
+		chain.mempool.ContinueProcessingCn <- mempool.CONTINUE_PROCESSING_ERROR
+		return
+	}
+
+	chain.ChainData.Store(newChainData)
+	chain.mempool.ContinueProcessingCn <- mempool.CONTINUE_PROCESSING_NO_ERROR
+
+	chain.updatesQueue.updatesCn <- &BlockchainUpdate{
+		newChainData:           newChainData,
+		dataStorage:            dataStorage,
+		allTransactionsChanges: allTransactionsChanges,
+		removedTxHashes:        removedTxHashes,
+		removedTxsList:         removedTxsList,
+	}
+
+	return
+}
@@ -0,0 +1,128 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/indexer"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/helpers"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder"
+	"pandora-pay/wallet/wallet_invoice"
+	"testing"
+	"time"
+)
+
+func TestRollbackToHeight(t *testing.T) {
+
+	test := createTestChain(t)
+
+	blocks := make([]*block_complete.BlockComplete, 5)
+	for i := range blocks {
+		blocks[i] = test.forgeBlock(t)
+	}
+	chainData := test.chain.GetChainData()
+
+	assert.Error(t, test.chain.RollbackToHeight(5))
+
+	assert.NoError(t, test.chain.RollbackToHeight(3))
+	assert.Equal(t, uint64(3), test.chain.GetChainData().Height)
+	assert.Equal(t, blocks[2].Bloom.Hash, test.chain.GetChainData().Hash)
+
+	_, err := test.chain.OpenLoadBlockHash(3)
+	assert.Error(t, err)
+
+	//the removed blocks can be added again
+	removed := make([]*block_complete.BlockComplete, 2)
+	for i := range removed {
+		removed[i] = block_complete.CreateEmptyBlockComplete()
+		assert.NoError(t, removed[i].Deserialize(helpers.NewBufferReader(blocks[3+i].SerializeToBytes())))
+		assert.NoError(t, removed[i].BloomAll())
+	}
+
+	_, err = test.chain.AddBlocks(removed, false, advanced_connection_types.UUID_ALL)
+	assert.NoError(t, err)
+	assert.Equal(t, chainData.Height, test.chain.GetChainData().Height)
+	assert.Equal(t, chainData.Hash, test.chain.GetChainData().Hash)
+	assert.Equal(t, chainData.KernelHash, test.chain.GetChainData().KernelHash)
+
+	hash, err := test.chain.OpenLoadBlockHash(4)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[4].Bloom.Hash, hash)
+
+	//the state is the same, so the chain continues
+	test.forgeBlock(t)
+	assert.Equal(t, uint64(6), test.chain.GetChainData().Height)
+}
+
+//the rollback update has no inserted blocks, so the subscribers remove the data of the blocks above the new chain height
+func TestRollbackSubscribers(t *testing.T) {
+
+	config.INDEXER = true
+	defer func() { config.INDEXER = false }()
+
+	test := createTestChain(t)
+	test.wallet.InitializeWallet(test.chain.UpdateNewChainUpdate)
+	test.forgeBlock(t)
+
+	payee, err := test.wallet.AddNewAddress(true, "payee", false, false, false)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	invoice, err := test.wallet.CreateInvoice(payee.PublicKey, 10, nil, "", 0)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	recipient, err := payee.PrivateKey.GenerateAddress(false, nil, true, invoice.PaymentID, 0, nil)
+	assert.NoError(t, err)
+
+	tx, err := test.txsBuilder.CreateZetherTx(&txs_builder.TxBuilderCreateZetherTxData{
+		Payloads: []*txs_builder.TxBuilderCreateZetherTxPayload{{
+			Sender:           test.sender.AddressEncoded,
+			Recipient:        recipient.EncodeAddr(),
+			Amount:           10,
+			DecryptedBalance: testSenderBalance,
+		}},
+	}, nil, false, false, false, true, context.Background(), func(string) {})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	height := test.chain.GetChainData().Height
+	test.forgeBlockWithTxs(t, []*transaction.Transaction{tx})
+	test.forgeBlock(t)
+
+	history := func() (txs []*indexer.IndexerTx) {
+		assert.NoError(t, store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+			txs, _, err = indexer.GetHistory(reader, payee.PublicKey, &indexer.IndexerHistoryFilter{Limit: 10})
+			return
+		}))
+		return
+	}
+
+	if assert.Len(t, history(), 1) {
+		assert.Equal(t, height, history()[0].BlockHeight)
+	}
+	assert.Eventually(t, func() bool {
+		return test.wallet.GetInvoice(invoice.PaymentID).GetStatus(0) == wallet_invoice.INVOICE_PAID
+	}, 10*time.Second, 10*time.Millisecond)
+
+	//the block of the payment is kept
+	assert.NoError(t, test.chain.RollbackToHeight(height+1))
+	assert.Len(t, history(), 1)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, wallet_invoice.INVOICE_PAID, test.wallet.GetInvoice(invoice.PaymentID).GetStatus(0))
+
+	assert.NoError(t, test.chain.RollbackToHeight(height))
+	assert.Empty(t, history())
+	assert.Eventually(t, func() bool {
+		invoice = test.wallet.GetInvoice(invoice.PaymentID)
+		return len(invoice.Payments) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(0), invoice.Paid)
+	assert.Equal(t, wallet_invoice.INVOICE_OPEN, invoice.GetStatus(0))
+}
@@ -0,0 +1,359 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"os"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/hash_map"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+const BLOCKCHAIN_SNAPSHOT_VERSION = uint64(0)
+
+type BlockchainSnapshotEntry struct {
+	Key   []byte `json:"key" msgpack:"key"`
+	Value []byte `json:"value" msgpack:"value"`
+}
+
+type BlockchainSnapshotAsset struct {
+	Asset            []byte                     `json:"asset" msgpack:"asset"`
+	Accounts         []*BlockchainSnapshotEntry `json:"accounts" msgpack:"accounts"`
+	FeeLiquidityHeap []*BlockchainSnapshotEntry `json:"feeLiquidityHeap" msgpack:"feeLiquidityHeap"`
+	FeeLiquidityDict []*BlockchainSnapshotEntry `json:"feeLiquidityDict" msgpack:"feeLiquidityDict"`
+}
+
+//the state of DataStorage after the block Height-1 was included
+type BlockchainSnapshot struct {
+	Version       uint64                     `json:"version" msgpack:"version"`
+	Network       uint64                     `json:"network" msgpack:"network"`
+	Height        uint64                     `json:"height" msgpack:"height"`
+	ChainData     *BlockchainData            `json:"chainData" msgpack:"chainData"`
+	ChainEntries  []*BlockchainSnapshotEntry `json:"chainEntries" msgpack:"chainEntries"` //headers and difficulties required to continue the sync
+	Registrations []*BlockchainSnapshotEntry `json:"registrations" msgpack:"registrations"`
+	PlainAccounts []*BlockchainSnapshotEntry `json:"plainAccounts" msgpack:"plainAccounts"`
+	PendingStakes []*BlockchainSnapshotEntry `json:"pendingStakes" msgpack:"pendingStakes"`
+	Assets        []*BlockchainSnapshotEntry `json:"assets" msgpack:"assets"`
+	AssetsMaps    []*BlockchainSnapshotAsset `json:"assetsMaps" msgpack:"assetsMaps"`
+}
+
+type BlockchainSnapshotSigned struct {
+	Snapshot  []byte `json:"snapshot" msgpack:"snapshot"`
+	PublicKey []byte `json:"publicKey" msgpack:"publicKey"`
+	Signature []byte `json:"signature" msgpack:"signature"`
+}
+
+func exportSnapshotHashMap(hashMap *hash_map.HashMap) (list []*BlockchainSnapshotEntry, err error) {
+	list = []*BlockchainSnapshotEntry{}
+	err = hashMap.Iterate("", false, func(key string, element hash_map.HashMapElementSerializableInterface) error {
+		list = append(list, &BlockchainSnapshotEntry{[]byte(key), helpers.SerializeToBytes(element)})
+		return nil
+	})
+	return
+}
+
+//indexes of Indexable HashMaps are recomputed in the order of the keys
+func importSnapshotHashMap(hashMap *hash_map.HashMap, list []*BlockchainSnapshotEntry) error {
+	for _, entry := range list {
+		element, err := hashMap.CreateObject(entry.Key, 0)
+		if err != nil {
+			return err
+		}
+		if err = element.Deserialize(helpers.NewBufferReader(entry.Value)); err != nil {
+			return err
+		}
+		if err = hashMap.Create(string(entry.Key), element); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (chain *Blockchain) createSnapshot(reader store_db_interface.StoreDBTransactionInterface, height uint64) (snapshot *BlockchainSnapshot, err error) {
+
+	chainInfoData := reader.Get("blockchainInfo")
+	if chainInfoData == nil {
+		return nil, errors.New("Chain not found")
+	}
+
+	chainData := &BlockchainData{}
+	if err = msgpack.Unmarshal(chainInfoData, chainData); err != nil {
+		return
+	}
+
+	if height == 0 || height > chainData.Height {
+		return nil, errors.New("Snapshot height is invalid")
+	}
+
+	snapshot = &BlockchainSnapshot{
+		Version:      BLOCKCHAIN_SNAPSHOT_VERSION,
+		Network:      config.NETWORK_SELECTED,
+		Height:       height,
+		ChainData:    &BlockchainData{},
+		ChainEntries: []*BlockchainSnapshotEntry{},
+	}
+
+	if err = snapshot.ChainData.loadBlockchainInfo(reader, height); err != nil {
+		return
+	}
+	snapshot.ChainData.ConsecutiveSelfForged = 0
+
+	start := uint64(0)
+	if height > config.DIFFICULTY_BLOCK_WINDOW {
+		start = height - config.DIFFICULTY_BLOCK_WINDOW
+	}
+
+	keys := []string{}
+	for h := start; h <= height; h++ {
+		heightStr := strconv.FormatUint(h, 10)
+		keys = append(keys, "totalDifficulty"+heightStr, "blockchainInfo_"+heightStr)
+		if h < height {
+			keys = append(keys, "blockHash_ByHeight"+heightStr, "blockKernelHash_ByHeight"+heightStr)
+			if hash := reader.Get("blockHash_ByHeight" + heightStr); hash != nil {
+				keys = append(keys, "block_ByHash"+string(hash), "blockHeight_ByHash"+string(hash))
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if data := reader.Get(key); data != nil {
+			snapshot.ChainEntries = append(snapshot.ChainEntries, &BlockchainSnapshotEntry{[]byte(key), data})
+		}
+	}
+
+	//reverting the state to the requested height without writing anything
+	dataStorage := data_storage.NewDataStorage(reader)
+	for h := chainData.Height; h > height; h-- {
+		if err = dataStorage.ReadTransitionalChangesFromStore(strconv.FormatUint(h-1, 10)); err != nil {
+			return
+		}
+	}
+
+	if snapshot.Registrations, err = exportSnapshotHashMap(dataStorage.Regs.HashMap); err != nil {
+		return
+	}
+	if snapshot.PlainAccounts, err = exportSnapshotHashMap(dataStorage.PlainAccs.HashMap); err != nil {
+		return
+	}
+	if snapshot.PendingStakes, err = exportSnapshotHashMap(dataStorage.PendingStakes.HashMap); err != nil {
+		return
+	}
+	if snapshot.Assets, err = exportSnapshotHashMap(dataStorage.Asts.HashMap); err != nil {
+		return
+	}
+
+	snapshot.AssetsMaps = make([]*BlockchainSnapshotAsset, len(snapshot.Assets))
+	for i, entry := range snapshot.Assets {
+
+		snapshotAsset := &BlockchainSnapshotAsset{Asset: entry.Key}
+
+		accs, err := dataStorage.AccsCollection.GetMap(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		if snapshotAsset.Accounts, err = exportSnapshotHashMap(accs.HashMap); err != nil {
+			return nil, err
+		}
+
+		maxHeap, err := dataStorage.AstsFeeLiquidityCollection.GetMaxHeap(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		if snapshotAsset.FeeLiquidityHeap, err = exportSnapshotHashMap(maxHeap.HashMap); err != nil {
+			return nil, err
+		}
+		if snapshotAsset.FeeLiquidityDict, err = exportSnapshotHashMap(maxHeap.DictMap); err != nil {
+			return nil, err
+		}
+
+		snapshot.AssetsMaps[i] = snapshotAsset
+	}
+
+	return
+}
+
+func (chain *Blockchain) ExportSnapshot(height uint64, privateKey *addresses.PrivateKey, path string) (err error) {
+
+	var snapshot *BlockchainSnapshot
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		snapshot, err = chain.createSnapshot(reader, height)
+		return
+	}); err != nil {
+		return
+	}
+
+	signed := &BlockchainSnapshotSigned{
+		PublicKey: privateKey.GeneratePublicKey(),
+	}
+
+	if signed.Snapshot, err = msgpack.Marshal(snapshot); err != nil {
+		return
+	}
+	if signed.Signature, err = privateKey.Sign(cryptography.SHA3(signed.Snapshot)); err != nil {
+		return
+	}
+
+	data, err := msgpack.Marshal(signed)
+	if err != nil {
+		return
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+//the snapshot must be signed by the trusted signer. The state can not be verified against the chain, so the signer is trusted with it
+func (chain *Blockchain) ImportSnapshot(path string, signer, checkpointHash []byte) (err error) {
+
+	if len(signer) != cryptography.PublicKeySize {
+		return errors.New("Trusted snapshot signer is invalid")
+	}
+
+	gui.GUI.Info("Importing snapshot " + path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	signed := &BlockchainSnapshotSigned{}
+	if err = msgpack.Unmarshal(data, signed); err != nil {
+		return
+	}
+
+	if !bytes.Equal(signed.PublicKey, signer) {
+		return errors.New("Snapshot is not signed by the trusted signer")
+	}
+	if !crypto.VerifySignature(cryptography.SHA3(signed.Snapshot), signed.Signature, signer) {
+		return errors.New("Snapshot signature is invalid")
+	}
+
+	snapshot := &BlockchainSnapshot{}
+	if err = msgpack.Unmarshal(signed.Snapshot, snapshot); err != nil {
+		return
+	}
+
+	if checkpointHash != nil && (snapshot.ChainData == nil || !bytes.Equal(snapshot.ChainData.Hash, checkpointHash)) {
+		return errors.New("Snapshot chain hash is not matching the checkpoint hash")
+	}
+
+	gui.GUI.Info("Snapshot signed by " + base64.StdEncoding.EncodeToString(signed.PublicKey) + " at height " + strconv.FormatUint(snapshot.Height, 10))
+
+	return chain.importSnapshot(snapshot)
+}
+
+func (chain *Blockchain) importSnapshot(snapshot *BlockchainSnapshot) (err error) {
+
+	if snapshot.Version != BLOCKCHAIN_SNAPSHOT_VERSION {
+		return errors.New("Snapshot version is not supported")
+	}
+	if snapshot.Network != config.NETWORK_SELECTED {
+		return errors.New("Snapshot network is different")
+	}
+	if snapshot.ChainData == nil || snapshot.Height == 0 || snapshot.ChainData.Height != snapshot.Height {
+		return errors.New("Snapshot height is invalid")
+	}
+
+	if err = store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if writer.Exists("blockchainInfo") {
+			return errors.New("Chain store is not empty. Snapshot can be imported only in an empty store")
+		}
+
+		for _, entry := range snapshot.ChainEntries {
+			writer.Put(string(entry.Key), entry.Value)
+		}
+
+		//the header of the last block must match the chain hash
+		blockData := writer.Get("block_ByHash" + string(snapshot.ChainData.Hash))
+		if blockData == nil {
+			return errors.New("Snapshot is missing the block header")
+		}
+		blk := block.CreateEmptyBlock()
+		if err = blk.Deserialize(helpers.NewBufferReader(blockData)); err != nil {
+			return
+		}
+		if err = blk.BloomNow(); err != nil {
+			return
+		}
+		if blk.Height != snapshot.Height-1 || !bytes.Equal(blk.Bloom.Hash, snapshot.ChainData.Hash) || !bytes.Equal(blk.Bloom.KernelHash, snapshot.ChainData.KernelHash) {
+			return errors.New("Snapshot block header is not matching the chain hash")
+		}
+		if hash := writer.Get("blockHash_ByHeight" + strconv.FormatUint(blk.Height, 10)); !bytes.Equal(hash, blk.Bloom.Hash) {
+			return errors.New("Snapshot stored header hash is not matching")
+		}
+
+		dataStorage := data_storage.NewDataStorage(writer)
+
+		if err = importSnapshotHashMap(dataStorage.Regs.HashMap, snapshot.Registrations); err != nil {
+			return
+		}
+		if err = importSnapshotHashMap(dataStorage.PlainAccs.HashMap, snapshot.PlainAccounts); err != nil {
+			return
+		}
+		if err = importSnapshotHashMap(dataStorage.PendingStakes.HashMap, snapshot.PendingStakes); err != nil {
+			return
+		}
+		if err = importSnapshotHashMap(dataStorage.Asts.HashMap, snapshot.Assets); err != nil {
+			return
+		}
+
+		for _, snapshotAsset := range snapshot.AssetsMaps {
+
+			accs, err := dataStorage.AccsCollection.GetMap(snapshotAsset.Asset)
+			if err != nil {
+				return err
+			}
+			if err = importSnapshotHashMap(accs.HashMap, snapshotAsset.Accounts); err != nil {
+				return err
+			}
+
+			maxHeap, err := dataStorage.AstsFeeLiquidityCollection.GetMaxHeap(snapshotAsset.Asset)
+			if err != nil {
+				return err
+			}
+			if err = importSnapshotHashMap(maxHeap.HashMap, snapshotAsset.FeeLiquidityHeap); err != nil {
+				return err
+			}
+			if err = importSnapshotHashMap(maxHeap.DictMap, snapshotAsset.FeeLiquidityDict); err != nil {
+				return err
+			}
+		}
+
+		if err = dataStorage.CommitChanges(); err != nil {
+			return
+		}
+
+		if config.SEED_WALLET_NODES_INFO {
+			if err = saveAssetsInfo(dataStorage.Asts); err != nil {
+				return
+			}
+		}
+
+		//blocks before the snapshot have no bodies and no transitions
+		chain.savePrunedHeight(writer, snapshot.Height)
+
+		snapshot.ChainData.saveBlockchainHeight(writer)
+		if err = snapshot.ChainData.saveBlockchainInfo(writer); err != nil {
+			return
+		}
+
+		return snapshot.ChainData.saveBlockchain(writer)
+	}); err != nil {
+		return
+	}
+
+	gui.GUI.Info("Snapshot imported. Sync will continue from height " + strconv.FormatUint(snapshot.Height, 10))
+
+	return
+}
@@ -0,0 +1,138 @@
+package blockchain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"os"
+	"pandora-pay/addresses"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"path/filepath"
+	"testing"
+)
+
+//hash of the DataStorage state at the given height
+func (chain *Blockchain) testStateHash(t *testing.T, height uint64) (hash []byte) {
+	assert.NoError(t, store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		snapshot, err := chain.createSnapshot(reader, height)
+		if err != nil {
+			return err
+		}
+
+		data, err := msgpack.Marshal([]any{snapshot.Registrations, snapshot.PlainAccounts, snapshot.PendingStakes, snapshot.Assets, snapshot.AssetsMaps})
+		if err != nil {
+			return err
+		}
+
+		hash = cryptography.SHA3(data)
+		return nil
+	}))
+	return
+}
+
+func TestSnapshotExportImport(t *testing.T) {
+
+	test := createTestChain(t)
+	for i := 0; i < 5; i++ {
+		test.forgeBlock(t)
+	}
+	chainData := test.chain.GetChainData()
+	stateHash := test.chain.testStateHash(t, chainData.Height)
+
+	//the snapshot is exported from an older height
+	for i := 0; i < 3; i++ {
+		test.forgeBlock(t)
+	}
+
+	privateKey := addresses.GenerateNewPrivateKey()
+	signer := privateKey.GeneratePublicKey()
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	assert.NoError(t, test.chain.ExportSnapshot(chainData.Height, privateKey, path))
+
+	chain := createTestReplayChain(t)
+	if !assert.NoError(t, chain.ImportSnapshot(path, signer, chainData.Hash)) {
+		t.FailNow()
+	}
+	assert.NoError(t, chain.InitializeChain())
+
+	assert.Equal(t, chainData.Height, chain.GetChainData().Height)
+	assert.Equal(t, chainData.Hash, chain.GetChainData().Hash)
+	assert.Equal(t, chainData.KernelHash, chain.GetChainData().KernelHash)
+	assert.Equal(t, stateHash, chain.testStateHash(t, chainData.Height))
+
+	assert.EqualError(t, chain.ImportSnapshot(path, signer, nil), "Chain store is not empty. Snapshot can be imported only in an empty store")
+
+	//the imported chain continues forging
+	test.chain = chain
+	test.forgeBlock(t)
+	assert.Equal(t, chainData.Height+1, chain.GetChainData().Height)
+}
+
+func TestSnapshotImportTampered(t *testing.T) {
+
+	test := createTestChain(t)
+	for i := 0; i < 3; i++ {
+		test.forgeBlock(t)
+	}
+
+	privateKey := addresses.GenerateNewPrivateKey()
+	signer := privateKey.GeneratePublicKey()
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	assert.NoError(t, test.chain.ExportSnapshot(test.chain.GetChainData().Height, privateKey, path))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	signed := &BlockchainSnapshotSigned{}
+	assert.NoError(t, msgpack.Unmarshal(data, signed))
+	snapshot := &BlockchainSnapshot{}
+	assert.NoError(t, msgpack.Unmarshal(signed.Snapshot, snapshot))
+
+	writeSigned := func(signed *BlockchainSnapshotSigned) string {
+		data, err := msgpack.Marshal(signed)
+		assert.NoError(t, err)
+		tampered := filepath.Join(t.TempDir(), "tampered")
+		assert.NoError(t, os.WriteFile(tampered, data, 0644))
+		return tampered
+	}
+
+	writeSnapshot := func(snapshot *BlockchainSnapshot) string {
+		tamperedSigned := &BlockchainSnapshotSigned{PublicKey: signer}
+		tamperedSigned.Snapshot, err = msgpack.Marshal(snapshot)
+		assert.NoError(t, err)
+		tamperedSigned.Signature, err = privateKey.Sign(cryptography.SHA3(tamperedSigned.Snapshot))
+		assert.NoError(t, err)
+		return writeSigned(tamperedSigned)
+	}
+
+	chain := createTestReplayChain(t)
+
+	assert.EqualError(t, chain.ImportSnapshot(path, addresses.GenerateNewPrivateKey().GeneratePublicKey(), nil), "Snapshot is not signed by the trusted signer")
+	assert.EqualError(t, chain.ImportSnapshot(path, signer, helpers.RandomBytes(cryptography.HashSize)), "Snapshot chain hash is not matching the checkpoint hash")
+
+	tamperedBytes := &BlockchainSnapshotSigned{append([]byte{}, signed.Snapshot...), signed.PublicKey, signed.Signature}
+	tamperedBytes.Snapshot[len(tamperedBytes.Snapshot)-1] ^= 1
+	assert.EqualError(t, chain.ImportSnapshot(writeSigned(tamperedBytes), signer, nil), "Snapshot signature is invalid")
+
+	//even a snapshot signed by the trusted signer must match the chain header
+	chainHash := snapshot.ChainData.Hash
+	snapshot.ChainData.Hash = helpers.RandomBytes(cryptography.HashSize)
+	assert.EqualError(t, chain.ImportSnapshot(writeSnapshot(snapshot), signer, nil), "Snapshot is missing the block header")
+	snapshot.ChainData.Hash = chainHash
+
+	snapshot.ChainData.Height += 1
+	assert.EqualError(t, chain.ImportSnapshot(writeSnapshot(snapshot), signer, nil), "Snapshot height is invalid")
+	snapshot.ChainData.Height -= 1
+
+	//the rejected imports did not write anything
+	assert.NoError(t, store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		assert.False(t, reader.Exists("blockchainInfo"))
+		return nil
+	}))
+
+	assert.NoError(t, chain.ImportSnapshot(writeSnapshot(snapshot), signer, chainHash))
+}
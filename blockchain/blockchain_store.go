@@ -7,11 +7,14 @@ import (
 	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/indexer"
 	"pandora-pay/config"
+	"pandora-pay/gui"
 	"pandora-pay/helpers"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"strconv"
+	"time"
 )
 
 func (chain *Blockchain) OpenExistsTx(hash []byte) (exists bool, errFinal error) {
@@ -58,6 +61,71 @@ func (chain *Blockchain) LoadBlockHash(reader store_db_interface.StoreDBTransact
 	return hash, nil
 }
 
+func (chain *Blockchain) LoadPrunedHeight(reader store_db_interface.StoreDBTransactionInterface) uint64 {
+	data := reader.Get("chainPrunedHeight")
+	if data == nil {
+		return 0
+	}
+	prunedHeight, _ := binary.Uvarint(data)
+	return prunedHeight
+}
+
+func (chain *Blockchain) savePrunedHeight(writer store_db_interface.StoreDBTransactionInterface, prunedHeight uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, prunedHeight)
+	writer.Put("chainPrunedHeight", buf[:n])
+}
+
+//blocks below the pruned height have only the header, kernel hash and blockchainInfo stored
+func (chain *Blockchain) IsBlockPruned(reader store_db_interface.StoreDBTransactionInterface, blockHeight uint64) bool {
+	return blockHeight < chain.LoadPrunedHeight(reader)
+}
+
+//deletes the block bodies and the transactions older than config.PRUNE_BLOCKS
+func (chain *Blockchain) pruneBlocksComplete(writer store_db_interface.StoreDBTransactionInterface, chainHeight uint64) error {
+
+	if config.PRUNE_BLOCKS == 0 || chainHeight <= config.PRUNE_BLOCKS {
+		return nil
+	}
+
+	pruneHeight := chainHeight - config.PRUNE_BLOCKS
+	prunedHeight := chain.LoadPrunedHeight(writer)
+	if prunedHeight >= pruneHeight {
+		return nil
+	}
+
+	//avoid huge transactions when pruning is enabled on an existing chain
+	if pruneHeight-prunedHeight > config.PRUNE_BLOCKS_MAX_BATCH {
+		pruneHeight = prunedHeight + config.PRUNE_BLOCKS_MAX_BATCH
+	}
+
+	for height := prunedHeight; height < pruneHeight; height++ {
+
+		blockHeightStr := strconv.FormatUint(height, 10)
+
+		data := writer.Get("blockTxs" + blockHeightStr)
+		if data == nil {
+			continue
+		}
+
+		txHashes := [][]byte{} //32 byte
+		if err := msgpack.Unmarshal(data, &txHashes); err != nil {
+			return err
+		}
+
+		//txHash: and txBlock: are kept to be able to detect existing txs
+		for _, txHash := range txHashes {
+			writer.Delete("tx:" + string(txHash))
+		}
+
+		writer.Delete("blockTxs" + blockHeightStr)
+	}
+
+	chain.savePrunedHeight(writer, pruneHeight)
+
+	return nil
+}
+
 func (chain *Blockchain) deleteUnusedBlocksComplete(writer store_db_interface.StoreDBTransactionInterface, blockHeight uint64, dataStorage *data_storage.DataStorage) error {
 
 	blockHeightStr := strconv.FormatUint(blockHeight, 10)
@@ -81,6 +149,10 @@ func (chain *Blockchain) removeBlockComplete(writer store_db_interface.StoreDBTr
 	blockHeightStr := strconv.FormatUint(blockHeight, 10)
 	blockHeightNextStr := strconv.FormatUint(blockHeight, 10)
 
+	if chain.IsBlockPruned(writer, blockHeight) {
+		return allTransactionsChanges, errors.New("Block was pruned and it can not be removed")
+	}
+
 	if err = dataStorage.ReadTransitionalChangesFromStore(blockHeightNextStr); err != nil {
 		return
 	}
@@ -123,13 +195,68 @@ func (chain *Blockchain) removeBlockComplete(writer store_db_interface.StoreDBTr
 		}
 	}
 
+	if config.INDEXER {
+		if err = indexer.RemoveBlock(writer, blockHeight); err != nil {
+			return
+		}
+	}
+
 	return allTransactionsChangesFinal, nil
 }
 
+//removes the blocks [height, chainData.Height) reverting the DataStorage and returns the BlockchainData of the given height
+func (chain *Blockchain) removeBlocksComplete(writer store_db_interface.StoreDBTransactionInterface, chainData *BlockchainData, height uint64, removedTxHashes map[string][]byte, allTransactionsChanges []*blockchain_types.BlockchainTransactionUpdate, dataStorage *data_storage.DataStorage) (newChainData *BlockchainData, removedBlocksHeights []uint64, allTransactionsChanges2 []*blockchain_types.BlockchainTransactionUpdate, err error) {
+
+	allTransactionsChanges2 = allTransactionsChanges
+
+	if height >= chainData.Height {
+		return nil, nil, allTransactionsChanges, errors.New("Height must be lower than the chain height")
+	}
+
+	removedBlocksHeights = make([]uint64, chainData.Height-height)
+
+	index := chainData.Height - 1
+	for {
+
+		removedBlocksHeights[index-height] = index
+
+		if allTransactionsChanges2, err = chain.removeBlockComplete(writer, index, removedTxHashes, allTransactionsChanges2, dataStorage); err != nil {
+			return
+		}
+
+		if index > height {
+			index -= 1
+		} else {
+			break
+		}
+	}
+
+	if height == 0 {
+		gui.GUI.Info("chain.createGenesisBlockchainData called")
+		newChainData = chain.createGenesisBlockchainData()
+	} else {
+		newChainData = &BlockchainData{}
+		if err = newChainData.loadBlockchainInfo(writer, height); err != nil {
+			return
+		}
+	}
+
+	if err = dataStorage.CommitChanges(); err != nil {
+		return
+	}
+
+	return
+}
+
 func (chain *Blockchain) saveBlockComplete(writer store_db_interface.StoreDBTransactionInterface, blkComplete *block_complete.BlockComplete, transactionsCount uint64, removedTxHashes map[string][]byte, allTransactionsChanges []*blockchain_types.BlockchainTransactionUpdate, dataStorage *data_storage.DataStorage) ([]*blockchain_types.BlockchainTransactionUpdate, error) {
 
 	allTransactionsChanges2 := allTransactionsChanges
 
+	var start time.Time
+	if chain.benchmark != nil {
+		start = time.Now()
+	}
+
 	blockHeightStr := strconv.FormatUint(blkComplete.Block.Height, 10)
 	if err := dataStorage.WriteTransitionalChangesToStore(blockHeightStr); err != nil {
 		return allTransactionsChanges, err
@@ -139,6 +266,14 @@ func (chain *Blockchain) saveBlockComplete(writer store_db_interface.StoreDBTran
 		return allTransactionsChanges, err
 	}
 
+	if chain.benchmark != nil {
+		chain.benchmark.dataStorage += time.Since(start)
+		start = time.Now()
+		defer func() {
+			chain.benchmark.storeWrite += time.Since(start)
+		}()
+	}
+
 	writer.Put("block_ByHash"+string(blkComplete.Block.Bloom.Hash), helpers.SerializeToBytes(blkComplete.Block))
 	writer.Put("blockHash_ByHeight"+blockHeightStr, blkComplete.Block.Bloom.Hash)
 	writer.Put("blockKernelHash_ByHeight"+blockHeightStr, blkComplete.Block.Bloom.KernelHash)
@@ -190,6 +325,12 @@ func (chain *Blockchain) saveBlockComplete(writer store_db_interface.StoreDBTran
 		}
 	}
 
+	if config.INDEXER {
+		if err := indexer.SaveBlock(writer, blkComplete); err != nil {
+			return allTransactionsChanges, err
+		}
+	}
+
 	return allTransactionsChanges2, nil
 }
 
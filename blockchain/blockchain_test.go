@@ -0,0 +1,206 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"pandora-pay/address_balance_decryptor"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/blocks/block/difficulty"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/blockchain/genesis"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forging"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/mempool"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/txs_builder"
+	"pandora-pay/txs_validator"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_address"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type testChain struct {
+	chain      *Blockchain
+	txsBuilder *txs_builder.TxsBuilder
+	forger     *wallet_address.WalletAddress
+	balance    uint64 //decrypted balance of the forger
+	sender     *wallet_address.WalletAddress
+	wallet     *wallet.Wallet
+}
+
+const testSenderBalance = 1000000000
+
+func createTestStore(t *testing.T, name string) *store.Store {
+	db, err := store_db_memory.CreateStoreDBMemory(name)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return &store.Store{Name: name, Opened: true, DB: db}
+}
+
+//the mempool worker keeps reading the chain store, so the mempool of the previous chain is suspended before the stores are replaced
+var testMempool *mempool.Mempool
+
+func suspendTestMempool() {
+	if testMempool != nil {
+		testMempool.SuspendProcessingCn <- struct{}{}
+	}
+}
+
+//creates a new chain whose genesis airdrops the stake to the forger of the wallet, some funds to the sender of the wallet and registers the ring members
+func createTestChain(t *testing.T) *testChain {
+	return createTestChainWithStore(t, createTestStore(t, "blockchain"))
+}
+
+//same as createTestChain, but the chain is stored in the given store
+func createTestChainWithStore(t *testing.T, chainStore *store.Store) *testChain {
+	return createTestChainWithAirDrops(t, chainStore, nil)
+}
+
+//airDrops funds other addresses of the wallet in the genesis
+func createTestChainWithAirDrops(t *testing.T, chainStore *store.Store, airDrops func(*wallet.Wallet) []*genesis.GenesisDataAirDropType) *testChain {
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.NoError(t, err)
+
+	config_forging.FORGING_ENABLED = false //the blocks are forged by the test
+
+	suspendTestMempool()
+
+	store.StoreBlockchain = chainStore
+	store.StoreWallet = createTestStore(t, "wallet")
+	store.StoreSettings = createTestStore(t, "settings")
+	store.StoreMempool = createTestStore(t, "mempool")
+	store.StoreBalancesDecrypted = createTestStore(t, "balancesDecrypted")
+
+	txsValidator, err := txs_validator.NewTxsValidator()
+	assert.NoError(t, err)
+	addressBalanceDecryptor, err := address_balance_decryptor.NewAddressBalanceDecryptor()
+	assert.NoError(t, err)
+	mempool, err := mempool.CreateMempool(txsValidator)
+	assert.NoError(t, err)
+	testMempool = mempool
+	mempool.OnBroadcastNewTransaction = func(txs []*transaction.Transaction, justCreated, awaitPropagation bool, exceptSocketUUID advanced_connection_types.UUID, ctx context.Context) []error {
+		return make([]error, len(txs)) //there are no peers
+	}
+	forging, err := forging.CreateForging(mempool, addressBalanceDecryptor)
+	assert.NoError(t, err)
+	chain, err := CreateBlockchain(mempool, txsValidator)
+	assert.NoError(t, err)
+	wallet, err := wallet.CreateWallet(forging, mempool, addressBalanceDecryptor)
+	assert.NoError(t, err)
+
+	forger, err := wallet.AddNewAddress(true, "forger", true, false, false)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	sender, err := wallet.AddNewAddress(true, "sender", false, false, false)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	balance := 100 * config_stake.GetRequiredStake(0)
+
+	genesis.GenesisData = &genesis.GenesisDataType{
+		Hash:       helpers.RandomBytes(cryptography.HashSize),
+		KernelHash: helpers.RandomBytes(cryptography.HashSize),
+		Timestamp:  uint64(time.Now().Add(-time.Hour).Unix()),
+		Target:     helpers.DecodeHex("0000000000FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF"),
+		AirDrops:   []*genesis.GenesisDataAirDropType{{Address: forger.AddressRegistrationEncoded, Amount: balance}, {Address: sender.AddressRegistrationEncoded, Amount: testSenderBalance}},
+	}
+	if airDrops != nil {
+		genesis.GenesisData.AirDrops = append(genesis.GenesisData.AirDrops, airDrops(wallet)...)
+	}
+	for i := 0; i < 150; i++ {
+		addr, err := addresses.GenerateNewPrivateKey().GenerateAddress(true, nil, true, nil, 0, nil)
+		assert.NoError(t, err)
+		genesis.GenesisData.AirDrops = append(genesis.GenesisData.AirDrops, &genesis.GenesisDataAirDropType{Address: addr.EncodeAddr()})
+	}
+	genesis.Genesis, err = genesis.CreateNewGenesisBlock()
+	assert.NoError(t, err)
+
+	if !assert.NoError(t, chain.InitializeChain()) {
+		t.FailNow()
+	}
+
+	return &testChain{chain, txs_builder.TxsBuilderInit(wallet, mempool, txsValidator), forger, balance, sender, wallet}
+}
+
+//forges the next block like the forging workers
+func (test *testChain) forgeBlock(t *testing.T) *block_complete.BlockComplete {
+	return test.forgeBlockWithTxs(t, []*transaction.Transaction{})
+}
+
+//same as forgeBlock, but the txs are included before the forging tx
+func (test *testChain) forgeBlockWithTxs(t *testing.T, txs []*transaction.Transaction) *block_complete.BlockComplete {
+
+	chainData := test.chain.GetChainData()
+
+	var blk *block.Block
+	if chainData.Height == 0 {
+		blk, _ = genesis.CreateNewGenesisBlock()
+	} else {
+		blk = &block.Block{
+			BlockHeader:    &block.BlockHeader{Version: 0, Height: chainData.Height},
+			MerkleHash:     cryptography.SHA3([]byte{}),
+			PrevHash:       chainData.Hash,
+			PrevKernelHash: chainData.KernelHash,
+			Timestamp:      chainData.Timestamp,
+		}
+	}
+
+	uinput := append([]byte(config.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), blk.PrevKernelHash...)
+	uinput = append(uinput, config_coins.NATIVE_ASSET_FULL...)
+	uinput = append(uinput, strconv.Itoa(0)...)
+	privateKeyPoint := new(crypto.BNRed).SetBytes(test.forger.PrivateKey.Key).BigInt()
+	u := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(uinput)), privateKeyPoint)
+	blk.StakingNonce = cryptography.SHA3(u.EncodeCompressed())
+
+	var kernelHash []byte
+	for {
+		kernelHash = blk.ComputeKernelHash()
+		kernelHashStaked, err := cryptography.ComputeKernelHash(kernelHash, test.balance)
+		assert.NoError(t, err)
+		if difficulty.CheckKernelHashBig(kernelHashStaked, chainData.Target) {
+			break
+		}
+		blk.Timestamp += 1
+	}
+
+	blk.StakingAmount = generics.Max(generics.Min(new(big.Int).Div(new(big.Int).SetBytes(kernelHash), chainData.Target).Uint64()+1, test.balance), config_stake.GetRequiredStake(blk.Height))
+
+	blkComplete := &block_complete.BlockComplete{Block: blk, Txs: []*transaction.Transaction{}}
+	tx, err := test.txsBuilder.CreateForgingTransactions(blkComplete, test.forger.PublicKey, test.balance, txs)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	blkComplete.Txs = append(txs, tx)
+	blk.MerkleHash = blkComplete.MerkleHash()
+	assert.NoError(t, blkComplete.BloomAll())
+
+	_, err = test.chain.AddBlocks([]*block_complete.BlockComplete{blkComplete}, true, advanced_connection_types.UUID_ALL)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return blkComplete
+}
@@ -41,6 +41,7 @@ type BlockchainUpdates struct {
 	Registrations  *registrations.Registrations
 	BlockHeight    uint64
 	BlockHash      []byte
+	InsertedBlocks []*block_complete.BlockComplete
 }
 
 type BlockchainSolutionAnswer struct {
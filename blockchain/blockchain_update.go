@@ -86,6 +86,7 @@ func (queue *BlockchainUpdatesQueue) executeUpdate(update *BlockchainUpdate) (er
 		update.dataStorage.Regs,
 		update.newChainData.Height,
 		update.newChainData.Hash,
+		update.insertedBlocks,
 	})
 
 	chainSyncData := queue.chain.Sync.AddBlocksChanged(uint32(len(update.insertedBlocks)), true)
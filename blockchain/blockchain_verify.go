@@ -0,0 +1,275 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/merkle_tree"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/hash_map"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+type BlockchainVerifyProblem struct {
+	Height  uint64 `json:"height" msgpack:"height"`
+	Message string `json:"message" msgpack:"message"`
+}
+
+type BlockchainVerifyReport struct {
+	ChainHeight      uint64                     `json:"chainHeight" msgpack:"chainHeight"`
+	FromHeight       uint64                     `json:"fromHeight" msgpack:"fromHeight"`
+	ConsistentHeight uint64                     `json:"consistentHeight" msgpack:"consistentHeight"` //the blocks below it were found consistent
+	Problems         []*BlockchainVerifyProblem `json:"problems" msgpack:"problems"`
+}
+
+//problems of the state are reported at the chain height as they can not be attributed to a block
+func (report *BlockchainVerifyReport) addProblem(height uint64, message string) {
+	report.Problems = append(report.Problems, &BlockchainVerifyProblem{height, message})
+	if height < report.ConsistentHeight {
+		report.ConsistentHeight = height
+	}
+}
+
+//corrupted data can make the deserialization panic
+func deserializeStoredBlock(data []byte) (blk *block.Block, err error) {
+
+	defer func() {
+		if errReturned := recover(); errReturned != nil {
+			err = fmt.Errorf("%v", errReturned)
+		}
+	}()
+
+	blk = block.CreateEmptyBlock()
+	if err = blk.Deserialize(helpers.NewBufferReader(data)); err != nil {
+		return
+	}
+	err = blk.BloomNow()
+	return
+}
+
+func (chain *Blockchain) verifyBlock(reader store_db_interface.StoreDBTransactionInterface, report *BlockchainVerifyReport, height, prunedHeight uint64, prevBlk *block.Block) *block.Block {
+
+	heightStr := strconv.FormatUint(height, 10)
+
+	hash := reader.Get("blockHash_ByHeight" + heightStr)
+	if hash == nil {
+		//headers older than an imported snapshot are not stored
+		if height >= prunedHeight {
+			report.addProblem(height, "blockHash_ByHeight is missing")
+		}
+		return nil
+	}
+
+	data := reader.Get("block_ByHash" + string(hash))
+	if data == nil {
+		report.addProblem(height, "block_ByHash is missing")
+		return nil
+	}
+
+	blk, err := deserializeStoredBlock(data)
+	if err != nil {
+		report.addProblem(height, "Block can not be deserialized: "+err.Error())
+		return nil
+	}
+
+	if !bytes.Equal(blk.Bloom.Hash, hash) {
+		report.addProblem(height, "Block hash is not matching blockHash_ByHeight")
+	}
+	if blk.Height != height {
+		report.addProblem(height, fmt.Sprintf("Block height %d is not matching", blk.Height))
+	}
+	if string(reader.Get("blockHeight_ByHash"+string(hash))) != heightStr {
+		report.addProblem(height, "blockHeight_ByHash is not matching")
+	}
+	if !bytes.Equal(reader.Get("blockKernelHash_ByHeight"+heightStr), blk.Bloom.KernelHash) {
+		report.addProblem(height, "Kernel hash is not matching blockKernelHash_ByHeight")
+	}
+
+	if prevBlk != nil {
+		if !bytes.Equal(blk.PrevHash, prevBlk.Bloom.Hash) {
+			report.addProblem(height, "PrevHash is not matching the previous block")
+		}
+		if !bytes.Equal(blk.PrevKernelHash, prevBlk.Bloom.KernelHash) {
+			report.addProblem(height, "PrevKernelHash is not matching the previous block")
+		}
+	}
+
+	chainData := &BlockchainData{}
+	if err := chainData.loadBlockchainInfo(reader, height+1); err != nil {
+		report.addProblem(height, "blockchainInfo_"+strconv.FormatUint(height+1, 10)+" can not be loaded: "+err.Error())
+	} else if chainData.Height != height+1 || !bytes.Equal(chainData.Hash, blk.Bloom.Hash) || !bytes.Equal(chainData.KernelHash, blk.Bloom.KernelHash) {
+		report.addProblem(height, "blockchainInfo_"+strconv.FormatUint(height+1, 10)+" is not matching the block")
+	}
+
+	//pruned blocks have no transactions stored
+	if height < prunedHeight {
+		return blk
+	}
+
+	data = reader.Get("blockTxs" + heightStr)
+	if data == nil {
+		report.addProblem(height, "blockTxs is missing")
+		return blk
+	}
+
+	txHashes := [][]byte{} //32 byte
+	if err := msgpack.Unmarshal(data, &txHashes); err != nil {
+		report.addProblem(height, "blockTxs can not be read: "+err.Error())
+		return blk
+	}
+
+	for _, txHash := range txHashes {
+
+		txHashStr := base64.StdEncoding.EncodeToString(txHash)
+
+		if txData := reader.Get("tx:" + string(txHash)); txData == nil {
+			report.addProblem(height, "tx:"+txHashStr+" is missing")
+		} else if !bytes.Equal(cryptography.SHA3(txData), txHash) {
+			report.addProblem(height, "tx:"+txHashStr+" hash is not matching")
+		}
+
+		if !reader.Exists("txHash:" + string(txHash)) {
+			report.addProblem(height, "txHash:"+txHashStr+" is missing")
+		}
+
+		if txBlock := reader.Get("txBlock:" + string(txHash)); txBlock == nil {
+			report.addProblem(height, "txBlock:"+txHashStr+" is missing")
+		} else if txHeight, n := binary.Uvarint(txBlock); n <= 0 || txHeight != height {
+			report.addProblem(height, "txBlock:"+txHashStr+" is not matching the block height")
+		}
+	}
+
+	merkleHash := cryptography.SHA3([]byte{})
+	if len(txHashes) > 0 {
+		merkleHash = merkle_tree.MerkleRoot(txHashes)
+	}
+	if !bytes.Equal(merkleHash, blk.MerkleHash) {
+		report.addProblem(height, "Merkle root is not matching the transactions")
+	}
+
+	return blk
+}
+
+func (chain *Blockchain) verifyHashMaps(reader store_db_interface.StoreDBTransactionInterface, report *BlockchainVerifyReport) (err error) {
+
+	dataStorage := data_storage.NewDataStorage(reader)
+
+	names := []string{"registrations", "plainAccs", "pendingStakes", "assets"}
+	hashMaps := []*hash_map.HashMap{dataStorage.Regs.HashMap, dataStorage.PlainAccs.HashMap, dataStorage.PendingStakes.HashMap, dataStorage.Asts.HashMap}
+
+	if err = dataStorage.Asts.HashMap.Iterate("", false, func(key string, element hash_map.HashMapElementSerializableInterface) error {
+
+		assetStr := base64.StdEncoding.EncodeToString([]byte(key))
+
+		accs, err := dataStorage.AccsCollection.GetMap([]byte(key))
+		if err != nil {
+			return err
+		}
+		maxHeap, err := dataStorage.AstsFeeLiquidityCollection.GetMaxHeap([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		names = append(names, "accounts "+assetStr, "feeLiquidity "+assetStr, "feeLiquidityDict "+assetStr)
+		hashMaps = append(hashMaps, accs.HashMap, maxHeap.HashMap, maxHeap.DictMap)
+		return nil
+	}); err != nil {
+		return
+	}
+
+	for i, hashMap := range hashMaps {
+		var problems []string
+		if problems, err = hashMap.VerifyStore(); err != nil {
+			return
+		}
+		for _, problem := range problems {
+			report.addProblem(report.ChainHeight, names[i]+": "+problem)
+		}
+	}
+
+	return
+}
+
+//walks the stored blocks starting with fromHeight and the HashMaps of the state
+func (chain *Blockchain) VerifyChain(fromHeight uint64) (report *BlockchainVerifyReport, err error) {
+
+	if config.CONSENSUS != config.CONSENSUS_TYPE_FULL {
+		return nil, errors.New("Chain verification requires a full node")
+	}
+
+	chainData := chain.GetChainData()
+	if fromHeight > chainData.Height {
+		return nil, errors.New("From height is bigger than the chain height")
+	}
+
+	report = &BlockchainVerifyReport{
+		ChainHeight:      chainData.Height,
+		FromHeight:       fromHeight,
+		ConsistentHeight: chainData.Height,
+		Problems:         []*BlockchainVerifyProblem{},
+	}
+
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		prunedHeight := chain.LoadPrunedHeight(reader)
+
+		var blk *block.Block
+		for height := fromHeight; height < chainData.Height; height++ {
+			blk = chain.verifyBlock(reader, report, height, prunedHeight, blk)
+			if height%10000 == 0 && height > fromHeight {
+				gui.GUI.Info("Verified blocks until " + strconv.FormatUint(height, 10))
+			}
+		}
+
+		if chainData.Height > 0 && !bytes.Equal(reader.Get("blockHash_ByHeight"+strconv.FormatUint(chainData.Height-1, 10)), chainData.Hash) {
+			report.addProblem(chainData.Height-1, "Last block hash is not matching blockchainInfo")
+		}
+		if reader.Exists("blockHash_ByHeight" + strconv.FormatUint(chainData.Height, 10)) {
+			report.addProblem(chainData.Height, "Block stored above the chain height")
+		}
+
+		return chain.verifyHashMaps(reader, report)
+	}); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+//prints the problems found and optionally rolls back to the last consistent height
+func (chain *Blockchain) VerifyChainAndRepair(fromHeight uint64, rollback bool) (err error) {
+
+	gui.GUI.Info("Verifying chain from " + strconv.FormatUint(fromHeight, 10))
+
+	report, err := chain.VerifyChain(fromHeight)
+	if err != nil {
+		return
+	}
+
+	for _, problem := range report.Problems {
+		gui.GUI.Error("Chain verification", problem.Height, problem.Message)
+	}
+
+	if len(report.Problems) == 0 {
+		gui.GUI.Info("Chain verified. No problems found")
+		return
+	}
+
+	gui.GUI.Warning(fmt.Sprintf("Chain verification found %d problems. Last consistent height %d", len(report.Problems), report.ConsistentHeight))
+
+	if !rollback || report.ConsistentHeight == report.ChainHeight {
+		return
+	}
+
+	return chain.RollbackToHeight(report.ConsistentHeight)
+}
@@ -0,0 +1,121 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"math/big"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/merkle_tree"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"strconv"
+	"testing"
+)
+
+func storeVerifyTestBlock(t *testing.T, writer store_db_interface.StoreDBTransactionInterface, prevBlk *block.Block, height uint64, txs [][]byte) *block.Block {
+
+	txHashes := make([][]byte, len(txs))
+	for i, tx := range txs {
+		txHashes[i] = cryptography.SHA3(tx)
+	}
+
+	blk := block.CreateEmptyBlock()
+	blk.Height = height
+	blk.MerkleHash = cryptography.SHA3([]byte{})
+	if len(txHashes) > 0 {
+		blk.MerkleHash = merkle_tree.MerkleRoot(txHashes)
+	}
+	blk.PrevHash = cryptography.SHA3([]byte("genesis"))
+	blk.PrevKernelHash = cryptography.SHA3([]byte("genesis"))
+	if prevBlk != nil {
+		blk.PrevHash = prevBlk.Bloom.Hash
+		blk.PrevKernelHash = prevBlk.Bloom.KernelHash
+	}
+	blk.StakingAmount = 1
+	blk.StakingNonce = helpers.RandomBytes(32)
+	assert.NoError(t, blk.BloomNow())
+
+	heightStr := strconv.FormatUint(height, 10)
+	writer.Put("block_ByHash"+string(blk.Bloom.Hash), helpers.SerializeToBytes(blk))
+	writer.Put("blockHash_ByHeight"+heightStr, blk.Bloom.Hash)
+	writer.Put("blockKernelHash_ByHeight"+heightStr, blk.Bloom.KernelHash)
+	writer.Put("blockHeight_ByHash"+string(blk.Bloom.Hash), []byte(heightStr))
+
+	marshal, err := msgpack.Marshal(txHashes)
+	assert.NoError(t, err)
+	writer.Put("blockTxs"+heightStr, marshal)
+
+	for i, tx := range txs {
+		writer.Put("tx:"+string(txHashes[i]), tx)
+		writer.Put("txHash:"+string(txHashes[i]), []byte{1})
+		buf := make([]byte, binary.MaxVarintLen64)
+		n := binary.PutUvarint(buf, height)
+		writer.Put("txBlock:"+string(txHashes[i]), buf[:n])
+	}
+
+	chainData := &BlockchainData{
+		Hash:               blk.Bloom.Hash,
+		KernelHash:         blk.Bloom.KernelHash,
+		Height:             height + 1,
+		Target:             new(big.Int),
+		BigTotalDifficulty: new(big.Int),
+	}
+	assert.NoError(t, chainData.saveBlockchainInfo(writer))
+
+	return blk
+}
+
+func verifyTestBlocks(t *testing.T, db store_db_interface.StoreDBInterface, chain *Blockchain) (report *BlockchainVerifyReport) {
+	report = &BlockchainVerifyReport{ChainHeight: 2, ConsistentHeight: 2}
+	assert.NoError(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		var blk *block.Block
+		for height := uint64(0); height < 2; height++ {
+			blk = chain.verifyBlock(reader, report, height, 0, blk)
+		}
+		return nil
+	}))
+	return
+}
+
+func TestVerifyBlock(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("blockchain")
+	assert.NoError(t, err)
+
+	chain := &Blockchain{}
+
+	tx := helpers.RandomBytes(100)
+	txHash := cryptography.SHA3(tx)
+
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		blk := storeVerifyTestBlock(t, writer, nil, 0, [][]byte{tx, helpers.RandomBytes(100)})
+		storeVerifyTestBlock(t, writer, blk, 1, nil)
+		return nil
+	}))
+
+	report := verifyTestBlocks(t, db, chain)
+	assert.Empty(t, report.Problems)
+	assert.Equal(t, uint64(2), report.ConsistentHeight)
+
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Put("blockKernelHash_ByHeight1", cryptography.SHA3([]byte("kernel")))
+		return nil
+	}))
+
+	report = verifyTestBlocks(t, db, chain)
+	assert.Len(t, report.Problems, 1)
+	assert.Equal(t, uint64(1), report.ConsistentHeight)
+
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Delete("tx:" + string(txHash))
+		writer.Put("txBlock:"+string(txHash), []byte{1})
+		return nil
+	}))
+
+	report = verifyTestBlocks(t, db, chain)
+	assert.Len(t, report.Problems, 3)
+	assert.Equal(t, uint64(0), report.ConsistentHeight)
+}
@@ -0,0 +1,159 @@
+package blocks_file
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"io"
+	"os"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/config"
+	"pandora-pay/helpers"
+)
+
+const (
+	BLOCKS_FILE_VERSION         = uint64(0)
+	BLOCKS_FILE_HEADER_MAX_SIZE = uint64(1 << 30) //the header includes the snapshot
+)
+
+//the file is the msgpack header followed by the serialized blocks complete, everything prefixed by its uvarint length
+type BlocksFileHeader struct {
+	Version     uint64 `json:"version" msgpack:"version"`
+	Network     uint64 `json:"network" msgpack:"network"`
+	GenesisHash []byte `json:"genesisHash" msgpack:"genesisHash"`
+	Start       uint64 `json:"start" msgpack:"start"`
+	End         uint64 `json:"end" msgpack:"end"`               //exclusive
+	Hash        []byte `json:"hash" msgpack:"hash"`             //hash of the block End-1
+	KernelHash  []byte `json:"kernelHash" msgpack:"kernelHash"` //kernel hash of the block End-1
+	Snapshot    []byte `json:"snapshot" msgpack:"snapshot"`     //state at Start. It is empty when Start is 0
+}
+
+type BlocksFileWriter struct {
+	file   *os.File
+	writer *bufio.Writer
+}
+
+type BlocksFileReader struct {
+	Header *BlocksFileHeader
+	file   *os.File
+	reader *bufio.Reader
+	size   uint64 //no entry can be longer than the file
+}
+
+func (fileWriter *BlocksFileWriter) write(data []byte) (err error) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, uint64(len(data)))
+	if _, err = fileWriter.writer.Write(buf[:n]); err != nil {
+		return
+	}
+	_, err = fileWriter.writer.Write(data)
+	return
+}
+
+//data is the serialized block complete
+func (fileWriter *BlocksFileWriter) WriteBlock(data []byte) error {
+	return fileWriter.write(data)
+}
+
+func (fileWriter *BlocksFileWriter) Close() (err error) {
+	if err = fileWriter.writer.Flush(); err != nil {
+		fileWriter.file.Close()
+		return
+	}
+	return fileWriter.file.Close()
+}
+
+func (fileReader *BlocksFileReader) read(maxSize uint64) (data []byte, err error) {
+
+	length, err := binary.ReadUvarint(fileReader.reader)
+	if err != nil {
+		return
+	}
+	if length > maxSize || length > fileReader.size {
+		return nil, errors.New("Blocks file entry exceeds max size")
+	}
+
+	data = make([]byte, length)
+	if _, err = io.ReadFull(fileReader.reader, data); err != nil {
+		return nil, errors.New("Blocks file is truncated")
+	}
+	return
+}
+
+//returns io.EOF after the last block
+func (fileReader *BlocksFileReader) ReadBlock() (blkComplete *block_complete.BlockComplete, err error) {
+
+	data, err := fileReader.read(config.BLOCK_MAX_SIZE)
+	if err != nil {
+		return
+	}
+
+	blkComplete = block_complete.CreateEmptyBlockComplete()
+	if err = blkComplete.Deserialize(helpers.NewBufferReader(data)); err != nil {
+		return nil, err
+	}
+	if err = blkComplete.BloomAll(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+func (fileReader *BlocksFileReader) Close() error {
+	return fileReader.file.Close()
+}
+
+func CreateBlocksFileWriter(path string, header *BlocksFileHeader) (*BlocksFileWriter, error) {
+
+	data, err := msgpack.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileWriter := &BlocksFileWriter{file, bufio.NewWriter(file)}
+	if err = fileWriter.write(data); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return fileWriter, nil
+}
+
+func OpenBlocksFileReader(path string) (*BlocksFileReader, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	fileReader := &BlocksFileReader{&BlocksFileHeader{}, file, bufio.NewReader(file), uint64(info.Size())}
+
+	data, err := fileReader.read(BLOCKS_FILE_HEADER_MAX_SIZE)
+	if err == nil {
+		err = msgpack.Unmarshal(data, fileReader.Header)
+	}
+	if err == nil && fileReader.Header.Version != BLOCKS_FILE_VERSION {
+		err = errors.New("Blocks file version is not supported")
+	}
+	if err == nil && fileReader.Header.Start >= fileReader.Header.End {
+		err = errors.New("Blocks file range is invalid")
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return fileReader, nil
+}
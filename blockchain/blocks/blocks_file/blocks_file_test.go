@@ -0,0 +1,87 @@
+package blocks_file
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"path/filepath"
+	"testing"
+)
+
+func TestBlocksFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "blocks")
+
+	header := &BlocksFileHeader{
+		Version:     BLOCKS_FILE_VERSION,
+		GenesisHash: cryptography.SHA3([]byte("genesis")),
+		Start:       5,
+		End:         7,
+		Hash:        cryptography.SHA3([]byte("hash")),
+		KernelHash:  cryptography.SHA3([]byte("kernelHash")),
+	}
+
+	blocks := make([]*block_complete.BlockComplete, 2)
+	for i := range blocks {
+		blocks[i] = block_complete.CreateEmptyBlockComplete()
+		blocks[i].Height = header.Start + uint64(i)
+		blocks[i].Block.MerkleHash = cryptography.SHA3([]byte{})
+		blocks[i].PrevHash = cryptography.SHA3([]byte("PrevHash"))
+		blocks[i].PrevKernelHash = cryptography.SHA3([]byte("PrevKernelHash"))
+		blocks[i].StakingAmount = 1
+		blocks[i].StakingNonce = helpers.RandomBytes(32)
+		assert.NoError(t, blocks[i].BloomAll())
+	}
+
+	fileWriter, err := CreateBlocksFileWriter(path, header)
+	assert.NoError(t, err)
+	for _, blkComplete := range blocks {
+		assert.NoError(t, fileWriter.WriteBlock(blkComplete.SerializeToBytes()))
+	}
+	assert.NoError(t, fileWriter.Close())
+
+	fileReader, err := OpenBlocksFileReader(path)
+	assert.NoError(t, err)
+	assert.Equal(t, header, fileReader.Header)
+
+	for _, blkComplete := range blocks {
+		blkComplete2, err := fileReader.ReadBlock()
+		assert.NoError(t, err)
+		assert.Equal(t, blkComplete.Bloom.Hash, blkComplete2.Bloom.Hash)
+	}
+
+	_, err = fileReader.ReadBlock()
+	assert.Equal(t, io.EOF, err)
+	assert.NoError(t, fileReader.Close())
+
+	//a truncated file must not be accepted
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, data[:len(data)-10], 0644))
+
+	fileReader, err = OpenBlocksFileReader(path)
+	assert.NoError(t, err)
+	_, err = fileReader.ReadBlock()
+	assert.NoError(t, err)
+	_, err = fileReader.ReadBlock()
+	assert.Error(t, err)
+	assert.NoError(t, fileReader.Close())
+
+	//a corrupted length must not allocate more than the file
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, 1<<40)
+	assert.NoError(t, os.WriteFile(path, append(buf[:n], data...), 0644))
+
+	_, err = OpenBlocksFileReader(path)
+	assert.EqualError(t, err, "Blocks file entry exceeds max size")
+
+	n = binary.PutUvarint(buf, uint64(len(data)+n+1))
+	assert.NoError(t, os.WriteFile(path, append(buf[:n], data...), 0644))
+
+	_, err = OpenBlocksFileReader(path)
+	assert.EqualError(t, err, "Blocks file entry exceeds max size")
+}
@@ -19,6 +19,11 @@ var regexAssetName = regexp.MustCompile("^([a-zA-Z0-9]+ )+[a-zA-Z0-9]+$|^[a-zA-Z
 var regexAssetTicker = regexp.MustCompile("^[A-Z0-9]+$") // only lowercase ascii is allowed. No space allowed
 var regexAssetDescription = regexp.MustCompile("[\\w|\\W]+")
 
+const (
+	ASSET_VERSION_SIMPLE       = uint64(0)
+	ASSET_VERSION_PAUSE_FREEZE = uint64(1) //serializes the Paused and Frozen states
+)
+
 type Asset struct {
 	hash_map.HashMapElementSerializableInterface `json:"-" msgpack:"-"`
 	PublicKeyHash                                []byte `json:"-" msgpack:"-"` //hashmap key
@@ -34,6 +39,8 @@ type Asset struct {
 	DecimalSeparator                             byte   `json:"decimalSeparator,omitempty" msgpack:"decimalSeparator,omitempty"`
 	MaxSupply                                    uint64 `json:"maxSupply,omitempty" msgpack:"maxSupply,omitempty"`
 	Supply                                       uint64 `json:"supply,omitempty" msgpack:"supply,omitempty"`
+	Paused                                       bool   `json:"paused,omitempty" msgpack:"paused,omitempty"`                   //transfers are rejected. Requires ASSET_VERSION_PAUSE_FREEZE
+	Frozen                                       bool   `json:"frozen,omitempty" msgpack:"frozen,omitempty"`                   //supply can not be changed anymore. Requires ASSET_VERSION_PAUSE_FREEZE
 	UpdatePublicKey                              []byte `json:"updatePublicKey,omitempty" msgpack:"updatePublicKey,omitempty"` //33 byte
 	SupplyPublicKey                              []byte `json:"supplyPublicKey,omitempty" msgpack:"supplyPublicKey,omitempty"` //33 byte
 	Name                                         string `json:"name" msgpack:"name"`
@@ -63,6 +70,12 @@ func (asset *Asset) GetIndex() uint64 {
 }
 
 func (asset *Asset) Validate() error {
+	if asset.Version > ASSET_VERSION_PAUSE_FREEZE {
+		return errors.New("asset version is invalid")
+	}
+	if asset.Version == ASSET_VERSION_SIMPLE && (asset.Paused || asset.Frozen) {
+		return errors.New("asset version can not be paused or frozen")
+	}
 	if asset.DecimalSeparator > config_assets.ASSETS_DECIMAL_SEPARATOR_MAX_BYTE {
 		return errors.New("asset decimal separator is invalid")
 	}
@@ -154,6 +167,10 @@ func (asset *Asset) AddSupply(sign bool, amount uint64) error {
 		return errors.New("BURN PUBLIC KEY")
 	}
 
+	if asset.Frozen {
+		return errors.New("Supply is frozen")
+	}
+
 	if sign {
 		if !asset.CanMint {
 			return errors.New("Can't mint")
@@ -173,6 +190,38 @@ func (asset *Asset) AddSupply(sign bool, amount uint64) error {
 	return helpers.SafeUint64Sub(&asset.Supply, amount)
 }
 
+func (asset *Asset) SetPaused(paused bool) error {
+	if !asset.CanPause {
+		return errors.New("Can't pause")
+	}
+	if asset.Paused == paused {
+		return errors.New("Asset paused state is already set")
+	}
+	asset.Paused = paused
+	asset.upgradeVersion()
+	return nil
+}
+
+//freezing is permanent
+func (asset *Asset) FreezeSupply() error {
+	if !asset.CanFreeze {
+		return errors.New("Can't freeze")
+	}
+	if asset.Frozen {
+		return errors.New("Supply is already frozen")
+	}
+	asset.Frozen = true
+	asset.upgradeVersion()
+	return nil
+}
+
+//the old assets are upgraded the first time they are paused or frozen, so their serialization stays the same until then
+func (asset *Asset) upgradeVersion() {
+	if asset.Version < ASSET_VERSION_PAUSE_FREEZE {
+		asset.Version = ASSET_VERSION_PAUSE_FREEZE
+	}
+}
+
 func (asset *Asset) Serialize(w *helpers.BufferWriter) {
 
 	w.WriteUvarint(asset.Version)
@@ -188,6 +237,10 @@ func (asset *Asset) Serialize(w *helpers.BufferWriter) {
 
 	w.WriteUvarint(asset.MaxSupply)
 	w.WriteUvarint(asset.Supply)
+	if asset.Version >= ASSET_VERSION_PAUSE_FREEZE {
+		w.WriteBool(asset.Paused)
+		w.WriteBool(asset.Frozen)
+	}
 
 	w.Write(asset.UpdatePublicKey)
 	w.Write(asset.SupplyPublicKey)
@@ -241,6 +294,14 @@ func (asset *Asset) Deserialize(r *helpers.BufferReader) (err error) {
 	if asset.Supply, err = r.ReadUvarint(); err != nil {
 		return
 	}
+	if asset.Version >= ASSET_VERSION_PAUSE_FREEZE {
+		if asset.Paused, err = r.ReadBool(); err != nil {
+			return
+		}
+		if asset.Frozen, err = r.ReadBool(); err != nil {
+			return
+		}
+	}
 	if asset.UpdatePublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
 		return
 	}
@@ -0,0 +1,79 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"testing"
+)
+
+func createTestAsset() *Asset {
+	ast := NewAsset(helpers.RandomBytes(cryptography.PublicKeyHashSize), 0)
+	ast.CanPause = true
+	ast.CanFreeze = true
+	ast.CanMint = true
+	ast.MaxSupply = 1000
+	ast.UpdatePublicKey = helpers.RandomBytes(cryptography.PublicKeySize)
+	ast.SupplyPublicKey = helpers.RandomBytes(cryptography.PublicKeySize)
+	ast.Name = "TEST"
+	ast.Ticker = "TEST"
+	return ast
+}
+
+func deserializeTestAsset(t *testing.T, data []byte) *Asset {
+	ast := NewAsset(helpers.RandomBytes(cryptography.PublicKeyHashSize), 0)
+	assert.NoError(t, ast.Deserialize(helpers.NewBufferReader(data)))
+	return ast
+}
+
+func TestAssetSerializeVersion(t *testing.T) {
+
+	ast := createTestAsset()
+
+	//the version 0 encoding is the one used before pausing and freezing were added
+	data := helpers.SerializeToBytes(ast)
+	ast.Version = ASSET_VERSION_PAUSE_FREEZE
+	assert.Equal(t, len(data)+2, len(helpers.SerializeToBytes(ast)))
+	ast.Version = ASSET_VERSION_SIMPLE
+
+	ast2 := deserializeTestAsset(t, data)
+	assert.Equal(t, ASSET_VERSION_SIMPLE, ast2.Version)
+	assert.Equal(t, data, helpers.SerializeToBytes(ast2))
+
+	assert.NoError(t, ast.SetPaused(true))
+	assert.NoError(t, ast.FreezeSupply())
+	assert.Equal(t, ASSET_VERSION_PAUSE_FREEZE, ast.Version)
+
+	ast2 = deserializeTestAsset(t, helpers.SerializeToBytes(ast))
+	assert.Equal(t, ASSET_VERSION_PAUSE_FREEZE, ast2.Version)
+	assert.True(t, ast2.Paused)
+	assert.True(t, ast2.Frozen)
+
+	ast2.Version = ASSET_VERSION_SIMPLE
+	assert.EqualError(t, ast2.Validate(), "asset version can not be paused or frozen")
+	ast2.Version = ASSET_VERSION_PAUSE_FREEZE + 1
+	assert.EqualError(t, ast2.Validate(), "asset version is invalid")
+}
+
+func TestAssetPauseFreeze(t *testing.T) {
+
+	ast := createTestAsset()
+
+	assert.EqualError(t, ast.SetPaused(false), "Asset paused state is already set")
+	assert.NoError(t, ast.SetPaused(true))
+	assert.EqualError(t, ast.SetPaused(true), "Asset paused state is already set")
+	assert.NoError(t, ast.SetPaused(false))
+
+	assert.NoError(t, ast.AddSupply(true, 10))
+	assert.NoError(t, ast.FreezeSupply())
+	assert.EqualError(t, ast.FreezeSupply(), "Supply is already frozen")
+	assert.EqualError(t, ast.AddSupply(true, 10), "Supply is frozen")
+	assert.Equal(t, uint64(10), ast.Supply)
+
+	ast = createTestAsset()
+	ast.CanPause = false
+	ast.CanFreeze = false
+	assert.EqualError(t, ast.SetPaused(true), "Can't pause")
+	assert.EqualError(t, ast.FreezeSupply(), "Can't freeze")
+	assert.Equal(t, ASSET_VERSION_SIMPLE, ast.Version)
+}
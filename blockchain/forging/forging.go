@@ -13,6 +13,7 @@ import (
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/mempool"
 	"pandora-pay/recovery"
+	"sync/atomic"
 )
 
 type Forging struct {
@@ -90,6 +91,14 @@ func (forging *Forging) StopForging() bool {
 	return false
 }
 
+//returns the hashes per second of all forging workers
+func (forging *Forging) GetHashrate() uint64 {
+	if forging.forgingThread == nil || !forging.started.IsSet() {
+		return 0
+	}
+	return atomic.LoadUint64(&forging.forgingThread.hashrate)
+}
+
 func (forging *Forging) Close() {
 	forging.StopForging()
 }
@@ -29,6 +29,7 @@ type ForgingThread struct {
 	workersDestroyedCn        chan struct{}
 	lastPrevKernelHash        *generics.Value[[]byte]
 	createForgingTransactions func(*block_complete.BlockComplete, []byte, uint64, []*transaction.Transaction) (*transaction.Transaction, error)
+	hashrate                  uint64 //use atomic
 }
 
 func (thread *ForgingThread) stopForging() {
@@ -53,10 +54,13 @@ func (thread *ForgingThread) startForging() {
 		for {
 
 			s := ""
+			total := uint64(0)
 			for i := 0; i < thread.threads; i++ {
 				hashesPerSecond := atomic.SwapUint32(&thread.workers[i].hashes, 0)
 				s += strconv.FormatUint(uint64(hashesPerSecond), 10) + " "
+				total += uint64(hashesPerSecond)
 			}
+			atomic.StoreUint64(&thread.hashrate, total)
 			gui.GUI.InfoUpdate("Hashes/s", s)
 
 			time.Sleep(time.Second)
@@ -157,5 +161,6 @@ func createForgingThread(threads int, createForgingTransactions func(*block_comp
 		make(chan struct{}),
 		&generics.Value[[]byte]{},
 		createForgingTransactions,
+		0,
 	}
 }
@@ -0,0 +1,156 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+type IndexerDirection byte
+
+const (
+	INDEXER_DIRECTION_OUT IndexerDirection = iota //the key is in the sender side of the ring or it signed the simple tx
+	INDEXER_DIRECTION_IN                          //the key is in the recipient side of the ring
+)
+
+type IndexerTx struct {
+	TxHash        []byte                                              `json:"txHash" msgpack:"txHash"`
+	BlockHeight   uint64                                              `json:"blockHeight" msgpack:"blockHeight"`
+	Version       transaction_type.TransactionVersion                 `json:"version" msgpack:"version"`
+	PayloadIndex  byte                                                `json:"payloadIndex" msgpack:"payloadIndex"`
+	Asset         []byte                                              `json:"asset" msgpack:"asset"`
+	PayloadScript transaction_zether_payload_script.PayloadScriptType `json:"payloadScript" msgpack:"payloadScript"` //only for zether txs
+	Direction     IndexerDirection                                    `json:"direction" msgpack:"direction"`
+}
+
+//keys are sorted by height, allowing iterating by height range
+func getKeyPrefix(publicKey []byte) string {
+	return "indexerTx:" + string(publicKey) + ":"
+}
+
+func getKeyHeight(publicKey []byte, blockHeight uint64) string {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, blockHeight)
+	return getKeyPrefix(publicKey) + string(buf)
+}
+
+func getKey(publicKey []byte, blockHeight uint64, txHash []byte, payloadIndex byte) string {
+	return getKeyHeight(publicKey, blockHeight) + string(txHash) + string([]byte{payloadIndex})
+}
+
+func getTxEntries(tx *transaction.Transaction, blockHeight uint64) (publicKeys [][]byte, entries []*IndexerTx) {
+
+	switch tx.Version {
+	case transaction_type.TX_SIMPLE:
+		txBase := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+		publicKeys = append(publicKeys, txBase.Vin.PublicKey)
+		entries = append(entries, &IndexerTx{tx.Bloom.Hash, blockHeight, tx.Version, 0, config_coins.NATIVE_ASSET_FULL, 0, INDEXER_DIRECTION_OUT})
+	case transaction_type.TX_ZETHER:
+		txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+		for t, payload := range txBase.Payloads {
+			for i, publicKey := range txBase.Bloom.PublicKeyLists[t] {
+				direction := INDEXER_DIRECTION_IN
+				if (i%2 == 0) == payload.Parity {
+					direction = INDEXER_DIRECTION_OUT
+				}
+				publicKeys = append(publicKeys, publicKey)
+				entries = append(entries, &IndexerTx{tx.Bloom.Hash, blockHeight, tx.Version, byte(t), payload.Asset, payload.PayloadScript, direction})
+			}
+		}
+	}
+
+	return
+}
+
+func saveBlockEntries(writer store_db_interface.StoreDBTransactionInterface, blockHeight uint64, publicKeys [][]byte, entries []*IndexerTx) (err error) {
+
+	keys := make([][]byte, len(entries))
+	for i, entry := range entries {
+
+		var data []byte
+		if data, err = msgpack.Marshal(entry); err != nil {
+			return
+		}
+
+		key := getKey(publicKeys[i], entry.BlockHeight, entry.TxHash, entry.PayloadIndex)
+		writer.Put(key, data)
+		keys[i] = []byte(key)
+	}
+
+	var data []byte
+	if data, err = msgpack.Marshal(keys); err != nil {
+		return
+	}
+
+	writer.Put("indexerBlock:"+strconv.FormatUint(blockHeight, 10), data)
+
+	return
+}
+
+//the index is complete only when it was enabled before the first block. It is marked as incomplete when the node runs without it
+func Init(writer store_db_interface.StoreDBTransactionInterface, chainHeight uint64, enabled bool) error {
+
+	if !enabled {
+		if writer.Exists("indexerEnabled") {
+			writer.Delete("indexerEnabled")
+		}
+		return nil
+	}
+
+	if writer.Exists("indexerEnabled") {
+		return nil
+	}
+	if chainHeight > 0 {
+		return errors.New("Indexer can be enabled only on an empty chain store as the stored blocks were not indexed")
+	}
+
+	writer.Put("indexerEnabled", []byte{1})
+	return nil
+}
+
+func SaveBlock(writer store_db_interface.StoreDBTransactionInterface, blkComplete *block_complete.BlockComplete) error {
+
+	publicKeys := make([][]byte, 0)
+	entries := make([]*IndexerTx, 0)
+
+	for _, tx := range blkComplete.Txs {
+		txPublicKeys, txEntries := getTxEntries(tx, blkComplete.Height)
+		publicKeys = append(publicKeys, txPublicKeys...)
+		entries = append(entries, txEntries...)
+	}
+
+	return saveBlockEntries(writer, blkComplete.Height, publicKeys, entries)
+}
+
+//removes all entries indexed by the block. Used when the block is removed by a reorg
+func RemoveBlock(writer store_db_interface.StoreDBTransactionInterface, blockHeight uint64) (err error) {
+
+	blockHeightStr := strconv.FormatUint(blockHeight, 10)
+
+	data := writer.Get("indexerBlock:" + blockHeightStr)
+	if data == nil {
+		return
+	}
+
+	keys := make([][]byte, 0)
+	if err = msgpack.Unmarshal(data, &keys); err != nil {
+		return
+	}
+
+	for _, key := range keys {
+		writer.Delete(string(key))
+	}
+
+	writer.Delete("indexerBlock:" + blockHeightStr)
+
+	return
+}
@@ -0,0 +1,105 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"math"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type IndexerHistoryFilter struct {
+	StartHeight uint64
+	EndHeight   uint64 //0 means no limit
+	Asset       []byte //nil means all assets
+	Scripts     []transaction_zether_payload_script.PayloadScriptType
+	Reverse     bool
+	Next        []byte //key returned by a previous call to continue the pagination
+	Limit       int
+}
+
+var errStopIteration = errors.New("Stop iteration")
+
+func (filter *IndexerHistoryFilter) matches(entry *IndexerTx) bool {
+
+	if len(filter.Asset) > 0 && !bytes.Equal(filter.Asset, entry.Asset) {
+		return false
+	}
+
+	if len(filter.Scripts) > 0 {
+		if entry.Version != transaction_type.TX_ZETHER {
+			return false
+		}
+		for _, script := range filter.Scripts {
+			if script == entry.PayloadScript {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+//returns the indexed txs of the public key and the key from where the next page starts
+func GetHistory(reader store_db_interface.StoreDBTransactionInterface, publicKey []byte, filter *IndexerHistoryFilter) (out []*IndexerTx, next []byte, err error) {
+
+	prefix := getKeyPrefix(publicKey)
+
+	endHeight := filter.EndHeight
+	if endHeight == 0 {
+		endHeight = math.MaxUint64
+	}
+	if filter.StartHeight > endHeight {
+		return nil, nil, errors.New("Invalid height range")
+	}
+
+	start := ""
+	if filter.Next != nil {
+		if !bytes.HasPrefix(filter.Next, []byte(prefix)) {
+			return nil, nil, errors.New("Invalid next")
+		}
+		start = string(filter.Next)
+	} else if !filter.Reverse {
+		start = getKeyHeight(publicKey, filter.StartHeight)
+	} else if endHeight != math.MaxUint64 {
+		start = string(store_db_interface.PrefixUpperBound([]byte(getKeyHeight(publicKey, endHeight))))
+	}
+
+	out = make([]*IndexerTx, 0)
+
+	if err = reader.IteratePrefix(prefix, start, filter.Reverse, func(key string, value []byte) error {
+
+		height := binary.BigEndian.Uint64([]byte(key[len(prefix) : len(prefix)+8]))
+		if height < filter.StartHeight || height > endHeight {
+			if filter.Reverse == (height < filter.StartHeight) {
+				return errStopIteration
+			}
+			return nil
+		}
+
+		entry := &IndexerTx{}
+		if err := msgpack.Unmarshal(value, entry); err != nil {
+			return err
+		}
+
+		if !filter.matches(entry) {
+			return nil
+		}
+
+		if len(out) == filter.Limit {
+			next = []byte(key)
+			return errStopIteration
+		}
+
+		out = append(out, entry)
+		return nil
+	}); err != nil && err != errStopIteration {
+		return nil, nil, err
+	}
+
+	return out, next, nil
+}
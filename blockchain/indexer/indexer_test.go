@@ -0,0 +1,114 @@
+package indexer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestIndexerHistory(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("indexer")
+	assert.Nil(t, err)
+
+	publicKey := []byte{1, 2, 3}
+	other := []byte{4, 5, 6}
+
+	assetA, assetB := []byte{10}, []byte{11}
+
+	//heights 1..6, even heights use assetB and staking
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		for height := uint64(1); height <= 6; height++ {
+			asset, script := assetA, transaction_zether_payload_script.SCRIPT_TRANSFER
+			if height%2 == 0 {
+				asset, script = assetB, transaction_zether_payload_script.SCRIPT_STAKING
+			}
+			entries := []*IndexerTx{
+				{[]byte{byte(height)}, height, transaction_type.TX_ZETHER, 0, asset, script, INDEXER_DIRECTION_OUT},
+				{[]byte{byte(height)}, height, transaction_type.TX_ZETHER, 0, asset, script, INDEXER_DIRECTION_IN},
+			}
+			if err := saveBlockEntries(writer, height, [][]byte{publicKey, other}, entries); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	heights := func(txs []*IndexerTx) []uint64 {
+		out := make([]uint64, len(txs))
+		for i, tx := range txs {
+			out[i] = tx.BlockHeight
+		}
+		return out
+	}
+
+	get := func(filter *IndexerHistoryFilter) (txs []*IndexerTx, next []byte) {
+		assert.Nil(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+			txs, next, err = GetHistory(reader, publicKey, filter)
+			return
+		}))
+		return
+	}
+
+	txs, next := get(&IndexerHistoryFilter{StartHeight: 2, EndHeight: 5, Limit: 2})
+	assert.Equal(t, []uint64{2, 3}, heights(txs))
+	assert.NotNil(t, next)
+	assert.Equal(t, INDEXER_DIRECTION_OUT, txs[0].Direction)
+
+	txs, next = get(&IndexerHistoryFilter{StartHeight: 2, EndHeight: 5, Next: next, Limit: 2})
+	assert.Equal(t, []uint64{4, 5}, heights(txs))
+	assert.Nil(t, next)
+
+	txs, next = get(&IndexerHistoryFilter{EndHeight: 5, Reverse: true, Limit: 3})
+	assert.Equal(t, []uint64{5, 4, 3}, heights(txs))
+
+	txs, next = get(&IndexerHistoryFilter{Reverse: true, Next: next, Limit: 3})
+	assert.Equal(t, []uint64{2, 1}, heights(txs))
+	assert.Nil(t, next)
+
+	txs, _ = get(&IndexerHistoryFilter{Asset: assetB, Limit: 10})
+	assert.Equal(t, []uint64{2, 4, 6}, heights(txs))
+
+	//an empty asset from JSON means all assets
+	txs, _ = get(&IndexerHistoryFilter{Asset: []byte{}, Limit: 10})
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6}, heights(txs))
+
+	txs, _ = get(&IndexerHistoryFilter{Scripts: []transaction_zether_payload_script.PayloadScriptType{transaction_zether_payload_script.SCRIPT_TRANSFER}, Limit: 10})
+	assert.Equal(t, []uint64{1, 3, 5}, heights(txs))
+
+	//reorg
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		for height := uint64(6); height >= 5; height-- {
+			if err := RemoveBlock(writer, height); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	txs, _ = get(&IndexerHistoryFilter{Limit: 10})
+	assert.Equal(t, []uint64{1, 2, 3, 4}, heights(txs))
+}
+
+func TestIndexerInit(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("indexer")
+	assert.Nil(t, err)
+
+	init := func(chainHeight uint64, enabled bool) error {
+		return db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+			return Init(writer, chainHeight, enabled)
+		})
+	}
+
+	assert.Error(t, init(10, true))
+	assert.Nil(t, init(0, true))
+	assert.Nil(t, init(10, true))
+
+	//the blocks added without the indexer are missing from the index
+	assert.Nil(t, init(10, false))
+	assert.Error(t, init(12, true))
+}
@@ -95,6 +95,36 @@ type json_Only_TransactionZetherPayloadExtraPlainAccountFund struct {
 	PlainAccountPublicKey []byte `json:"plainAccountPublicKey"  msgpack:"plainAccountPublicKey"`
 }
 
+type json_Only_TransactionZetherPayloadExtraAssetPause struct {
+	AssetId              []byte `json:"assetId"  msgpack:"assetId"`
+	Paused               bool   `json:"paused"  msgpack:"paused"`
+	AssetUpdatePublicKey []byte `json:"assetUpdatePublicKey"  msgpack:"assetUpdatePublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
+type json_Only_TransactionZetherPayloadExtraAssetFreezeSupply struct {
+	AssetId              []byte `json:"assetId"  msgpack:"assetId"`
+	AssetUpdatePublicKey []byte `json:"assetUpdatePublicKey"  msgpack:"assetUpdatePublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
+type json_Only_TransactionZetherPayloadExtraAssetSupplyDecrease struct {
+	AssetSupplyPublicKey []byte `json:"assetSupplyPublicKey"  msgpack:"assetSupplyPublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
+type json_Only_TransactionZetherPayloadExtraAssetUpdate struct {
+	AssetId              []byte `json:"assetId"  msgpack:"assetId"`
+	UpdateInfo           bool   `json:"updateInfo"  msgpack:"updateInfo"`
+	Name                 string `json:"name,omitempty"  msgpack:"name,omitempty"`
+	Description          string `json:"description,omitempty"  msgpack:"description,omitempty"`
+	Data                 []byte `json:"data,omitempty"  msgpack:"data,omitempty"`
+	NewUpdatePublicKey   []byte `json:"newUpdatePublicKey,omitempty"  msgpack:"newUpdatePublicKey,omitempty"`
+	NewSupplyPublicKey   []byte `json:"newSupplyPublicKey,omitempty"  msgpack:"newSupplyPublicKey,omitempty"`
+	AssetUpdatePublicKey []byte `json:"assetUpdatePublicKey"  msgpack:"assetUpdatePublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
 type json_Only_TransactionZetherStatement struct {
 	RingSize      int      `json:"ringSize"  msgpack:"ringSize"`
 	CLn           [][]byte `json:"cLn"  msgpack:"cLn"`
@@ -241,6 +271,40 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 				extra = &json_Only_TransactionZetherPayloadExtraPlainAccountFund{
 					payloadExtra.PlainAccountPublicKey,
 				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetPause{
+					payloadExtra.AssetId,
+					payloadExtra.Paused,
+					payloadExtra.AssetUpdatePublicKey,
+					payloadExtra.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE_SUPPLY:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreezeSupply)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetFreezeSupply{
+					payloadExtra.AssetId,
+					payloadExtra.AssetUpdatePublicKey,
+					payloadExtra.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetSupplyDecrease{
+					payloadExtra.AssetSupplyPublicKey,
+					payloadExtra.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetUpdate{
+					payloadExtra.AssetId,
+					payloadExtra.UpdateInfo,
+					payloadExtra.Name,
+					payloadExtra.Description,
+					payloadExtra.Data,
+					payloadExtra.NewUpdatePublicKey,
+					payloadExtra.NewSupplyPublicKey,
+					payloadExtra.AssetUpdatePublicKey,
+					payloadExtra.AssetSignature,
+				}
 			default:
 				return nil, errors.New("Invalid zether.TxScript")
 			}
@@ -507,6 +571,56 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 					nil,
 					extraJson.PlainAccountPublicKey,
 				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetPause{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause{
+					nil,
+					extraJson.AssetId,
+					extraJson.Paused,
+					extraJson.AssetUpdatePublicKey,
+					extraJson.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE_SUPPLY:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetFreezeSupply{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreezeSupply{
+					nil,
+					extraJson.AssetId,
+					extraJson.AssetUpdatePublicKey,
+					extraJson.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetSupplyDecrease{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease{
+					nil,
+					extraJson.AssetSupplyPublicKey,
+					extraJson.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetUpdate{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate{
+					nil,
+					extraJson.AssetId,
+					extraJson.UpdateInfo,
+					extraJson.Name,
+					extraJson.Description,
+					extraJson.Data,
+					extraJson.NewUpdatePublicKey,
+					extraJson.NewSupplyPublicKey,
+					extraJson.AssetUpdatePublicKey,
+					extraJson.AssetSignature,
+				}
 			default:
 				return errors.New("Invalid Zether TxScript")
 			}
@@ -7,6 +7,7 @@ import (
 	"pandora-pay/blockchain/data_storage"
 	"pandora-pay/blockchain/data_storage/accounts"
 	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/blockchain/transactions/transaction/transaction_data"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
@@ -102,6 +103,19 @@ func (payload *TransactionZetherPayload) IncludePayload(txHash []byte, payloadIn
 	var balance *crypto.ElGamal
 
 	if !bytes.Equal(payload.Asset, config_coins.NATIVE_ASSET_FULL) {
+
+		//the mempool will also evict the txs of a paused asset
+		var ast *asset.Asset
+		if ast, err = dataStorage.Asts.GetAsset(payload.Asset); err != nil {
+			return
+		}
+		if ast == nil {
+			return errors.New("Asset was not found")
+		}
+		if ast.Paused {
+			return errors.New("Asset is paused")
+		}
+
 		if err = payload.processAssetFee(payload.Asset, payload.Statement.Fee, payload.FeeRate, payload.FeeLeadingZeros, blockHeight, dataStorage); err != nil {
 			return
 		}
@@ -263,7 +277,7 @@ func (payload *TransactionZetherPayload) Validate(payloadIndex byte) (err error)
 
 	switch payload.PayloadScript {
 	case transaction_zether_payload_script.SCRIPT_TRANSFER:
-	case transaction_zether_payload_script.SCRIPT_STAKING, transaction_zether_payload_script.SCRIPT_STAKING_REWARD, transaction_zether_payload_script.SCRIPT_SPEND, transaction_zether_payload_script.SCRIPT_ASSET_CREATE, transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE, transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND:
+	case transaction_zether_payload_script.SCRIPT_STAKING, transaction_zether_payload_script.SCRIPT_STAKING_REWARD, transaction_zether_payload_script.SCRIPT_SPEND, transaction_zether_payload_script.SCRIPT_ASSET_CREATE, transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE, transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND, transaction_zether_payload_script.SCRIPT_ASSET_PAUSE, transaction_zether_payload_script.SCRIPT_ASSET_FREEZE_SUPPLY, transaction_zether_payload_script.SCRIPT_ASSET_UPDATE, transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
 		if payload.Extra == nil {
 			return errors.New("extra is not assigned")
 		}
@@ -340,6 +354,14 @@ func (payload *TransactionZetherPayload) Deserialize(r *helpers.BufferReader) (e
 		payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraPlainAccountFund{}
 	case transaction_zether_payload_script.SCRIPT_SPEND:
 		payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend{}
+	case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+		payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause{}
+	case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE_SUPPLY:
+		payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreezeSupply{}
+	case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE:
+		payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate{}
+	case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+		payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease{}
 	default:
 		return errors.New("INVALID SCRIPT TYPE")
 	}
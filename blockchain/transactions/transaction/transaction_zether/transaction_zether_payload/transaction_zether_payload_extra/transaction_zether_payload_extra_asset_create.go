@@ -49,6 +49,9 @@ func (payloadExtra *TransactionZetherPayloadExtraAssetCreate) Validate(payloadRe
 	if payloadExtra.Asset.Supply != 0 {
 		return errors.New("AssetInfo Supply must be zero")
 	}
+	if payloadExtra.Asset.Paused || payloadExtra.Asset.Frozen {
+		return errors.New("AssetInfo can not be created paused or frozen")
+	}
 	if !bytes.Equal(payloadAsset, config_coins.NATIVE_ASSET_FULL) {
 		return errors.New("payloadAsset must be NATIVE_ASSET_FULL")
 	}
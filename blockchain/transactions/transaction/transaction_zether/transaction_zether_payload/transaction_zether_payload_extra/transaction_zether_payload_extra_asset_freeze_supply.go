@@ -0,0 +1,93 @@
+package transaction_zether_payload_extra
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_registrations"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+)
+
+type TransactionZetherPayloadExtraAssetFreezeSupply struct {
+	TransactionZetherPayloadExtraInterface
+	AssetId              []byte
+	AssetUpdatePublicKey []byte
+	AssetSignature       []byte
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetFreezeSupply) BeforeIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetFreezeSupply) AfterIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
+
+	ast, err := dataStorage.Asts.GetAsset(payloadExtra.AssetId)
+	if err != nil {
+		return
+	}
+
+	if ast == nil {
+		return errors.New("Asset was not found")
+	}
+
+	if !bytes.Equal(payloadExtra.AssetUpdatePublicKey, ast.UpdatePublicKey) {
+		return errors.New("Asset UpdatePublicKey is not matching")
+	}
+
+	if err = ast.FreezeSupply(); err != nil {
+		return
+	}
+
+	return dataStorage.Asts.Update(string(payloadExtra.AssetId), ast)
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetFreezeSupply) ComputeAllKeys(out map[string]bool) {
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetFreezeSupply) VerifyExtraSignature(hashForSignature []byte, payloadStatement *crypto.Statement) bool {
+	return crypto.VerifySignature(hashForSignature, payloadExtra.AssetSignature, payloadExtra.AssetUpdatePublicKey)
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetFreezeSupply) Validate(payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, payloadParity bool) error {
+	if !bytes.Equal(payloadAsset, config_coins.NATIVE_ASSET_FULL) {
+		return errors.New("payloadAsset must be NATIVE_ASSET_FULL")
+	}
+	if bytes.Equal(payloadExtra.AssetId, config_coins.NATIVE_ASSET_FULL) {
+		return errors.New("Native asset can not be frozen")
+	}
+	if len(payloadExtra.AssetUpdatePublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid Public Key")
+	}
+	if len(payloadExtra.AssetSignature) != cryptography.SignatureSize {
+		return errors.New("Invalid Signature")
+	}
+	return nil
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetFreezeSupply) Serialize(w *helpers.BufferWriter, inclSignature bool) {
+	w.Write(payloadExtra.AssetId)
+	w.Write(payloadExtra.AssetUpdatePublicKey)
+	if inclSignature {
+		w.Write(payloadExtra.AssetSignature)
+	}
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetFreezeSupply) Deserialize(r *helpers.BufferReader) (err error) {
+	if payloadExtra.AssetId, err = r.ReadBytes(config_coins.ASSET_LENGTH); err != nil {
+		return
+	}
+	if payloadExtra.AssetUpdatePublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+		return
+	}
+	if payloadExtra.AssetSignature, err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+		return
+	}
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetFreezeSupply) UpdateStatement(payloadStatement *crypto.Statement) error {
+	return nil
+}
@@ -0,0 +1,166 @@
+package transaction_zether_payload_extra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+type testAssetAuthority struct {
+	assetId         []byte
+	updatePublicKey []byte
+	supplyPublicKey []byte
+}
+
+//creates the asset and runs the include in a new DataStorage, reloading the asset from the store
+func testAssetIncludes(t *testing.T, ast *asset.Asset, process func(authority *testAssetAuthority, dataStorage *data_storage.DataStorage)) *asset.Asset {
+
+	db, err := store_db_memory.CreateStoreDBMemory("assets")
+	assert.NoError(t, err)
+
+	authority := &testAssetAuthority{
+		helpers.RandomBytes(cryptography.PublicKeyHashSize),
+		helpers.RandomBytes(cryptography.PublicKeySize),
+		helpers.RandomBytes(cryptography.PublicKeySize),
+	}
+
+	ast.UpdatePublicKey = authority.updatePublicKey
+	ast.SupplyPublicKey = authority.supplyPublicKey
+	ast.Name = "TEST"
+	ast.Ticker = "TEST"
+	ast.Description = "Test asset"
+
+	if !assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		dataStorage := data_storage.NewDataStorage(writer)
+		if err := dataStorage.Asts.CreateAsset(authority.assetId, ast); err != nil {
+			return err
+		}
+		return dataStorage.CommitChanges()
+	})) {
+		t.FailNow()
+	}
+
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		dataStorage := data_storage.NewDataStorage(writer)
+		process(authority, dataStorage)
+		return dataStorage.CommitChanges()
+	}))
+
+	var out *asset.Asset
+	assert.NoError(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		out, err = data_storage.NewDataStorage(reader).Asts.GetAsset(authority.assetId)
+		return
+	}))
+	return out
+}
+
+func TestAssetPauseFreezeSupply(t *testing.T) {
+
+	ast := testAssetIncludes(t, &asset.Asset{CanPause: true, CanFreeze: true}, func(authority *testAssetAuthority, dataStorage *data_storage.DataStorage) {
+
+		pause := &TransactionZetherPayloadExtraAssetPause{AssetId: authority.assetId, Paused: true, AssetUpdatePublicKey: authority.supplyPublicKey}
+		assert.EqualError(t, pause.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Asset UpdatePublicKey is not matching")
+
+		pause.AssetUpdatePublicKey = authority.updatePublicKey
+		assert.NoError(t, pause.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage))
+		assert.EqualError(t, pause.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Asset paused state is already set")
+
+		freeze := &TransactionZetherPayloadExtraAssetFreezeSupply{AssetId: authority.assetId, AssetUpdatePublicKey: authority.supplyPublicKey}
+		assert.EqualError(t, freeze.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Asset UpdatePublicKey is not matching")
+
+		freeze.AssetUpdatePublicKey = authority.updatePublicKey
+		assert.NoError(t, freeze.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage))
+		assert.EqualError(t, freeze.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Supply is already frozen")
+	})
+
+	assert.Equal(t, asset.ASSET_VERSION_PAUSE_FREEZE, ast.Version)
+	assert.True(t, ast.Paused)
+	assert.True(t, ast.Frozen)
+
+	ast = testAssetIncludes(t, &asset.Asset{}, func(authority *testAssetAuthority, dataStorage *data_storage.DataStorage) {
+		pause := &TransactionZetherPayloadExtraAssetPause{AssetId: authority.assetId, Paused: true, AssetUpdatePublicKey: authority.updatePublicKey}
+		assert.EqualError(t, pause.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Can't pause")
+		freeze := &TransactionZetherPayloadExtraAssetFreezeSupply{AssetId: authority.assetId, AssetUpdatePublicKey: authority.updatePublicKey}
+		assert.EqualError(t, freeze.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Can't freeze")
+	})
+
+	assert.Equal(t, asset.ASSET_VERSION_SIMPLE, ast.Version)
+	assert.False(t, ast.Paused)
+	assert.False(t, ast.Frozen)
+}
+
+func TestAssetUpdateAuthority(t *testing.T) {
+
+	newUpdatePublicKey := helpers.RandomBytes(cryptography.PublicKeySize)
+
+	ast := testAssetIncludes(t, &asset.Asset{CanUpgrade: true, CanChangeUpdatePublicKey: true}, func(authority *testAssetAuthority, dataStorage *data_storage.DataStorage) {
+
+		update := &TransactionZetherPayloadExtraAssetUpdate{AssetId: authority.assetId, UpdateInfo: true, Name: "CHANGED", Description: "Changed asset", NewUpdatePublicKey: newUpdatePublicKey}
+
+		//the supply authority and other keys can not update the asset
+		for _, publicKey := range [][]byte{authority.supplyPublicKey, helpers.RandomBytes(cryptography.PublicKeySize)} {
+			update.AssetUpdatePublicKey = publicKey
+			assert.EqualError(t, update.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Asset UpdatePublicKey is not matching")
+		}
+
+		update.AssetUpdatePublicKey = authority.updatePublicKey
+		assert.NoError(t, update.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage))
+
+		//the old update key lost the authority
+		update.NewUpdatePublicKey = nil
+		assert.EqualError(t, update.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Asset UpdatePublicKey is not matching")
+	})
+
+	assert.Equal(t, "CHANGED", ast.Name)
+	assert.Equal(t, newUpdatePublicKey, ast.UpdatePublicKey)
+
+	testAssetIncludes(t, &asset.Asset{}, func(authority *testAssetAuthority, dataStorage *data_storage.DataStorage) {
+		update := &TransactionZetherPayloadExtraAssetUpdate{AssetId: authority.assetId, UpdateInfo: true, Name: "CHANGED", AssetUpdatePublicKey: authority.updatePublicKey}
+		assert.EqualError(t, update.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Can't upgrade")
+		update = &TransactionZetherPayloadExtraAssetUpdate{AssetId: authority.assetId, NewSupplyPublicKey: newUpdatePublicKey, AssetUpdatePublicKey: authority.updatePublicKey}
+		assert.EqualError(t, update.AfterIncludeTxPayload(nil, nil, 0, nil, 0, nil, nil, 0, dataStorage), "Can't change Supply Public Key")
+	})
+}
+
+func TestAssetSupplyDecreaseAuthority(t *testing.T) {
+
+	ast := testAssetIncludes(t, &asset.Asset{CanBurn: true, MaxSupply: 1000, Supply: 100}, func(authority *testAssetAuthority, dataStorage *data_storage.DataStorage) {
+
+		decrease := &TransactionZetherPayloadExtraAssetSupplyDecrease{}
+
+		//the update authority and other keys can not decrease the supply
+		for _, publicKey := range [][]byte{authority.updatePublicKey, helpers.RandomBytes(cryptography.PublicKeySize)} {
+			decrease.AssetSupplyPublicKey = publicKey
+			assert.EqualError(t, decrease.AfterIncludeTxPayload(nil, nil, 0, authority.assetId, 10, nil, nil, 0, dataStorage), "Asset SupplyPublicKey is not matching")
+		}
+
+		decrease.AssetSupplyPublicKey = authority.supplyPublicKey
+		assert.NoError(t, decrease.AfterIncludeTxPayload(nil, nil, 0, authority.assetId, 10, nil, nil, 0, dataStorage))
+		assert.EqualError(t, decrease.AfterIncludeTxPayload(nil, nil, 0, authority.assetId, 100, nil, nil, 0, dataStorage), "Supply would become negative")
+	})
+
+	assert.Equal(t, uint64(90), ast.Supply)
+
+	testAssetIncludes(t, &asset.Asset{MaxSupply: 1000, Supply: 100}, func(authority *testAssetAuthority, dataStorage *data_storage.DataStorage) {
+		decrease := &TransactionZetherPayloadExtraAssetSupplyDecrease{AssetSupplyPublicKey: authority.supplyPublicKey}
+		assert.EqualError(t, decrease.AfterIncludeTxPayload(nil, nil, 0, authority.assetId, 10, nil, nil, 0, dataStorage), "Can't burn")
+	})
+
+	//the signature must be done by the supply authority
+	supplyPrivateKey := addresses.GenerateNewPrivateKey()
+	message := helpers.RandomBytes(32)
+	signature, err := supplyPrivateKey.Sign(message)
+	assert.NoError(t, err)
+
+	decrease := &TransactionZetherPayloadExtraAssetSupplyDecrease{AssetSupplyPublicKey: supplyPrivateKey.GeneratePublicKey(), AssetSignature: signature}
+	assert.True(t, decrease.VerifyExtraSignature(message, nil))
+
+	decrease.AssetSupplyPublicKey = addresses.GenerateNewPrivateKey().GeneratePublicKey()
+	assert.False(t, decrease.VerifyExtraSignature(message, nil))
+}
@@ -0,0 +1,181 @@
+package transaction_zether_payload_extra
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_registrations"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+)
+
+type TransactionZetherPayloadExtraAssetUpdate struct {
+	TransactionZetherPayloadExtraInterface
+	AssetId              []byte
+	UpdateInfo           bool //requires CanUpgrade
+	Name                 string
+	Description          string
+	Data                 []byte
+	NewUpdatePublicKey   []byte //empty means no change. Requires CanChangeUpdatePublicKey
+	NewSupplyPublicKey   []byte //empty means no change. Requires CanChangeSupplyPublicKey
+	AssetUpdatePublicKey []byte
+	AssetSignature       []byte
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) BeforeIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) AfterIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
+
+	ast, err := dataStorage.Asts.GetAsset(payloadExtra.AssetId)
+	if err != nil {
+		return
+	}
+
+	if ast == nil {
+		return errors.New("Asset was not found")
+	}
+
+	if !bytes.Equal(payloadExtra.AssetUpdatePublicKey, ast.UpdatePublicKey) {
+		return errors.New("Asset UpdatePublicKey is not matching")
+	}
+
+	if payloadExtra.UpdateInfo {
+		if !ast.CanUpgrade {
+			return errors.New("Can't upgrade")
+		}
+		ast.Name = payloadExtra.Name
+		ast.Description = payloadExtra.Description
+		ast.Data = payloadExtra.Data
+	}
+
+	if len(payloadExtra.NewUpdatePublicKey) > 0 {
+		if !ast.CanChangeUpdatePublicKey {
+			return errors.New("Can't change Update Public Key")
+		}
+		ast.UpdatePublicKey = payloadExtra.NewUpdatePublicKey
+	}
+
+	if len(payloadExtra.NewSupplyPublicKey) > 0 {
+		if !ast.CanChangeSupplyPublicKey {
+			return errors.New("Can't change Supply Public Key")
+		}
+		ast.SupplyPublicKey = payloadExtra.NewSupplyPublicKey
+	}
+
+	if err = ast.Validate(); err != nil {
+		return
+	}
+
+	return dataStorage.Asts.Update(string(payloadExtra.AssetId), ast)
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) ComputeAllKeys(out map[string]bool) {
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) VerifyExtraSignature(hashForSignature []byte, payloadStatement *crypto.Statement) bool {
+	return crypto.VerifySignature(hashForSignature, payloadExtra.AssetSignature, payloadExtra.AssetUpdatePublicKey)
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) Validate(payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, payloadParity bool) error {
+	if !bytes.Equal(payloadAsset, config_coins.NATIVE_ASSET_FULL) {
+		return errors.New("payloadAsset must be NATIVE_ASSET_FULL")
+	}
+	if bytes.Equal(payloadExtra.AssetId, config_coins.NATIVE_ASSET_FULL) {
+		return errors.New("Native asset can not be updated")
+	}
+	if !payloadExtra.UpdateInfo && len(payloadExtra.NewUpdatePublicKey) == 0 && len(payloadExtra.NewSupplyPublicKey) == 0 {
+		return errors.New("Asset Update has no changes")
+	}
+	if !payloadExtra.UpdateInfo && (len(payloadExtra.Name) > 0 || len(payloadExtra.Description) > 0 || len(payloadExtra.Data) > 0) {
+		return errors.New("Asset Info must be empty")
+	}
+	if len(payloadExtra.NewUpdatePublicKey) != 0 && len(payloadExtra.NewUpdatePublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid New Update Public Key")
+	}
+	if len(payloadExtra.NewSupplyPublicKey) != 0 && len(payloadExtra.NewSupplyPublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid New Supply Public Key")
+	}
+	if len(payloadExtra.AssetUpdatePublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid Public Key")
+	}
+	if len(payloadExtra.AssetSignature) != cryptography.SignatureSize {
+		return errors.New("Invalid Signature")
+	}
+	return nil
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) Serialize(w *helpers.BufferWriter, inclSignature bool) {
+	w.Write(payloadExtra.AssetId)
+	w.WriteBool(payloadExtra.UpdateInfo)
+	if payloadExtra.UpdateInfo {
+		w.WriteString(payloadExtra.Name)
+		w.WriteString(payloadExtra.Description)
+		w.WriteVariableBytes(payloadExtra.Data)
+	}
+	w.WriteBool(len(payloadExtra.NewUpdatePublicKey) > 0)
+	if len(payloadExtra.NewUpdatePublicKey) > 0 {
+		w.Write(payloadExtra.NewUpdatePublicKey)
+	}
+	w.WriteBool(len(payloadExtra.NewSupplyPublicKey) > 0)
+	if len(payloadExtra.NewSupplyPublicKey) > 0 {
+		w.Write(payloadExtra.NewSupplyPublicKey)
+	}
+	w.Write(payloadExtra.AssetUpdatePublicKey)
+	if inclSignature {
+		w.Write(payloadExtra.AssetSignature)
+	}
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) Deserialize(r *helpers.BufferReader) (err error) {
+	if payloadExtra.AssetId, err = r.ReadBytes(config_coins.ASSET_LENGTH); err != nil {
+		return
+	}
+	if payloadExtra.UpdateInfo, err = r.ReadBool(); err != nil {
+		return
+	}
+	if payloadExtra.UpdateInfo {
+		if payloadExtra.Name, err = r.ReadString(15); err != nil {
+			return
+		}
+		if payloadExtra.Description, err = r.ReadString(1024); err != nil {
+			return
+		}
+		if payloadExtra.Data, err = r.ReadVariableBytes(5120); err != nil {
+			return
+		}
+	}
+
+	var hasKey bool
+	if hasKey, err = r.ReadBool(); err != nil {
+		return
+	}
+	if hasKey {
+		if payloadExtra.NewUpdatePublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+	}
+	if hasKey, err = r.ReadBool(); err != nil {
+		return
+	}
+	if hasKey {
+		if payloadExtra.NewSupplyPublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+	}
+
+	if payloadExtra.AssetUpdatePublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+		return
+	}
+	if payloadExtra.AssetSignature, err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+		return
+	}
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) UpdateStatement(payloadStatement *crypto.Statement) error {
+	return nil
+}
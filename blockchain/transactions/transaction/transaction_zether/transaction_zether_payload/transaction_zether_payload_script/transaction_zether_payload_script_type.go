@@ -10,6 +10,10 @@ const (
 	SCRIPT_ASSET_CREATE
 	SCRIPT_ASSET_SUPPLY_INCREASE
 	SCRIPT_PLAIN_ACCOUNT_FUND
+	SCRIPT_ASSET_PAUSE
+	SCRIPT_ASSET_FREEZE_SUPPLY
+	SCRIPT_ASSET_UPDATE
+	SCRIPT_ASSET_SUPPLY_DECREASE
 )
 
 func (t PayloadScriptType) String() string {
@@ -28,6 +32,14 @@ func (t PayloadScriptType) String() string {
 		return "SCRIPT_ASSET_SUPPLY_INCREASE"
 	case SCRIPT_PLAIN_ACCOUNT_FUND:
 		return "SCRIPT_PLAIN_ACCOUNT_FUND"
+	case SCRIPT_ASSET_PAUSE:
+		return "SCRIPT_ASSET_PAUSE"
+	case SCRIPT_ASSET_FREEZE_SUPPLY:
+		return "SCRIPT_ASSET_FREEZE_SUPPLY"
+	case SCRIPT_ASSET_UPDATE:
+		return "SCRIPT_ASSET_UPDATE"
+	case SCRIPT_ASSET_SUPPLY_DECREASE:
+		return "SCRIPT_ASSET_SUPPLY_DECREASE"
 	default:
 		return "Unknown ScriptType"
 	}
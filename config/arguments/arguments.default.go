@@ -8,20 +8,22 @@ package arguments
 const commands = `PANDORA PAY.
 
 Usage:
-  pandorapay [--pprof] [--network=network] [--debug] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--tor-onion=onion] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--seed-wallet-nodes-info=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-site-key=args] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--auth-users=args] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--exit] [--skip-init-sync]
+  pandorapay [--config=path] [--print-config] [--pprof] [--network=network] [--debug] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--tor-onion=onion] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--seed-wallet-nodes-info=bool] [--indexer=bool] [--prune=blocks] [--import-snapshot=path] [--import-snapshot-signer=publicKey] [--import-snapshot-hash=hash] [--verify-chain=height] [--verify-chain-rollback] [--export-blocks=args] [--import-blocks=path] [--replay-blocks=path] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-site-key=args] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--auth-users=args] [--rate-limit=args] [--rate-limit-costs=args] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--exit] [--skip-init-sync]
   pandorapay -h | --help
   pandorapay -v | --version
 
 Options:
   -h --help                                          Show this screen.
   --version                                          Show version.
+  --config=path                                      Load the arguments from a YAML or TOML file using the same keys without "--". Precedence is CLI > env vars (PANDORAPAY_TCP_SERVER_PORT for --tcp-server-port) > config file > defaults.
+  --print-config                                     Print the effective configuration with the secrets masked and exit.
   --network=network                                  Select network. Accepted values: "mainnet|testnet|devnet". [default: mainnet]
   --new-devnet                                       Create a new devnet genesis.
   --run-testnet-script                               Run testnet script which will create dummy transactions in the network.
   --set-genesis=genesis                              Manually set the Genesis via a JSON. By using argument "file" it will read it via a file.
   --create-new-genesis=args                          Create a new Genesis. Useful for creating a new private testnet. Argument must be "0.stake,1.stake,2.stake"
-  --store-wallet-type=type                           Set Wallet Store Type. Accepted values: "bolt|bunt|bunt-memory|memory". [default: bolt]
-  --store-chain-type=type                            Set Chain Store Type. Accepted values: "bolt|bunt|bunt-memory|memory".  [default: bolt]
+  --store-wallet-type=type                           Set Wallet Store Type. Accepted values: "bolt|bunt|bunt-memory|memory|pebble". [default: bolt]
+  --store-chain-type=type                            Set Chain Store Type. Accepted values: "bolt|bunt|bunt-memory|memory|pebble".  [default: bolt]
   --debug                                            Debug mode enabled (print log message).
   --forging                                          Start Forging blocks.
   --node-name=name                                   Change node name.
@@ -37,6 +39,16 @@ Options:
   --tor-onion=onion                                  Define your tor onion address to be used.
   --consensus=type                                   Consensus type. Accepted values: "full|wallet|none" [default: full].
   --seed-wallet-nodes-info=bool                      Storing and serving additional info to wallet nodes. [default: true]. To enable, it requires full node
+  --indexer=bool                                     Index the transactions of every public key found in the rings. It serves the account history with height range, asset and script filters. It can be enabled only on an empty chain store. Requires full node [default: false].
+  --prune=blocks                                     Pruned node. Keeps only the bodies and transactions of the last N blocks. Requires full node and at least 60 blocks.
+  --import-snapshot=path                             Import a signed state snapshot into an empty chain store and continue the sync from its height. It requires --import-snapshot-signer.
+  --import-snapshot-signer=publicKey                 Base64 public key of the trusted signer of the snapshot.
+  --import-snapshot-hash=hash                        Optional base64 chain hash at the snapshot height used as checkpoint.
+  --verify-chain=height                              Verify the integrity of the chain store starting with the given height and report the problems found. Without a height the entire chain is verified.
+  --verify-chain-rollback                            Roll back the chain to the last consistent height found by --verify-chain.
+  --export-blocks=args                               Export the blocks into a portable file. Argument must be "from,to,path" where the block "to" is not included. When "from" is not 0, the state at "from" is also included.
+  --import-blocks=path                               Verify and add the blocks from an exported blocks file. The chain must already contain the blocks before the first block of the file.
+  --replay-blocks=path                               Replay an exported blocks file into a fresh memory chain store, check the final hash and report the timings of every block. Use --exit to stop after the replay.
   --wallet-import-secret-mnemonic=mnemonic           Import Wallet from a given Mnemonic. It will delete your existing wallet. 
   --wallet-import-secret-entropy=entropy             Import Wallet from a given Entropy. It will delete your existing wallet.
   --wallet-encrypt=args                              Encrypt wallet. Argument must be "password,difficulty".
@@ -50,6 +62,8 @@ Options:
   --delegator-require-auth=bool                      Delegator will require authentication.
   --delegates-maximum=args                           Maximum number of Delegates
   --auth-users=args                                  Credential for Authenticated Users. Arguments must be a JSON "[{'user': 'username', 'pass': 'secret'}]".
+  --rate-limit=args                                  Token bucket rate limit of the APIs. Disabled by default. Use "default" to enable it with the default limits or "connectionRate,connectionBurst,ipRate,ipBurst" in tokens per second.
+  --rate-limit-costs=args                            Cost in tokens of the API methods. Argument must be a JSON "{'block-complete': 5}".
   --light-computations                               Reduces the computations for a testnet node.
   --balance-decryptor-disable-init                   Disable first balance decryptor initialization. 
   --balance-decryptor-table-size=size                Balance Decryptor initial table size. [default: 23]
@@ -7,11 +7,26 @@ import (
 	"pandora-pay/config/globals"
 )
 
+func parseArguments(usage string, argv []string) (map[string]interface{}, error) {
+	arguments, err := docopt.Parse(usage, argv, false, config.VERSION_STRING, false, false)
+	if err != nil {
+		return nil, errors.New("Error processing arguments" + err.Error())
+	}
+	return arguments, nil
+}
+
 func InitArguments(argv []string) (err error) {
 
-	if globals.Arguments, err = docopt.Parse(commands, argv, false, config.VERSION_STRING, false, false); err != nil {
-		return errors.New("Error processing arguments" + err.Error())
+	//docopt doesn't support options with an optional value
+	for i, arg := range argv {
+		if arg == "--verify-chain" {
+			argv[i] = "--verify-chain=0"
+		}
+	}
+
+	if globals.Arguments, err = parseArguments(commands, argv); err != nil {
+		return
 	}
 
-	return
+	return applyConfig(globals.Arguments, argv)
 }
@@ -0,0 +1,185 @@
+package arguments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const configEnvPrefix = "PANDORAPAY_"
+
+var configDefaultsRegexp = regexp.MustCompile(`\[default: [^\]]*\]`)
+
+//flags which can not be set by the config file or env vars
+var configIgnoredKeys = map[string]bool{
+	"--help":         true,
+	"--version":      true,
+	"--config":       true,
+	"--print-config": true,
+}
+
+var configSecretKeys = map[string]bool{
+	"--auth-users":                    true,
+	"--hcaptcha-secret":               true,
+	"--wallet-encrypt":                true,
+	"--wallet-decrypt":                true,
+	"--wallet-import-secret-mnemonic": true,
+	"--wallet-import-secret-entropy":  true,
+}
+
+//--tcp-server-port is read from PANDORAPAY_TCP_SERVER_PORT
+func configEnvName(key string) string {
+	return configEnvPrefix + strings.ToUpper(strings.ReplaceAll(strings.TrimPrefix(key, "--"), "-", "_"))
+}
+
+func readConfigFile(path string) (values map[string]interface{}, err error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		values = make(map[string]interface{})
+		if err = yaml.Unmarshal(data, &values); err != nil {
+			return nil, errors.New("Error reading config file " + err.Error())
+		}
+	case ".toml":
+		values = make(map[string]interface{})
+		if err = toml.Unmarshal(data, &values); err != nil {
+			return nil, errors.New("Error reading config file " + err.Error())
+		}
+	default:
+		return nil, errors.New("Config file must be .yaml, .yml or .toml")
+	}
+
+	return
+}
+
+//converts a value of the config file or of an env var in the type used by docopt
+func convertConfigValue(key string, flag bool, value interface{}) (interface{}, error) {
+
+	if flag {
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be true or false", key)
+			}
+			return b, nil
+		default:
+			return nil, fmt.Errorf("%s must be true or false", key)
+		}
+	}
+
+	//an empty value would replace the default with a value matching none of the accepted values
+	switch v := value.(type) {
+	case nil:
+		return nil, fmt.Errorf("%s must have a value", key)
+	case string:
+		if v == "" {
+			return nil, fmt.Errorf("%s must have a value", key)
+		}
+		return v, nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	default:
+		//lists and objects like the --auth-users are given as JSON
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s can not be converted to JSON: %s", key, err.Error())
+		}
+		return string(data), nil
+	}
+}
+
+//precedence is CLI > env vars > config file > defaults
+func applyConfig(arguments map[string]interface{}, argv []string) (err error) {
+
+	//without the defaults, only the arguments set in the CLI have values
+	cli, err := parseArguments(configDefaultsRegexp.ReplaceAllString(commands, ""), argv)
+	if err != nil {
+		return
+	}
+
+	path, _ := cli["--config"].(string)
+	if path == "" {
+		path = os.Getenv(configEnvName("--config"))
+	}
+
+	file := map[string]interface{}{}
+	if path != "" {
+		if file, err = readConfigFile(path); err != nil {
+			return
+		}
+
+		unknown := []string{}
+		for key := range file {
+			if _, found := arguments["--"+key]; !found || configIgnoredKeys["--"+key] {
+				unknown = append(unknown, key)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return errors.New("Unknown keys in config file: " + strings.Join(unknown, ", "))
+		}
+
+		arguments["--config"] = path
+	}
+
+	for key, value := range arguments {
+
+		if !strings.HasPrefix(key, "--") || configIgnoredKeys[key] || (cli[key] != nil && cli[key] != false) {
+			continue
+		}
+
+		_, flag := value.(bool)
+
+		var newValue interface{}
+		if env, found := os.LookupEnv(configEnvName(key)); found {
+			newValue = env
+		} else if fileValue, found := file[strings.TrimPrefix(key, "--")]; found {
+			newValue = fileValue
+		} else {
+			continue
+		}
+
+		if arguments[key], err = convertConfigValue(key, flag, newValue); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+//the effective configuration as YAML with the secrets masked
+func PrintConfig(arguments map[string]interface{}) (string, error) {
+
+	values := make(map[string]interface{})
+	for key, value := range arguments {
+		if !strings.HasPrefix(key, "--") || configIgnoredKeys[key] {
+			continue
+		}
+		if configSecretKeys[key] && value != nil && value != false {
+			value = "****"
+		}
+		values[strings.TrimPrefix(key, "--")] = value
+	}
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
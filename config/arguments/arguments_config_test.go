@@ -0,0 +1,93 @@
+//go:build !wasm
+// +build !wasm
+
+package arguments
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyConfig(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("network: devnet\ntcp-server-port: 9000\ntcp-max-clients: 10\nforging: true\nauth-users:\n  - user: a\n    pass: b\n"), 0644))
+
+	t.Setenv("PANDORAPAY_TCP_MAX_CLIENTS", "20")
+
+	argv := []string{"--config=" + path, "--tcp-server-port=7000"}
+	arguments, err := parseArguments(commands, argv)
+	assert.NoError(t, err)
+	assert.NoError(t, applyConfig(arguments, argv))
+
+	assert.Equal(t, "7000", arguments["--tcp-server-port"])
+	assert.Equal(t, "20", arguments["--tcp-max-clients"])
+	assert.Equal(t, "devnet", arguments["--network"])
+	assert.Equal(t, true, arguments["--forging"])
+	assert.Equal(t, `[{"pass":"b","user":"a"}]`, arguments["--auth-users"])
+	assert.Equal(t, "bolt", arguments["--store-chain-type"])
+
+	data, err := PrintConfig(arguments)
+	assert.NoError(t, err)
+	assert.Contains(t, data, "auth-users: '****'")
+	assert.NotContains(t, data, "pass")
+
+	assert.NoError(t, os.WriteFile(path, []byte("netwrk: devnet\n"), 0644))
+	arguments, err = parseArguments(commands, argv)
+	assert.NoError(t, err)
+	assert.EqualError(t, applyConfig(arguments, argv), "Unknown keys in config file: netwrk")
+}
+
+func TestApplyConfigTOML(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(t, os.WriteFile(path, []byte(`# comment
+network = "devnet" # inline
+tcp-server-port = 9001
+debug = true
+
+[[auth-users]]
+user = "a"
+pass = "b"
+
+[[auth-users]]
+user = "c"
+pass = "d"
+`), 0644))
+
+	argv := []string{"--config=" + path}
+	arguments, err := parseArguments(commands, argv)
+	assert.NoError(t, err)
+	assert.NoError(t, applyConfig(arguments, argv))
+
+	assert.Equal(t, "devnet", arguments["--network"])
+	assert.Equal(t, "9001", arguments["--tcp-server-port"])
+	assert.Equal(t, true, arguments["--debug"])
+	assert.Equal(t, `[{"pass":"b","user":"a"},{"pass":"d","user":"c"}]`, arguments["--auth-users"])
+
+	assert.NoError(t, os.WriteFile(path, []byte("network = devnet\n"), 0644))
+	arguments, err = parseArguments(commands, argv)
+	assert.NoError(t, err)
+	assert.Error(t, applyConfig(arguments, argv))
+}
+
+func TestApplyConfigEmptyValues(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	argv := []string{"--config=" + path}
+
+	for _, data := range []string{"network:\n", "network: null\n", "network: \"\"\n"} {
+		assert.NoError(t, os.WriteFile(path, []byte(data), 0644))
+		arguments, err := parseArguments(commands, argv)
+		assert.NoError(t, err)
+		assert.EqualError(t, applyConfig(arguments, argv), "--network must have a value")
+	}
+
+	t.Setenv("PANDORAPAY_STORE_CHAIN_TYPE", "")
+	assert.NoError(t, os.WriteFile(path, []byte("network: devnet\n"), 0644))
+	arguments, err := parseArguments(commands, argv)
+	assert.NoError(t, err)
+	assert.EqualError(t, applyConfig(arguments, argv), "--store-chain-type must have a value")
+}
@@ -2,12 +2,14 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"github.com/blang/semver/v4"
 	"math/big"
 	"math/rand"
 	"pandora-pay/config/config_auth"
 	"pandora-pay/config/config_forging"
 	"pandora-pay/config/config_nodes"
+	"pandora-pay/config/config_rate_limit"
 	"pandora-pay/config/globals"
 	"runtime"
 	"strconv"
@@ -54,6 +56,7 @@ const (
 	DIFFICULTY_BLOCK_WINDOW uint64 = 10
 	FORK_MAX_UNCLE_ALLOWED  uint64 = 60
 	FORK_MAX_DOWNLOAD       uint64 = 20
+	IMPORT_BLOCKS_BATCH     uint64 = 100
 )
 
 var (
@@ -76,10 +79,17 @@ const (
 	WEBSOCKETS_INCREASE_KNOWN_NODE_SCORE_INTERVAL = 1 * time.Minute
 )
 
+const (
+	MEMPOOL_SAVE_INTERVAL = 5 * time.Minute
+)
+
 var (
 	API_MEMPOOL_MAX_TRANSACTIONS = 50
 	API_ACCOUNT_MAX_TXS          = uint64(10)
+	API_ACCOUNT_HISTORY_MAX_TXS  = 50
 	API_ASSETS_INFO_MAX_RESULTS  = 10
+	API_FEE_ESTIMATE_BLOCKS      = uint64(20)
+	API_FEE_ESTIMATE_MAX_BLOCKS  = uint64(100)
 )
 
 var (
@@ -97,12 +107,18 @@ var (
 var (
 	CONSENSUS              ConsensusType = CONSENSUS_TYPE_FULL
 	SEED_WALLET_NODES_INFO bool
+	INDEXER                bool
+	PRUNE_BLOCKS           uint64 //0 means disabled
+	PRUNE_BLOCKS_MAX_BATCH = uint64(1000)
 )
 
 var (
-	NETWORK_ADDRESS_URL_STRING      string
-	NETWORK_KNOWN_NODES_LIMIT       int32 = 5000
-	NETWORK_KNOWN_NODES_LIST_RETURN       = 100
+	NETWORK_ADDRESS_URL_STRING        string
+	NETWORK_KNOWN_NODES_LIMIT         int32 = 5000
+	NETWORK_KNOWN_NODES_LIST_RETURN         = 100
+	NETWORK_KNOWN_NODES_SELECTION           = 3 //random nodes compared to pick the best one to connect
+	NETWORK_KNOWN_NODES_SAVE_INTERVAL       = 5 * time.Minute
+	NETWORK_KNOWN_NODES_EXPIRATION          = 3 * 24 * time.Hour //nodes not seen since are removed
 )
 
 func StartConfig() {
@@ -149,12 +165,16 @@ func InitConfig() (err error) {
 	}
 
 	SEED_WALLET_NODES_INFO = false
+	INDEXER = false
 	switch globals.Arguments["--consensus"] {
 	case "full":
 		CONSENSUS = CONSENSUS_TYPE_FULL
 		if globals.Arguments["--seed-wallet-nodes-info"] == "true" {
 			SEED_WALLET_NODES_INFO = true
 		}
+		if globals.Arguments["--indexer"] == "true" {
+			INDEXER = true
+		}
 	case "wallet":
 		CONSENSUS = CONSENSUS_TYPE_WALLET
 	case "none":
@@ -163,6 +183,18 @@ func InitConfig() (err error) {
 		return errors.New("invalid consensus argument")
 	}
 
+	if globals.Arguments["--prune"] != nil {
+		if PRUNE_BLOCKS, err = strconv.ParseUint(globals.Arguments["--prune"].(string), 10, 64); err != nil {
+			return
+		}
+		if CONSENSUS != CONSENSUS_TYPE_FULL {
+			return errors.New("--prune requires full consensus")
+		}
+		if PRUNE_BLOCKS < FORK_MAX_UNCLE_ALLOWED {
+			return fmt.Errorf("--prune must keep at least %d blocks", FORK_MAX_UNCLE_ALLOWED)
+		}
+	}
+
 	if globals.Arguments["--light-computations"] == true {
 		LIGHT_COMPUTATIONS = true
 	}
@@ -190,6 +222,10 @@ func InitConfig() (err error) {
 		return
 	}
 
+	if err = config_rate_limit.InitConfig(); err != nil {
+		return
+	}
+
 	if err = config_init(); err != nil {
 		return
 	}
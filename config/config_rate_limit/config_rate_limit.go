@@ -0,0 +1,118 @@
+package config_rate_limit
+
+import (
+	"encoding/json"
+	"errors"
+	"pandora-pay/config/globals"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	RATE_LIMIT_ENABLED          = false        //enabled by --rate-limit
+	RATE_LIMIT_CONNECTION_RATE  = float64(100) //tokens refilled per second for every connection
+	RATE_LIMIT_CONNECTION_BURST = float64(1000)
+	RATE_LIMIT_IP_RATE          = float64(200) //tokens refilled per second for every remote ip. It is shared by all its connections and http requests
+	RATE_LIMIT_IP_BURST         = float64(2000)
+	RATE_LIMIT_DEFAULT_COST     = float64(1)
+	RATE_LIMIT_CONSENSUS_COST   = float64(0.1) //cost of the consensus methods requested by the websocket peers
+	RATE_LIMIT_SCORE_PENALTY    = int32(5)
+	RATE_LIMIT_BAN_VIOLATIONS   = float64(200)
+	RATE_LIMIT_BAN_DECAY        = float64(1) //violations forgiven per second
+	RATE_LIMIT_BAN_DURATION     = 30 * time.Minute
+	RATE_LIMIT_IP_EXPIRATION    = 10 * time.Minute //idle remote ips are removed
+)
+
+//methods used by the peers to sync the chain and the mempool. They cost RATE_LIMIT_CONSENSUS_COST when they are requested over websockets
+var RATE_LIMIT_CONSENSUS_METHODS = map[string]bool{
+	"handshake":         true,
+	"get-chain":         true,
+	"chain-update":      true,
+	"block-hash":        true,
+	"block-complete":    true,
+	"block-miss-txs":    true,
+	"mempool/new-tx-id": true,
+}
+
+// cost of the api methods. Methods not listed cost RATE_LIMIT_DEFAULT_COST
+var RATE_LIMIT_METHOD_COSTS = map[string]float64{
+	"block":                   5,
+	"block-complete":          5,
+	"block-info":              2,
+	"tx":                      2,
+	"tx-raw":                  2,
+	"tx-preview":              2,
+	"accounts/by-keys":        5,
+	"accounts/keys-by-index":  5,
+	"account/txs":             5,
+	"account/history":         10,
+	"account/mempool":         5,
+	"mempool":                 5,
+	"mempool/new-tx":          10,
+	"network/nodes":           5,
+	"blockchain/fee-estimate": 5,
+	"wallet/get-balances":     20,
+	"wallet/decrypt-tx":       20,
+	"wallet/private-transfer": 50,
+	"faucet/coins":            50,
+	"events":                  10,
+}
+
+func InitConfig() (err error) {
+
+	if str := globals.Arguments["--rate-limit"]; str != nil {
+
+		switch str {
+		case "0":
+			RATE_LIMIT_ENABLED = false
+		case "default":
+			RATE_LIMIT_ENABLED = true
+		default:
+			v := strings.Split(str.(string), ",")
+			if len(v) != 4 {
+				return errors.New("--rate-limit argument must be \"default\" or \"connectionRate,connectionBurst,ipRate,ipBurst\"")
+			}
+
+			values := make([]float64, len(v))
+			for i := range v {
+				if values[i], err = strconv.ParseFloat(v[i], 64); err != nil {
+					return
+				}
+				if values[i] <= 0 {
+					return errors.New("--rate-limit values must be positive")
+				}
+			}
+
+			RATE_LIMIT_ENABLED = true
+			RATE_LIMIT_CONNECTION_RATE, RATE_LIMIT_CONNECTION_BURST, RATE_LIMIT_IP_RATE, RATE_LIMIT_IP_BURST = values[0], values[1], values[2], values[3]
+		}
+	}
+
+	if str := globals.Arguments["--rate-limit-costs"]; str != nil {
+
+		costs := map[string]float64{}
+		if err = json.Unmarshal([]byte(str.(string)), &costs); err != nil {
+			return
+		}
+
+		for method, cost := range costs {
+			if cost < 0 {
+				return errors.New("--rate-limit-costs values must not be negative")
+			}
+			RATE_LIMIT_METHOD_COSTS[method] = cost
+		}
+	}
+
+	return
+}
+
+func GetMethodCost(method string, websocket bool) float64 {
+	if websocket && RATE_LIMIT_CONSENSUS_METHODS[method] {
+		return RATE_LIMIT_CONSENSUS_COST
+	}
+	if cost, ok := RATE_LIMIT_METHOD_COSTS[method]; ok {
+		return cost
+	}
+	return RATE_LIMIT_DEFAULT_COST
+}
@@ -8,4 +8,5 @@ var (
 	Data        = map[string]interface{}{}
 	MainEvents  = events.NewEvents[any]()
 	MainStarted = false
+	MainExitCn  = make(chan struct{}, 1) //closes the app like SIGINT
 )
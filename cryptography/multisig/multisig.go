@@ -0,0 +1,72 @@
+package multisig
+
+import (
+	"errors"
+	"math/big"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+)
+
+//M-of-N key generated together by the co-signers. PublicKey is a regular public key and it can be used as SpendPublicKey, UpdatePublicKey or SupplyPublicKey
+type ThresholdKey struct {
+	Threshold       int      `json:"threshold" msgpack:"threshold"`
+	PublicKey       []byte   `json:"publicKey" msgpack:"publicKey"`
+	SharePublicKeys [][]byte `json:"sharePublicKeys" msgpack:"sharePublicKeys"` //public key of the share with index i+1
+}
+
+//secret share of a co-signer
+type ThresholdKeyShare struct {
+	Key   *ThresholdKey `json:"key" msgpack:"key"`
+	Index uint64        `json:"index" msgpack:"index"` //starting from 1
+	Share []byte        `json:"share" msgpack:"share"`
+}
+
+func scalarToBytes(x *big.Int) []byte {
+	return crypto.ConvertBigIntToByte(x)
+}
+
+func decodePoint(data []byte) (*bn256.G1, error) {
+	p := new(bn256.G1)
+	if err := p.DecodeCompressed(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (key *ThresholdKey) Validate() error {
+	if key.Threshold < 1 || key.Threshold > len(key.SharePublicKeys) {
+		return errors.New("Invalid threshold")
+	}
+	if len(key.SharePublicKeys) > 255 {
+		return errors.New("Too many shares")
+	}
+	if len(key.PublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid public key")
+	}
+	for _, sharePublicKey := range key.SharePublicKeys {
+		if len(sharePublicKey) != cryptography.PublicKeySize {
+			return errors.New("Invalid share public key")
+		}
+	}
+	return nil
+}
+
+func (share *ThresholdKeyShare) Validate() error {
+	if share.Key == nil {
+		return errors.New("Threshold key is missing")
+	}
+	if err := share.Key.Validate(); err != nil {
+		return err
+	}
+	if share.Index < 1 || share.Index > uint64(len(share.Key.SharePublicKeys)) {
+		return errors.New("Invalid share index")
+	}
+	if len(share.Share) != cryptography.PrivateKeySize {
+		return errors.New("Invalid share")
+	}
+	if string(new(bn256.G1).ScalarMult(crypto.G, new(big.Int).SetBytes(share.Share)).EncodeCompressed()) != string(share.Key.SharePublicKeys[share.Index-1]) {
+		return errors.New("Share is not matching the share public key")
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package multisig
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+)
+
+//secret polynomial of a co-signer during the key generation. It must never be shared and it must be deleted once the key is generated
+type KeyGeneration struct {
+	Threshold    int      `json:"threshold" msgpack:"threshold"`
+	Total        int      `json:"total" msgpack:"total"`
+	Index        uint64   `json:"index" msgpack:"index"` //starting from 1
+	Coefficients [][]byte `json:"coefficients" msgpack:"coefficients"`
+}
+
+//public commitment of the polynomial of a co-signer. It is sent to all the other co-signers
+type KeyGenerationCommitment struct {
+	Index        uint64   `json:"index" msgpack:"index"`
+	Coefficients [][]byte `json:"coefficients" msgpack:"coefficients"` //a_k*G
+	ProofR       []byte   `json:"proofR" msgpack:"proofR"`             //proof of knowledge of a_0, so a co-signer can not cancel the public keys of the others
+	ProofZ       []byte   `json:"proofZ" msgpack:"proofZ"`
+}
+
+//secret share of the polynomial of the co-signer From. It is sent only to the co-signer To
+type KeyGenerationShare struct {
+	From  uint64 `json:"from" msgpack:"from"`
+	To    uint64 `json:"to" msgpack:"to"`
+	Share []byte `json:"share" msgpack:"share"`
+}
+
+func getKeyGenerationChallenge(index uint64, publicKey, R *bn256.G1) *big.Int {
+	return crypto.ReducedHash([]byte(fmt.Sprintf("multisigkeygen%s%s%s", scalarToBytes(new(big.Int).SetUint64(index)), publicKey.EncodeCompressed(), R.EncodeCompressed())))
+}
+
+//f(x) = c0 + c1*x + ... + c(t-1)*x^(t-1)
+func evaluatePolynomial(coefficients []*big.Int, x uint64) *big.Int {
+	z := new(big.Int).SetUint64(x)
+	out := new(big.Int)
+	for j := len(coefficients) - 1; j >= 0; j-- {
+		out.Mul(out, z)
+		out.Add(out, coefficients[j])
+		out.Mod(out, bn256.Order)
+	}
+	return out
+}
+
+//f(x)*G computed from the commitments of the coefficients
+func evaluatePolynomialCommitments(coefficients []*bn256.G1, x uint64) *bn256.G1 {
+	z := new(big.Int).SetUint64(x)
+	out := new(bn256.G1).ScalarMult(crypto.G, new(big.Int))
+	for j := len(coefficients) - 1; j >= 0; j-- {
+		out = new(bn256.G1).Add(new(bn256.G1).ScalarMult(out, z), coefficients[j])
+	}
+	return out
+}
+
+//round 1 of the key generation. Every co-signer creates its own random polynomial, so the private key is never known by anyone
+func CreateKeyGeneration(index uint64, threshold, total int) (*KeyGeneration, *KeyGenerationCommitment, error) {
+
+	if threshold < 1 || threshold > total {
+		return nil, nil, errors.New("Threshold must be between 1 and total")
+	}
+	if total > 255 {
+		return nil, nil, errors.New("Total must be at most 255")
+	}
+	if index < 1 || index > uint64(total) {
+		return nil, nil, errors.New("Invalid index")
+	}
+
+	gen := &KeyGeneration{threshold, total, index, make([][]byte, threshold)}
+	commitment := &KeyGenerationCommitment{index, make([][]byte, threshold), nil, nil}
+
+	coefficients := make([]*big.Int, threshold)
+	for i := range coefficients {
+		coefficients[i] = crypto.RandomScalar()
+		gen.Coefficients[i] = scalarToBytes(coefficients[i])
+		commitment.Coefficients[i] = new(bn256.G1).ScalarMult(crypto.G, coefficients[i]).EncodeCompressed()
+	}
+
+	k := crypto.RandomScalar()
+	R := new(bn256.G1).ScalarMult(crypto.G, k)
+	c := getKeyGenerationChallenge(index, new(bn256.G1).ScalarMult(crypto.G, coefficients[0]), R)
+
+	z := new(big.Int).Mul(coefficients[0], c)
+	z.Add(z, k)
+	z.Mod(z, bn256.Order)
+
+	commitment.ProofR, commitment.ProofZ = R.EncodeCompressed(), scalarToBytes(z)
+
+	return gen, commitment, nil
+}
+
+func (gen *KeyGeneration) getCoefficients() []*big.Int {
+	out := make([]*big.Int, len(gen.Coefficients))
+	for i := range gen.Coefficients {
+		out[i] = new(big.Int).SetBytes(gen.Coefficients[i])
+	}
+	return out
+}
+
+//the share for the co-signer to
+func (gen *KeyGeneration) GetShare(to uint64) (*KeyGenerationShare, error) {
+	if to < 1 || to > uint64(gen.Total) {
+		return nil, errors.New("Invalid index")
+	}
+	return &KeyGenerationShare{gen.Index, to, scalarToBytes(evaluatePolynomial(gen.getCoefficients(), to))}, nil
+}
+
+func (commitment *KeyGenerationCommitment) verify(threshold int) ([]*bn256.G1, error) {
+
+	if len(commitment.Coefficients) != threshold {
+		return nil, fmt.Errorf("Commitment %d has an invalid number of coefficients", commitment.Index)
+	}
+
+	var err error
+	points := make([]*bn256.G1, threshold)
+	for i := range commitment.Coefficients {
+		if points[i], err = decodePoint(commitment.Coefficients[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var R *bn256.G1
+	if R, err = decodePoint(commitment.ProofR); err != nil {
+		return nil, err
+	}
+	if len(commitment.ProofZ) != 32 {
+		return nil, fmt.Errorf("Commitment %d proof is invalid", commitment.Index)
+	}
+
+	//z*G == R + c*A_0
+	c := getKeyGenerationChallenge(commitment.Index, points[0], R)
+	expected := new(bn256.G1).Add(R, new(bn256.G1).ScalarMult(points[0], c))
+	if string(new(bn256.G1).ScalarMult(crypto.G, new(big.Int).SetBytes(commitment.ProofZ)).EncodeCompressed()) != string(expected.EncodeCompressed()) {
+		return nil, fmt.Errorf("Commitment %d proof is invalid", commitment.Index)
+	}
+
+	return points, nil
+}
+
+//round 2 of the key generation. The threshold key is the sum of the polynomials of all the co-signers and the share of the co-signer is the sum of the shares it received
+func CombineKeyGeneration(gen *KeyGeneration, commitments []*KeyGenerationCommitment, shares []*KeyGenerationShare) (*ThresholdKeyShare, error) {
+
+	if len(commitments) != gen.Total {
+		return nil, errors.New("Commitments of all the co-signers are required")
+	}
+
+	polynomials := make(map[uint64][]*bn256.G1)
+	for _, commitment := range commitments {
+		if commitment.Index < 1 || commitment.Index > uint64(gen.Total) || polynomials[commitment.Index] != nil {
+			return nil, errors.New("Invalid commitment index")
+		}
+		points, err := commitment.verify(gen.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		polynomials[commitment.Index] = points
+	}
+
+	own, err := gen.GetShare(gen.Index)
+	if err != nil {
+		return nil, err
+	}
+	if string(new(bn256.G1).ScalarMult(crypto.G, new(big.Int).SetBytes(own.Share)).EncodeCompressed()) != string(evaluatePolynomialCommitments(polynomials[gen.Index], gen.Index).EncodeCompressed()) {
+		return nil, errors.New("Commitment is not matching the key generation")
+	}
+
+	if len(shares) != gen.Total-1 {
+		return nil, errors.New("Shares of all the other co-signers are required")
+	}
+
+	received := map[uint64]bool{gen.Index: true}
+	secret := new(big.Int).SetBytes(own.Share)
+
+	for _, share := range shares {
+		if share.To != gen.Index {
+			return nil, errors.New("Share was sent to another co-signer")
+		}
+		if share.From < 1 || share.From > uint64(gen.Total) || received[share.From] {
+			return nil, errors.New("Invalid share index")
+		}
+		received[share.From] = true
+
+		if len(share.Share) != 32 {
+			return nil, fmt.Errorf("Share %d is invalid", share.From)
+		}
+
+		//the share must be on the committed polynomial, otherwise the co-signers would end with shares of different keys
+		value := new(big.Int).SetBytes(share.Share)
+		if string(new(bn256.G1).ScalarMult(crypto.G, value).EncodeCompressed()) != string(evaluatePolynomialCommitments(polynomials[share.From], gen.Index).EncodeCompressed()) {
+			return nil, fmt.Errorf("Share %d is not matching its commitment", share.From)
+		}
+
+		secret.Add(secret, value)
+		secret.Mod(secret, bn256.Order)
+	}
+
+	publicKey := new(bn256.G1).ScalarMult(crypto.G, new(big.Int))
+	for _, points := range polynomials {
+		publicKey.Add(publicKey, points[0])
+	}
+
+	key := &ThresholdKey{gen.Threshold, publicKey.EncodeCompressed(), make([][]byte, gen.Total)}
+	for i := range key.SharePublicKeys {
+		sharePublicKey := new(bn256.G1).ScalarMult(crypto.G, new(big.Int))
+		for _, points := range polynomials {
+			sharePublicKey.Add(sharePublicKey, evaluatePolynomialCommitments(points, uint64(i+1)))
+		}
+		key.SharePublicKeys[i] = sharePublicKey.EncodeCompressed()
+	}
+
+	share := &ThresholdKeyShare{key, gen.Index, scalarToBytes(secret)}
+	if err = share.Validate(); err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
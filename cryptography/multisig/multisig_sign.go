@@ -0,0 +1,254 @@
+package multisig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"sort"
+)
+
+//secret nonce of a co-signer. It must be used only once and never shared
+type Nonce struct {
+	Index uint64 `json:"index" msgpack:"index"`
+	D     []byte `json:"d" msgpack:"d"`
+	E     []byte `json:"e" msgpack:"e"`
+}
+
+//public commitment of a Nonce. It is sent to the other co-signers
+type Commitment struct {
+	Index uint64 `json:"index" msgpack:"index"`
+	D     []byte `json:"d" msgpack:"d"`
+	E     []byte `json:"e" msgpack:"e"`
+}
+
+type PartialSignature struct {
+	Index uint64 `json:"index" msgpack:"index"`
+	S     []byte `json:"s" msgpack:"s"`
+}
+
+//round 1 of signing
+func CreateNonce(share *ThresholdKeyShare) (*Nonce, *Commitment, error) {
+
+	if err := share.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	d, e := crypto.RandomScalar(), crypto.RandomScalar()
+
+	nonce := &Nonce{share.Index, scalarToBytes(d), scalarToBytes(e)}
+	commitment := &Commitment{
+		share.Index,
+		new(bn256.G1).ScalarMult(crypto.G, d).EncodeCompressed(),
+		new(bn256.G1).ScalarMult(crypto.G, e).EncodeCompressed(),
+	}
+
+	return nonce, commitment, nil
+}
+
+type signingContext struct {
+	publicKey *bn256.G1
+	indexes   []uint64
+	bindings  map[uint64]*big.Int
+	points    map[uint64][2]*bn256.G1
+	R         *bn256.G1
+	c         *big.Int
+}
+
+func sortCommitments(key *ThresholdKey, commitments []*Commitment) ([]*Commitment, error) {
+
+	if len(commitments) < key.Threshold {
+		return nil, errors.New("Not enough commitments")
+	}
+
+	sorted := make([]*Commitment, len(commitments))
+	copy(sorted, commitments)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Index < sorted[j].Index
+	})
+
+	for i, commitment := range sorted {
+		if commitment.Index < 1 || commitment.Index > uint64(len(key.SharePublicKeys)) {
+			return nil, errors.New("Invalid commitment index")
+		}
+		if i > 0 && sorted[i-1].Index == commitment.Index {
+			return nil, errors.New("Duplicate commitment")
+		}
+	}
+
+	return sorted, nil
+}
+
+func createSigningContext(key *ThresholdKey, message []byte, commitments []*Commitment) (*signingContext, error) {
+
+	if err := key.Validate(); err != nil {
+		return nil, err
+	}
+
+	sorted, err := sortCommitments(key, commitments)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &signingContext{
+		nil,
+		make([]uint64, len(sorted)),
+		make(map[uint64]*big.Int),
+		make(map[uint64][2]*bn256.G1),
+		new(bn256.G1).ScalarMult(crypto.G, new(big.Int)),
+		nil,
+	}
+
+	if ctx.publicKey, err = decodePoint(key.PublicKey); err != nil {
+		return nil, err
+	}
+
+	encoded := &bytes.Buffer{}
+	for i, commitment := range sorted {
+
+		ctx.indexes[i] = commitment.Index
+
+		var D, E *bn256.G1
+		if D, err = decodePoint(commitment.D); err != nil {
+			return nil, err
+		}
+		if E, err = decodePoint(commitment.E); err != nil {
+			return nil, err
+		}
+		ctx.points[commitment.Index] = [2]*bn256.G1{D, E}
+
+		encoded.Write(scalarToBytes(new(big.Int).SetUint64(commitment.Index)))
+		encoded.Write(commitment.D)
+		encoded.Write(commitment.E)
+	}
+
+	//the binding factors tie every nonce to the message and to all the commitments
+	for _, index := range ctx.indexes {
+
+		binding := crypto.ReducedHash([]byte(fmt.Sprintf("multisig%s%s%s", scalarToBytes(new(big.Int).SetUint64(index)), message, encoded.Bytes())))
+		ctx.bindings[index] = binding
+
+		points := ctx.points[index]
+		ctx.R.Add(ctx.R, points[0])
+		ctx.R.Add(ctx.R, new(bn256.G1).ScalarMult(points[1], binding))
+	}
+
+	//same challenge as crypto.SignMessage, so the final signature is a regular one
+	ctx.c = crypto.ReducedHash([]byte(fmt.Sprintf("%s%s%s", ctx.publicKey.String(), ctx.R.String(), string(message))))
+
+	return ctx, nil
+}
+
+func (ctx *signingContext) lagrange(index uint64) *big.Int {
+
+	num, den := big.NewInt(1), big.NewInt(1)
+	x := new(big.Int).SetUint64(index)
+
+	for _, other := range ctx.indexes {
+		if other == index {
+			continue
+		}
+		y := new(big.Int).SetUint64(other)
+		num.Mul(num, y)
+		num.Mod(num, bn256.Order)
+		den.Mul(den, new(big.Int).Sub(y, x))
+		den.Mod(den, bn256.Order)
+	}
+
+	return num.Mul(num, den.ModInverse(den, bn256.Order)).Mod(num, bn256.Order)
+}
+
+//round 2 of signing. The nonce must be deleted afterwards
+func Sign(share *ThresholdKeyShare, nonce *Nonce, message []byte, commitments []*Commitment) (*PartialSignature, error) {
+
+	if err := share.Validate(); err != nil {
+		return nil, err
+	}
+	if nonce.Index != share.Index {
+		return nil, errors.New("Nonce doesn't belong to the share")
+	}
+
+	ctx, err := createSigningContext(share.Key, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+
+	d, e := new(big.Int).SetBytes(nonce.D), new(big.Int).SetBytes(nonce.E)
+
+	points, ok := ctx.points[share.Index]
+	if !ok {
+		return nil, errors.New("Commitment of the share is missing")
+	}
+	if !bytes.Equal(points[0].EncodeCompressed(), new(bn256.G1).ScalarMult(crypto.G, d).EncodeCompressed()) ||
+		!bytes.Equal(points[1].EncodeCompressed(), new(bn256.G1).ScalarMult(crypto.G, e).EncodeCompressed()) {
+		return nil, errors.New("Commitment is not matching the nonce")
+	}
+
+	s := new(big.Int).Mul(ctx.c, ctx.lagrange(share.Index))
+	s.Mul(s, new(big.Int).SetBytes(share.Share))
+	s.Add(s, d)
+	s.Add(s, new(big.Int).Mul(e, ctx.bindings[share.Index]))
+	s.Mod(s, bn256.Order)
+
+	return &PartialSignature{share.Index, scalarToBytes(s)}, nil
+}
+
+//aggregates the partial signatures in a signature that can be verified with crypto.VerifySignature against key.PublicKey
+func Combine(key *ThresholdKey, message []byte, commitments []*Commitment, partials []*PartialSignature) ([]byte, error) {
+
+	ctx, err := createSigningContext(key, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(partials) != len(ctx.indexes) {
+		return nil, errors.New("Number of partial signatures is not matching the number of commitments")
+	}
+
+	used := make(map[uint64]bool)
+	s := new(big.Int)
+
+	for _, partial := range partials {
+
+		points, ok := ctx.points[partial.Index]
+		if !ok || used[partial.Index] {
+			return nil, errors.New("Invalid partial signature index")
+		}
+		used[partial.Index] = true
+
+		if len(partial.S) != 32 {
+			return nil, errors.New("Invalid partial signature")
+		}
+
+		var Y *bn256.G1
+		if Y, err = decodePoint(key.SharePublicKeys[partial.Index-1]); err != nil {
+			return nil, err
+		}
+
+		//s_i*G == D_i + binding_i*E_i + c*lambda_i*Y_i
+		si := new(big.Int).SetBytes(partial.S)
+		expected := new(bn256.G1).Add(points[0], new(bn256.G1).ScalarMult(points[1], ctx.bindings[partial.Index]))
+		coefficient := new(big.Int).Mul(ctx.c, ctx.lagrange(partial.Index))
+		expected.Add(expected, new(bn256.G1).ScalarMult(Y, coefficient.Mod(coefficient, bn256.Order)))
+
+		if !bytes.Equal(new(bn256.G1).ScalarMult(crypto.G, si).EncodeCompressed(), expected.EncodeCompressed()) {
+			return nil, fmt.Errorf("Partial signature %d is invalid", partial.Index)
+		}
+
+		s.Add(s, si)
+	}
+	s.Mod(s, bn256.Order)
+
+	out := make([]byte, cryptography.SignatureSize)
+	copy(out[:32], scalarToBytes(s))
+	copy(out[32:], scalarToBytes(ctx.c))
+
+	if !crypto.VerifySignaturePoint(message, out, ctx.publicKey) {
+		return nil, errors.New("Combined signature is invalid")
+	}
+
+	return out, nil
+}
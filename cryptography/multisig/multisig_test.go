@@ -0,0 +1,129 @@
+package multisig
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"testing"
+)
+
+//runs the key generation of all the co-signers
+func generateTestThresholdKey(t *testing.T, threshold, total int) []*ThresholdKeyShare {
+
+	gens := make([]*KeyGeneration, total)
+	commitments := make([]*KeyGenerationCommitment, total)
+	for i := range gens {
+		var err error
+		gens[i], commitments[i], err = CreateKeyGeneration(uint64(i+1), threshold, total)
+		assert.Nil(t, err)
+	}
+
+	shares := make([]*ThresholdKeyShare, total)
+	for i, gen := range gens {
+
+		received := make([]*KeyGenerationShare, 0)
+		for _, other := range gens {
+			if other != gen {
+				share, err := other.GetShare(gen.Index)
+				assert.Nil(t, err)
+				received = append(received, share)
+			}
+		}
+
+		var err error
+		shares[i], err = CombineKeyGeneration(gen, commitments, received)
+		assert.Nil(t, err)
+	}
+
+	return shares
+}
+
+func TestKeyGeneration(t *testing.T) {
+
+	shares := generateTestThresholdKey(t, 2, 3)
+
+	//all the co-signers computed the same key
+	for _, share := range shares {
+		assert.Equal(t, shares[0].Key, share.Key)
+		assert.Nil(t, share.Validate())
+	}
+
+	//any threshold shares rebuild the private key of the public key, so none of the co-signers knew it alone
+	lambda1, lambda2 := big.NewInt(2), new(big.Int).Sub(bn256.Order, big.NewInt(1))
+	privateKey := new(big.Int).Mul(new(big.Int).SetBytes(shares[0].Share), lambda1)
+	privateKey.Add(privateKey, new(big.Int).Mul(new(big.Int).SetBytes(shares[1].Share), lambda2))
+	privateKey.Mod(privateKey, bn256.Order)
+	assert.Equal(t, shares[0].Key.PublicKey, new(bn256.G1).ScalarMult(crypto.G, privateKey).EncodeCompressed())
+
+	gen1, commitment1, err := CreateKeyGeneration(1, 2, 2)
+	assert.Nil(t, err)
+	gen2, commitment2, err := CreateKeyGeneration(2, 2, 2)
+	assert.Nil(t, err)
+	share2, err := gen2.GetShare(1)
+	assert.Nil(t, err)
+
+	//share not on the committed polynomial
+	wrongShare := &KeyGenerationShare{2, 1, scalarToBytes(crypto.RandomScalar())}
+	_, err = CombineKeyGeneration(gen1, []*KeyGenerationCommitment{commitment1, commitment2}, []*KeyGenerationShare{wrongShare})
+	assert.EqualError(t, err, "Share 2 is not matching its commitment")
+
+	//the public key of the co-signer 2 replaced to cancel the public key of the co-signer 1
+	rogue := &KeyGenerationCommitment{2, append([][]byte{}, commitment2.Coefficients...), commitment2.ProofR, commitment2.ProofZ}
+	rogue.Coefficients[0] = new(bn256.G1).Neg(new(bn256.G1).ScalarMult(crypto.G, new(big.Int).SetBytes(gen1.Coefficients[0]))).EncodeCompressed()
+	_, err = CombineKeyGeneration(gen1, []*KeyGenerationCommitment{commitment1, rogue}, []*KeyGenerationShare{share2})
+	assert.EqualError(t, err, "Commitment 2 proof is invalid")
+
+	_, err = CombineKeyGeneration(gen1, []*KeyGenerationCommitment{commitment1}, []*KeyGenerationShare{share2})
+	assert.NotNil(t, err)
+
+	share, err := CombineKeyGeneration(gen1, []*KeyGenerationCommitment{commitment1, commitment2}, []*KeyGenerationShare{share2})
+	assert.Nil(t, err)
+	assert.Nil(t, share.Validate())
+}
+
+func TestThresholdSign(t *testing.T) {
+
+	shares := generateTestThresholdKey(t, 2, 3)
+	assert.Equal(t, 3, len(shares))
+	var err error
+
+	key := shares[0].Key
+	message := []byte("message to sign")
+
+	for _, signers := range [][]int{{0, 1}, {1, 2}, {0, 2}, {0, 1, 2}} {
+
+		nonces := make([]*Nonce, len(signers))
+		commitments := make([]*Commitment, len(signers))
+		for i, signer := range signers {
+			nonces[i], commitments[i], err = CreateNonce(shares[signer])
+			assert.Nil(t, err)
+		}
+
+		partials := make([]*PartialSignature, len(signers))
+		for i, signer := range signers {
+			partials[i], err = Sign(shares[signer], nonces[i], message, commitments)
+			assert.Nil(t, err)
+		}
+
+		signature, err := Combine(key, message, commitments, partials)
+		assert.Nil(t, err)
+		assert.True(t, crypto.VerifySignature(message, signature, key.PublicKey))
+		assert.False(t, crypto.VerifySignature([]byte("other message"), signature, key.PublicKey))
+	}
+
+	//below threshold
+	_, commitment, err := CreateNonce(shares[0])
+	assert.Nil(t, err)
+	_, err = Combine(key, message, []*Commitment{commitment}, nil)
+	assert.NotNil(t, err)
+
+	//tampered partial signature
+	nonce0, commitment0, _ := CreateNonce(shares[0])
+	nonce1, commitment1, _ := CreateNonce(shares[1])
+	commitments := []*Commitment{commitment0, commitment1}
+	partial0, _ := Sign(shares[0], nonce0, message, commitments)
+	partial1, _ := Sign(shares[1], nonce1, []byte("other message"), commitments)
+	_, err = Combine(key, message, commitments, []*PartialSignature{partial0, partial1})
+	assert.NotNil(t, err)
+}
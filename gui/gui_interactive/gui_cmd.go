@@ -52,17 +52,39 @@ var commands = []Command{
 	{Name: "Wallet:TX", Text: "Private Claim"},
 	{Name: "Wallet:TX", Text: "Private Asset Create"},
 	{Name: "Wallet:TX", Text: "Private Asset Supply Increase"},
+	{Name: "Wallet:TX", Text: "Private Asset Supply Decrease"},
+	{Name: "Wallet:TX", Text: "Private Asset Pause"},
+	{Name: "Wallet:TX", Text: "Private Asset Freeze Supply"},
+	{Name: "Wallet:TX", Text: "Private Asset Update"},
 	{Name: "Wallet:TX", Text: "Private Plain Account Fund"},
 	{Name: "Wallet:TX", Text: "Update Asset Fee Liquidity"},
+	{Name: "Wallet:TX", Text: "Multisig Key Generation"},
+	{Name: "Wallet:TX", Text: "Multisig Key Generation Combine"},
+	{Name: "Wallet:TX", Text: "Multisig Commit"},
+	{Name: "Wallet:TX", Text: "Multisig Partial Sign"},
+	{Name: "Wallet:TX", Text: "Multisig Combine"},
 	{Name: "Wallet", Text: "Export Addresses"},
 	{Name: "Wallet", Text: "Export Address JSON"},
 	{Name: "Wallet", Text: "Import Address JSON"},
+	{Name: "Wallet", Text: "Export Address View-Only JSON"},
+	{Name: "Wallet", Text: "Import Watch-Only Address JSON"},
 	{Name: "Wallet", Text: "Export Wallet JSON"},
 	{Name: "Wallet", Text: "Import Wallet JSON"},
 	{Name: "Wallet", Text: "Encrypt Wallet"},
 	{Name: "Wallet", Text: "Decrypt Wallet"},
 	{Name: "Wallet", Text: "Remove Encryption"},
+	{Name: "Wallet", Text: "Create Invoice"},
+	{Name: "Wallet", Text: "List Invoices"},
+	{Name: "Wallet", Text: "Invoice Status"},
 	{Name: "Mempool", Text: "Show Txs"},
+	{Name: "Network", Text: "List Banned Nodes"},
+	{Name: "Network", Text: "Ban Node"},
+	{Name: "Network", Text: "Unban Node"},
+	{Name: "Chain", Text: "Export Snapshot"},
+	{Name: "Chain", Text: "Verify Chain"},
+	{Name: "Chain", Text: "Rollback Chain"},
+	{Name: "Chain", Text: "Export Blocks"},
+	{Name: "Chain", Text: "Import Blocks"},
 	{Name: "App", Text: "Exit"},
 }
 var commandsLock sync.Mutex
@@ -147,6 +169,7 @@ func (g *GUIInteractive) cmdProcess(e ui.Event) {
 			return
 		}
 		g.Close()
+		g.exit()
 	case "<Enter>":
 
 		if cmdData.cmdStatus == "cmd" {
@@ -4,7 +4,7 @@ import (
 	"context"
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
-	"os"
+	"pandora-pay/config/globals"
 	"pandora-pay/gui/gui_interface"
 	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/generics"
@@ -52,6 +52,14 @@ func (g *GUIInteractive) Close() {
 	g.logger.GeneralLog.Close()
 }
 
+//the app is closed by main, saving the mempool and closing the stores
+func (g *GUIInteractive) exit() {
+	select {
+	case globals.MainExitCn <- struct{}{}:
+	default:
+	}
+}
+
 func CreateGUIInteractive() (*GUIInteractive, error) {
 
 	logger, err := gui_logger.CreateLogger()
@@ -127,7 +135,7 @@ func CreateGUIInteractive() (*GUIInteractive, error) {
 	}()
 
 	g.CommandDefineCallback("Exit", func(string, context.Context) error {
-		os.Exit(1)
+		g.exit()
 		return nil
 	}, true)
 
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"io"
+	"pandora-pay/helpers/generics"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type gauge struct {
+	name     string
+	help     string
+	callback func() float64
+}
+
+type histogram struct {
+	counts []uint64 //one for each bucket
+	count  uint64
+	sum    float64
+	lock   sync.Mutex
+}
+
+var (
+	gauges     []*gauge
+	gaugesLock sync.RWMutex
+
+	apiLatencies = &generics.Map[string, *histogram]{}
+
+	latencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+//registers a gauge whose value is read every time the metrics are exported
+func AddGauge(name, help string, callback func() float64) {
+	gaugesLock.Lock()
+	defer gaugesLock.Unlock()
+	gauges = append(gauges, &gauge{name, help, callback})
+}
+
+func ObserveAPILatency(transport, method string, duration time.Duration) {
+
+	key := transport + "\x00" + method
+	h, found := apiLatencies.Load(key)
+	if !found {
+		h, _ = apiLatencies.LoadOrStore(key, &histogram{counts: make([]uint64, len(latencyBuckets))})
+	}
+
+	value := duration.Seconds()
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for i, bucket := range latencyBuckets {
+		if value <= bucket {
+			h.counts[i] += 1
+		}
+	}
+	h.count += 1
+	h.sum += value
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+//writes all metrics using the prometheus text exposition format
+func Write(w io.Writer) (err error) {
+
+	var b strings.Builder
+
+	gaugesLock.RLock()
+	list := gauges
+	gaugesLock.RUnlock()
+
+	for _, g := range list {
+		b.WriteString("# HELP " + g.name + " " + g.help + "\n")
+		b.WriteString("# TYPE " + g.name + " gauge\n")
+		b.WriteString(g.name + " " + formatFloat(g.callback()) + "\n")
+	}
+
+	keys := make([]string, 0)
+	apiLatencies.Range(func(key string, h *histogram) bool {
+		keys = append(keys, key)
+		return true
+	})
+	sort.Strings(keys)
+
+	const name = "pandora_api_request_duration_seconds"
+	b.WriteString("# HELP " + name + " Latency of the API requests\n")
+	b.WriteString("# TYPE " + name + " histogram\n")
+
+	for _, key := range keys {
+
+		h, _ := apiLatencies.Load(key)
+		parts := strings.SplitN(key, "\x00", 2)
+		labels := `transport="` + labelEscaper.Replace(parts[0]) + `",method="` + labelEscaper.Replace(parts[1]) + `"`
+
+		h.lock.Lock()
+		for i, bucket := range latencyBuckets {
+			b.WriteString(name + "_bucket{" + labels + `,le="` + formatFloat(bucket) + `"} ` + strconv.FormatUint(h.counts[i], 10) + "\n")
+		}
+		b.WriteString(name + "_bucket{" + labels + `,le="+Inf"} ` + strconv.FormatUint(h.count, 10) + "\n")
+		b.WriteString(name + "_sum{" + labels + "} " + formatFloat(h.sum) + "\n")
+		b.WriteString(name + "_count{" + labels + "} " + strconv.FormatUint(h.count, 10) + "\n")
+		h.lock.Unlock()
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return
+}
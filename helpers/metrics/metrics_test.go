@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+
+	AddGauge("test_gauge", "Test gauge", func() float64 {
+		return 12.5
+	})
+
+	ObserveAPILatency("http", "chain", 3*time.Millisecond)
+	ObserveAPILatency("http", "chain", 2*time.Second)
+
+	var b strings.Builder
+	assert.Nil(t, Write(&b))
+	out := b.String()
+
+	assert.Contains(t, out, "# TYPE test_gauge gauge\ntest_gauge 12.5\n")
+	assert.Contains(t, out, `pandora_api_request_duration_seconds_bucket{transport="http",method="chain",le="0.001"} 0`)
+	assert.Contains(t, out, `pandora_api_request_duration_seconds_bucket{transport="http",method="chain",le="0.005"} 1`)
+	assert.Contains(t, out, `pandora_api_request_duration_seconds_bucket{transport="http",method="chain",le="2.5"} 2`)
+	assert.Contains(t, out, `pandora_api_request_duration_seconds_bucket{transport="http",method="chain",le="+Inf"} 2`)
+	assert.Contains(t, out, `pandora_api_request_duration_seconds_count{transport="http",method="chain"} 2`)
+}
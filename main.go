@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"pandora-pay/app"
 	"pandora-pay/config"
 	"pandora-pay/config/arguments"
 	"pandora-pay/config/globals"
@@ -25,6 +26,15 @@ func main() {
 		panic(err)
 	}
 
+	if globals.Arguments["--print-config"] == true {
+		var data string
+		if data, err = arguments.PrintConfig(globals.Arguments); err != nil {
+			panic(err)
+		}
+		fmt.Print(data)
+		return
+	}
+
 	if err = config.InitConfig(); err != nil {
 		panic(err)
 	}
@@ -34,8 +44,13 @@ func main() {
 
 	exitSignal := make(chan os.Signal, 10)
 	signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
-	<-exitSignal
+	select {
+	case <-exitSignal:
+	case <-globals.MainExitCn:
+	}
 
 	fmt.Println("Shutting down")
 
+	app.Close()
+
 }
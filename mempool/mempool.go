@@ -3,6 +3,7 @@ package mempool
 import (
 	"context"
 	"errors"
+	"github.com/tevino/abool"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
@@ -15,15 +16,19 @@ import (
 	"pandora-pay/recovery"
 	"pandora-pay/txs_validator"
 	"runtime"
+	"sync"
 	"time"
 )
 
 type mempoolTx struct {
-	Tx          *transaction.Transaction `json:"tx" msgpack:"tx"`
-	Added       int64                    `json:"added" msgpack:"added"`
-	Mine        bool                     `json:"mine" msgpack:"mine"`
-	FeePerByte  uint64                   `json:"feePerByte" msgpack:"feePerByte"`
-	ChainHeight uint64                   `json:"chainHeight" msgpack:"chainHeight"`
+	Tx            *transaction.Transaction `json:"tx" msgpack:"tx"`
+	Added         int64                    `json:"added" msgpack:"added"`
+	Mine          bool                     `json:"mine" msgpack:"mine"`
+	FeePerByte    uint64                   `json:"feePerByte" msgpack:"feePerByte"`
+	ChainHeight   uint64                   `json:"chainHeight" msgpack:"chainHeight"`
+	conflictKeys  map[string]bool
+	replaceTxHash []byte              //the pending tx explicitly replaced by the wallet. The peers find it only by the conflict keys
+	replacement   *mempoolReplacement //set while the replacement is not validated
 }
 
 type Mempool struct {
@@ -36,6 +41,10 @@ type Mempool struct {
 	removeTransactionsCn      chan *MempoolWorkerRemoveTxs
 	insertTransactionsCn      chan *MempoolWorkerInsertTxs
 	Txs                       *MempoolTxs
+	loadedFromStore           *abool.AtomicBool
+	saveLoop                  *sync.WaitGroup
+	closeCn                   chan struct{}
+	closed                    *abool.AtomicBool
 	OnBroadcastNewTransaction func([]*transaction.Transaction, bool, bool, advanced_connection_types.UUID, context.Context) []error
 }
 
@@ -45,13 +54,13 @@ func (mempool *Mempool) ContinueProcessing(continueProcessingType ContinueProces
 
 func (mempool *Mempool) RemoveInsertedTxsFromBlockchain(txs []string) bool {
 	answerCn := make(chan bool)
-	mempool.removeTransactionsCn <- &MempoolWorkerRemoveTxs{txs, answerCn}
+	mempool.removeTransactionsCn <- &MempoolWorkerRemoveTxs{txs, true, answerCn}
 	return <-answerCn
 }
 
 func (mempool *Mempool) InsertRemovedTxsFromBlockchain(txs []*transaction.Transaction, height uint64) bool {
 
-	finalTxs, _ := mempool.processTxsToMempool(txs, height, context.Background())
+	finalTxs, _ := mempool.processTxsToMempool(txs, nil, height, context.Background())
 
 	insertTxs := make([]*mempoolTx, len(finalTxs))
 	for i, it := range finalTxs {
@@ -71,7 +80,13 @@ func (mempool *Mempool) AddTxToMempool(tx *transaction.Transaction, height uint6
 	return result[0]
 }
 
-func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, height uint64, ctx context.Context) (finalTxs []*mempoolTx, errs []error) {
+//the tx replaces the pending tx replaceTxHash only if it pays a higher fee per byte. The replaced tx is restored if the replacement can not be included
+func (mempool *Mempool) ReplaceTxInMempool(tx *transaction.Transaction, replaceTxHash []byte, height uint64, awaitAnswer, awaitBroadcasting bool, ctx context.Context) error {
+	result := mempool.addTxsToMempool([]*transaction.Transaction{tx}, replaceTxHash, height, true, awaitAnswer, awaitBroadcasting, advanced_connection_types.UUID_ALL, ctx)
+	return result[0]
+}
+
+func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, replaceTxHash []byte, height uint64, ctx context.Context) (finalTxs []*mempoolTx, errs []error) {
 
 	finalTxs = make([]*mempoolTx, len(txs))
 	errs = make([]error, len(txs))
@@ -132,10 +147,12 @@ func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, heig
 		}
 
 		finalTxs[i] = &mempoolTx{
-			Tx:          tx,
-			Added:       time.Now().Unix(),
-			FeePerByte:  computedFeePerByte,
-			ChainHeight: height,
+			Tx:            tx,
+			Added:         time.Now().Unix(),
+			FeePerByte:    computedFeePerByte,
+			ChainHeight:   height,
+			conflictKeys:  computeTxConflictKeys(tx),
+			replaceTxHash: replaceTxHash,
 		}
 
 	}
@@ -144,8 +161,18 @@ func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, heig
 }
 
 func (mempool *Mempool) AddTxsToMempool(txs []*transaction.Transaction, height uint64, justCreated, awaitAnswer, awaitBroadcasting bool, exceptSocketUUID advanced_connection_types.UUID, ctx context.Context) []error {
+	return mempool.addTxsToMempool(txs, nil, height, justCreated, awaitAnswer, awaitBroadcasting, exceptSocketUUID, ctx)
+}
+
+func (mempool *Mempool) addTxsToMempool(txs []*transaction.Transaction, replaceTxHash []byte, height uint64, justCreated, awaitAnswer, awaitBroadcasting bool, exceptSocketUUID advanced_connection_types.UUID, ctx context.Context) []error {
 
-	finalTxs, errs := mempool.processTxsToMempool(txs, height, ctx)
+	finalTxs, errs := mempool.processTxsToMempool(txs, replaceTxHash, height, ctx)
+
+	for _, finalTx := range finalTxs {
+		if finalTx != nil {
+			finalTx.Mine = justCreated
+		}
+	}
 
 	//making sure that the transaction is not inserted twice
 	if runtime.GOARCH != "wasm" {
@@ -239,6 +266,10 @@ func CreateMempool(txsValidator *txs_validator.TxsValidator) (*Mempool, error) {
 		make(chan *MempoolWorkerRemoveTxs),
 		make(chan *MempoolWorkerInsertTxs),
 		createMempoolTxs(),
+		abool.New(),
+		&sync.WaitGroup{},
+		make(chan struct{}),
+		abool.New(),
 		nil,
 	}
 
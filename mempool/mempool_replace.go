@@ -0,0 +1,104 @@
+package mempool
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//the replacement is included again by the worker after the conflicting txs were evicted
+type mempoolReplacement struct {
+	replacedTxs []*mempoolTx //restored if the replacement can not be included
+	result      chan<- error
+}
+
+//the keys can be spent only by a single tx. Simple txs are spending the nonce. Zether txs are spending the balance of the sender ring of every payload,
+//as the real sender can not be distinguished from the decoys. The replacement keeps the same rings, so every node computes the same conflicts from the txs.
+//Independent payments of the same sender are using other random rings
+func computeTxConflictKeys(tx *transaction.Transaction) map[string]bool {
+
+	out := make(map[string]bool)
+
+	switch tx.Version {
+	case transaction_type.TX_SIMPLE:
+		txBase := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+		out["nonce:"+string(txBase.Vin.PublicKey)+":"+strconv.FormatUint(txBase.Nonce, 10)] = true
+	case transaction_type.TX_ZETHER:
+		txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+		for t, payload := range txBase.Payloads {
+			senders := make([]string, 0, len(txBase.Bloom.PublicKeyLists[t])/2)
+			for i, publicKey := range txBase.Bloom.PublicKeyLists[t] {
+				if (i%2 == 0) == payload.Parity {
+					senders = append(senders, string(publicKey))
+				}
+			}
+			sort.Strings(senders)
+			out["ring:"+string(payload.Asset)+":"+strings.Join(senders, "")] = true
+		}
+	}
+
+	return out
+}
+
+func getConflictingTxs(list []*mempoolTx, tx *mempoolTx) []*mempoolTx {
+
+	out := make([]*mempoolTx, 0)
+	for _, other := range list {
+		if other.Tx.Bloom.HashStr == tx.Tx.Bloom.HashStr {
+			continue
+		}
+		for key := range tx.conflictKeys {
+			if other.conflictKeys[key] {
+				out = append(out, other)
+				break
+			}
+		}
+	}
+
+	return out
+}
+
+func (mempool *Mempool) GetConflictingTxs(tx *mempoolTx) []*mempoolTx {
+	return getConflictingTxs(mempool.Txs.GetTxsList(), tx)
+}
+
+//the replacement must pay a strictly higher fee per byte than all the conflicting txs and must be included without them. Only then the conflicting txs are evicted
+func replaceConflictingTxs(tx *mempoolTx, conflicts []*mempoolTx, include func() error, evict func()) error {
+
+	if len(conflicts) == 0 {
+		return errors.New("Transaction to be replaced was not found in mempool")
+	}
+
+	//the tx explicitly replaced by the wallet must be still pending
+	if tx.replaceTxHash != nil {
+		found := false
+		for _, conflict := range conflicts {
+			if bytes.Equal(conflict.Tx.Bloom.Hash, tx.replaceTxHash) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("Transaction to be replaced was not found in mempool")
+		}
+	}
+
+	for _, conflict := range conflicts {
+		if tx.FeePerByte <= conflict.FeePerByte {
+			return errors.New("Transaction conflicts with a mempool transaction and the fee per byte is not higher")
+		}
+	}
+
+	if err := include(); err != nil {
+		return err
+	}
+
+	evict()
+	return nil
+}
@@ -0,0 +1,124 @@
+package mempool
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder/wizard"
+	"testing"
+)
+
+func getTestPoint(t *testing.T, privateKey *addresses.PrivateKey) (*addresses.Address, *bn256.G1) {
+	addr, err := privateKey.GenerateAddress(false, nil, false, nil, 0, nil)
+	assert.NoError(t, err)
+	point, err := addr.GetPoint()
+	assert.NoError(t, err)
+	return addr, point.G1()
+}
+
+//sender transfers to a new recipient using decoy as the other sender ring member
+func createTestZetherTx(t *testing.T, sender, decoy *addresses.PrivateKey, chainHeight uint64, chainKernelHash []byte) *mempoolTx {
+
+	_, senderPoint := getTestPoint(t, sender)
+	_, decoyPoint := getTestPoint(t, decoy)
+	recipientAddr, recipientPoint := getTestPoint(t, addresses.GenerateNewPrivateKey())
+	_, otherPoint := getTestPoint(t, addresses.GenerateNewPrivateKey())
+
+	emap := wizard.InitializeEmap([][]byte{config_coins.NATIVE_ASSET_FULL})
+	publicKeyIndexes := make(map[string]*wizard.WizardZetherPublicKeyIndex)
+	for _, point := range []*bn256.G1{senderPoint, decoyPoint, recipientPoint, otherPoint} {
+		balance := crypto.ConstructElGamal(point, crypto.ElGamal_BASE_G)
+		if point == senderPoint {
+			balance = balance.Plus(new(big.Int).SetUint64(1000))
+		}
+		emap[config_coins.NATIVE_ASSET_FULL_STRING][point.String()] = balance.Serialize()
+		publicKeyIndexes[string(point.EncodeCompressed())] = &wizard.WizardZetherPublicKeyIndex{Registered: true}
+	}
+
+	transfers := []*wizard.WizardZetherTransfer{{
+		Asset:                  config_coins.NATIVE_ASSET_FULL,
+		SenderPrivateKey:       sender.Key,
+		SenderDecryptedBalance: 1000,
+		Recipient:              recipientAddr.EncodeAddr(),
+		Amount:                 10,
+		Data:                   &wizard.WizardTransactionData{},
+		WitnessIndexes:         helpers.ShuffleArray_for_Zether(4),
+	}}
+
+	tx, err := wizard.CreateZetherTx(transfers, emap, map[string]bool{}, [][]*bn256.G1{{senderPoint, decoyPoint}}, [][]*bn256.G1{{recipientPoint, otherPoint}}, chainHeight, chainKernelHash, publicKeyIndexes, []*wizard.WizardTransactionFee{{}}, context.Background(), func(string) {})
+	assert.NoError(t, err)
+
+	return &mempoolTx{Tx: tx, conflictKeys: computeTxConflictKeys(tx)}
+}
+
+func TestComputeTxConflictKeys(t *testing.T) {
+
+	alice := addresses.GenerateNewPrivateKey()
+	bob := addresses.GenerateNewPrivateKey()
+	carol := addresses.GenerateNewPrivateKey()
+	chainKernelHash := helpers.RandomBytes(32)
+
+	aliceTx := createTestZetherTx(t, alice, bob, 0, chainKernelHash)
+	bobTx := createTestZetherTx(t, bob, carol, 0, chainKernelHash)
+
+	//the decoys are not spending anything
+	assert.Empty(t, getConflictingTxs([]*mempoolTx{aliceTx}, bobTx))
+	assert.Empty(t, getConflictingTxs([]*mempoolTx{bobTx}, aliceTx))
+
+	//independent chained payments of the same sender are using other rings, even if they were built against the same chain kernel hash
+	aliceTx2 := createTestZetherTx(t, alice, addresses.GenerateNewPrivateKey(), 0, chainKernelHash)
+	assert.Empty(t, getConflictingTxs([]*mempoolTx{aliceTx, bobTx}, aliceTx2))
+
+	//the replacement keeps the sender ring, even if a new block changed the chain kernel hash
+	replacement := createTestZetherTx(t, alice, bob, 1, helpers.RandomBytes(32))
+	assert.Equal(t, []*mempoolTx{aliceTx}, getConflictingTxs([]*mempoolTx{aliceTx, aliceTx2, bobTx}, replacement))
+
+	//the replacement of the replacement
+	replacement2 := createTestZetherTx(t, alice, bob, 1, helpers.RandomBytes(32))
+	assert.Equal(t, []*mempoolTx{replacement}, getConflictingTxs([]*mempoolTx{replacement, aliceTx2, bobTx}, replacement2))
+}
+
+func TestReplaceConflictingTxs(t *testing.T) {
+
+	tx := &mempoolTx{Tx: &transaction.Transaction{}, FeePerByte: 10}
+	conflicts := []*mempoolTx{{FeePerByte: 5}, {FeePerByte: 10}}
+
+	var includeErr error
+	include := func() error {
+		return includeErr
+	}
+	evicted := false
+	evict := func() {
+		evicted = true
+	}
+
+	assert.EqualError(t, replaceConflictingTxs(tx, []*mempoolTx{}, include, evict), "Transaction to be replaced was not found in mempool")
+	assert.False(t, evicted)
+
+	assert.Error(t, replaceConflictingTxs(tx, conflicts, include, evict))
+	assert.False(t, evicted)
+
+	//the explicitly replaced tx is not conflicting anymore
+	tx.FeePerByte = 11
+	tx.replaceTxHash = helpers.RandomBytes(32)
+	conflicts = []*mempoolTx{{Tx: &transaction.Transaction{Bloom: &transaction.TransactionBloom{Hash: helpers.RandomBytes(32)}}, FeePerByte: 5}}
+	assert.EqualError(t, replaceConflictingTxs(tx, conflicts, include, evict), "Transaction to be replaced was not found in mempool")
+	assert.False(t, evicted)
+
+	//the replacement can not be included
+	conflicts[0].Tx.Bloom.Hash = tx.replaceTxHash
+	includeErr = errors.New("Invalid")
+	assert.EqualError(t, replaceConflictingTxs(tx, conflicts, include, evict), "Invalid")
+	assert.False(t, evicted)
+
+	includeErr = nil
+	assert.NoError(t, replaceConflictingTxs(tx, conflicts, include, evict))
+	assert.True(t, evicted)
+}
@@ -0,0 +1,132 @@
+package mempool
+
+import (
+	"context"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"time"
+)
+
+func (mempool *Mempool) SaveTxsToStore() error {
+
+	if !mempool.loadedFromStore.IsSet() { //the stored txs were not processed yet
+		return nil
+	}
+
+	txs := mempool.Txs.GetTxsList()
+
+	return store.StoreMempool.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		//removing the txs that are no longer in the mempool
+		keys := make([]string, 0)
+		if err = writer.IteratePrefix("mempoolTx:", "", false, func(key string, value []byte) error {
+			keys = append(keys, key)
+			return nil
+		}); err != nil {
+			return
+		}
+		for _, key := range keys {
+			writer.Delete(key)
+		}
+
+		for _, tx := range txs {
+			w := helpers.NewBufferWriter()
+			w.WriteVariableBytes(tx.Tx.Bloom.Serialized)
+			w.WriteBool(tx.Mine)
+			writer.Put("mempoolTx:"+tx.Tx.Bloom.HashStr, w.Bytes())
+		}
+
+		return
+	})
+}
+
+//the stored txs are validated again against the current chain. Invalid txs are dropped
+func (mempool *Mempool) LoadTxsFromStore(chainHeight uint64) (err error) {
+
+	txs := make([]*transaction.Transaction, 0)
+	mine := make([]bool, 0)
+
+	if err = store.StoreMempool.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		return reader.IteratePrefix("mempoolTx:", "", false, func(key string, value []byte) error {
+
+			r := helpers.NewBufferReader(value)
+
+			//corrupted entries are dropped
+			var tx *transaction.Transaction
+			isMine := false
+
+			serialized, err := r.ReadVariableBytes(config.BLOCK_MAX_SIZE)
+			if err == nil {
+				isMine, err = r.ReadBool()
+			}
+			if err == nil {
+				tx = &transaction.Transaction{}
+				if err = tx.Deserialize(helpers.NewBufferReader(serialized)); err != nil {
+					tx = nil
+				}
+			}
+
+			txs = append(txs, tx)
+			mine = append(mine, isMine)
+			return nil
+		})
+	}); err != nil {
+		return
+	}
+
+	dropped := 0
+	for i, tx := range txs {
+
+		if tx == nil {
+			dropped++
+			continue
+		}
+
+		//our own txs are broadcasted again
+		exceptSocketUUID := advanced_connection_types.UUID_SKIP_ALL
+		if mine[i] {
+			exceptSocketUUID = advanced_connection_types.UUID_ALL
+		}
+
+		if err = mempool.AddTxToMempool(tx, chainHeight, mine[i], true, false, exceptSocketUUID, context.Background()); err != nil {
+			dropped++
+		}
+	}
+
+	gui.GUI.Log("Mempool loaded " + strconv.Itoa(len(txs)-dropped) + " txs. Dropped " + strconv.Itoa(dropped) + " invalid txs")
+
+	mempool.loadedFromStore.Set()
+
+	mempool.saveLoop.Add(1)
+	recovery.SafeGo(func() {
+		defer mempool.saveLoop.Done()
+		for {
+			select {
+			case <-mempool.closeCn:
+				return
+			case <-time.After(config.MEMPOOL_SAVE_INTERVAL):
+				if err := mempool.SaveTxsToStore(); err != nil {
+					gui.GUI.Error("Error saving mempool txs", err)
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+//Close stops saving periodically and saves the mempool txs one last time. It can be called more than once
+func (mempool *Mempool) Close() error {
+	if mempool.closed.SetToIf(false, true) {
+		close(mempool.closeCn)
+	}
+	mempool.saveLoop.Wait()
+	return mempool.SaveTxsToStore()
+}
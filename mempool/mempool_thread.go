@@ -26,8 +26,9 @@ type MempoolWorkerAddTx struct {
 }
 
 type MempoolWorkerRemoveTxs struct {
-	Txs    []string
-	Result chan<- bool
+	Txs                  []string
+	IncludedInBlockchain bool //false when the txs are evicted
+	Result               chan<- bool
 }
 
 type MempoolWorkerInsertTxs struct {
@@ -81,14 +82,43 @@ func (worker *mempoolWorker) processing(
 		}
 	}
 
-	removeTxs := func(data *MempoolWorkerRemoveTxs) {
+	//the replacement is answered once it was included in the work. The evicted txs are restored when it failed
+	finishReplacement := func(tx *mempoolTx, err error, restore bool) {
+
+		if tx.replacement == nil {
+			return
+		}
+
+		if err != nil && restore {
+			for _, replaced := range tx.replacement.replacedTxs {
+				if txsMap[replaced.Tx.Bloom.HashStr] == nil {
+					txsMap[replaced.Tx.Bloom.HashStr] = replaced
+					txsList = append(txsList, replaced)
+					txs.insertTx(replaced)
+					txs.inserted(replaced)
+				}
+			}
+		}
+
+		if tx.replacement.result != nil {
+			tx.replacement.result <- err
+		}
+		tx.replacement = nil
+	}
+
+	removeTxsNow := func(hashes []string, includedInBlockchain bool) bool {
 
 		removedTxsMap := make(map[string]bool)
-		for _, hash := range data.Txs {
+		for _, hash := range hashes {
 			if hash != "" {
 				if tx := txsMap[hash]; tx != nil {
 					removedTxsMap[hash] = true
-					removeTxNow(tx, true, true)
+					removeTxNow(tx, true, includedInBlockchain)
+					if includedInBlockchain {
+						finishReplacement(tx, nil, false)
+					} else {
+						finishReplacement(tx, errors.New("Transaction was evicted from mempool"), false)
+					}
 				}
 			}
 		}
@@ -115,9 +145,79 @@ func (worker *mempoolWorker) processing(
 				index++
 			}
 			txsList = newList
+
+			//evicted txs could have been already included in the work
+			if !includedInBlockchain && work != nil {
+				dataStorage = nil
+				listIndex = 0
+				includedTotalSize = 0
+				includedTxs = []*mempoolTx{}
+				atomic.StoreUint64(&work.result.totalSize, includedTotalSize)
+				work.result.txs.Store(includedTxs)
+			}
+		}
+
+		return len(removedTxsMap) > 0
+	}
+
+	removeTxs := func(data *MempoolWorkerRemoveTxs) {
+		data.Result <- removeTxsNow(data.Txs, data.IncludedInBlockchain)
+	}
+
+	//the replacement is included on the work state without the conflicts. Nothing is evicted if it fails
+	includeReplacement := func(tx *mempoolTx, conflicts []*mempoolTx, dbTx store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if dbTx.Exists("txHash:" + string(tx.Tx.Bloom.HashStr)) {
+			return errors.New("Tx is already included in blockchain")
+		}
+
+		defer func() {
+			if errReturned := recover(); errReturned != nil {
+				err = errReturned.(error)
+			}
+		}()
+
+		conflictsMap := make(map[string]bool)
+		for _, conflict := range conflicts {
+			conflictsMap[conflict.Tx.Bloom.HashStr] = true
+		}
+
+		replacementStorage := data_storage.NewDataStorage(dbTx)
+		for _, included := range includedTxs {
+			if conflictsMap[included.Tx.Bloom.HashStr] {
+				continue
+			}
+			if err = included.Tx.IncludeTransaction(work.chainHeight, replacementStorage); err != nil {
+				replacementStorage.Rollback() //it depends on the conflicts
+			} else if err = replacementStorage.CommitChanges(); err != nil {
+				return
+			}
 		}
 
-		data.Result <- len(removedTxsMap) > 0
+		err = tx.Tx.IncludeTransaction(work.chainHeight, replacementStorage)
+		replacementStorage.Rollback()
+		return
+	}
+
+	//the eviction resets the work, so the replacement is included again from the list
+	replaceTxs := func(data *MempoolWorkerAddTx, conflicts []*mempoolTx, dbTx store_db_interface.StoreDBTransactionInterface) error {
+		tx := data.Tx
+		return replaceConflictingTxs(tx, conflicts, func() error {
+			return includeReplacement(tx, conflicts, dbTx)
+		}, func() {
+
+			hashes := make([]string, len(conflicts))
+			for i, conflict := range conflicts {
+				hashes[i] = conflict.Tx.Bloom.HashStr
+			}
+			removeTxsNow(hashes, false)
+
+			tx.replacement = &mempoolReplacement{conflicts, data.Result}
+			txsList = append(txsList, tx)
+			txsMap[tx.Tx.Bloom.HashStr] = tx
+			txs.insertTx(tx)
+			txs.inserted(tx)
+		})
 	}
 
 	insertTxs := func(data *MempoolWorkerInsertTxs) {
@@ -204,6 +304,13 @@ func (worker *mempoolWorker) processing(
 							}
 							continue
 						}
+						//a tx spending the same nonce or replacing a pending tx evicts the conflicting txs only if it pays more
+						if conflicts := getConflictingTxs(txsList, tx); len(conflicts) > 0 || tx.replaceTxHash != nil {
+							if err := replaceTxs(newAddTx, conflicts, dbTx); err != nil && newAddTx.Result != nil {
+								newAddTx.Result <- err
+							}
+							continue
+						}
 					}
 				} else {
 					select {
@@ -287,6 +394,10 @@ func (worker *mempoolWorker) processing(
 						removeTxNow(tx, newAddTx == nil, exists)
 					}
 
+					if newAddTx == nil {
+						finishReplacement(tx, finalErr, !exists)
+					}
+
 					if newAddTx != nil && newAddTx.Result != nil {
 						newAddTx.Result <- finalErr
 					}
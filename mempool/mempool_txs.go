@@ -142,6 +142,16 @@ func (self *MempoolTxs) GetTxsOnlyList() []*transaction.Transaction {
 	return out
 }
 
+//returns the number of transactions and their total size in bytes
+func (self *MempoolTxs) GetCountAndSize() (count int, size uint64) {
+	self.txsMap.Range(func(key string, value *mempoolTx) bool {
+		count += 1
+		size += value.Tx.Bloom.Size
+		return true
+	})
+	return
+}
+
 func (self *MempoolTxs) Exists(txId string) bool {
 	_, loaded := self.txsMap.Load(txId)
 	return loaded
@@ -10,6 +10,7 @@ import (
 	"pandora-pay/mempool"
 	"pandora-pay/network/api/api_common/api_delegator_node"
 	"pandora-pay/network/api/api_common/api_faucet"
+	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/known_nodes"
 	"pandora-pay/recovery"
 	"pandora-pay/txs_builder"
@@ -31,6 +32,7 @@ type APICommon struct {
 	chain                     *blockchain.Blockchain
 	wallet                    *wallet.Wallet
 	knownNodes                *known_nodes.KnownNodes
+	bannedNodes               *banned_nodes.BannedNodes
 	localChain                *generics.Value[*APIBlockchain]
 	localChainSync            *generics.Value[*blockchain_sync.BlockchainSyncData]
 	Faucet                    *api_faucet.Faucet
@@ -39,6 +41,8 @@ type APICommon struct {
 	mempoolProcessedThisBlock *generics.Value[*generics.Map[string, *mempoolNewTxReply]]
 	temporaryList             *generics.Value[*APINetworkNodesReply]
 	temporaryListCreation     *generics.Value[time.Time]
+	feeEstimateBlocks         *generics.Map[uint64, *feeEstimateBlock]
+	Methods                   []*APIMethod
 }
 
 //make sure it is safe to read
@@ -65,7 +69,7 @@ func (api *APICommon) readLocalBlockchainSync(newLocalSync *blockchain_sync.Bloc
 	api.localChainSync.Store(newLocalSync)
 }
 
-func NewAPICommon(knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool, chain *blockchain.Blockchain, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder, apiStore *APIStore) (api *APICommon, err error) {
+func NewAPICommon(knownNodes *known_nodes.KnownNodes, bannedNodes *banned_nodes.BannedNodes, mempool *mempool.Mempool, chain *blockchain.Blockchain, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder, apiStore *APIStore) (api *APICommon, err error) {
 
 	var faucet *api_faucet.Faucet
 	if config.NETWORK_SELECTED == config.TEST_NET_NETWORK_BYTE || config.NETWORK_SELECTED == config.DEV_NET_NETWORK_BYTE {
@@ -86,6 +90,7 @@ func NewAPICommon(knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool,
 		chain,
 		wallet,
 		knownNodes,
+		bannedNodes,
 		&generics.Value[*APIBlockchain]{},
 		&generics.Value[*blockchain_sync.BlockchainSyncData]{},
 		faucet,
@@ -94,8 +99,12 @@ func NewAPICommon(knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool,
 		&generics.Value[*generics.Map[string, *mempoolNewTxReply]]{},
 		&generics.Value[*APINetworkNodesReply]{},
 		&generics.Value[time.Time]{},
+		&generics.Map[uint64, *feeEstimateBlock]{},
+		nil,
 	}
 
+	api.initMethods()
+
 	api.temporaryListCreation.Store(time.Now())
 
 	api.mempoolProcessedThisBlock.Store(&generics.Map[string, *mempoolNewTxReply]{})
@@ -119,6 +128,21 @@ func NewAPICommon(knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool,
 		}
 	})
 
+	recovery.SafeGo(func() {
+
+		updateNewChainUpdateListener := api.chain.UpdateNewChainUpdate.AddListener()
+		defer api.chain.UpdateNewChainUpdate.RemoveChannel(updateNewChainUpdateListener)
+
+		for {
+			newChainUpdate, ok := <-updateNewChainUpdateListener
+			if !ok {
+				return
+			}
+
+			api.updateFeeEstimateBlocks(newChainUpdate)
+		}
+	})
+
 	recovery.SafeGo(func() {
 		updateNewSync := api.chain.Sync.UpdateSyncMulticast.AddListener()
 		defer api.chain.Sync.UpdateSyncMulticast.RemoveChannel(updateNewSync)
@@ -0,0 +1,49 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/blockchain/indexer"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type APIAccountHistoryRequest struct {
+	api_types.APIAccountBaseRequest
+	StartHeight uint64                                                `json:"startHeight,omitempty" msgpack:"startHeight,omitempty"`
+	EndHeight   uint64                                                `json:"endHeight,omitempty" msgpack:"endHeight,omitempty"` //0 means until the end of the chain
+	Asset       helpers.Base64                                        `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	Scripts     []transaction_zether_payload_script.PayloadScriptType `json:"scripts,omitempty" msgpack:"scripts,omitempty"`
+	Dsc         bool                                                  `json:"dsc,omitempty" msgpack:"dsc,omitempty"`
+	Next        helpers.Base64                                        `json:"next,omitempty" msgpack:"next,omitempty"`
+	Limit       int                                                   `json:"limit,omitempty" msgpack:"limit,omitempty"`
+}
+
+type APIAccountHistoryReply struct {
+	Txs  []*indexer.IndexerTx `json:"txs" msgpack:"txs"`
+	Next helpers.Base64       `json:"next,omitempty" msgpack:"next,omitempty"`
+}
+
+func (api *APICommon) GetAccountHistory(r *http.Request, args *APIAccountHistoryRequest, reply *APIAccountHistoryReply) (err error) {
+
+	publicKey, err := args.GetPublicKey(true)
+	if err != nil {
+		return
+	}
+
+	limit := config.API_ACCOUNT_HISTORY_MAX_TXS
+	if args.Limit > 0 {
+		limit = generics.Min(args.Limit, config.API_ACCOUNT_HISTORY_MAX_TXS)
+	}
+
+	filter := &indexer.IndexerHistoryFilter{args.StartHeight, args.EndHeight, args.Asset, args.Scripts, args.Dsc, args.Next, limit}
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		reply.Txs, reply.Next, err = indexer.GetHistory(reader, publicKey, filter)
+		return
+	})
+}
@@ -33,7 +33,7 @@ func (api *APICommon) GetBlock(r *http.Request, args *APIBlockRequest, reply *AP
 			}
 		}
 
-		if reply.Block, err = api.ApiStore.loadBlock(reader, args.Hash); err != nil || reply.Block == nil {
+		if reply.Block, err = api.ApiStore.LoadBlock(reader, args.Hash); err != nil || reply.Block == nil {
 			return helpers.ReturnErrorIfNot(err, "Block was not found")
 		}
 
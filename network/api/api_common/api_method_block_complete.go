@@ -33,10 +33,14 @@ func (api *APICommon) GetBlockComplete(r *http.Request, args *APIBlockCompleteRe
 
 		reply.BlockComplete = &block_complete.BlockComplete{}
 
-		if reply.BlockComplete.Block, err = api.ApiStore.loadBlock(reader, args.Hash); err != nil || reply.BlockComplete.Block == nil {
+		if reply.BlockComplete.Block, err = api.ApiStore.LoadBlock(reader, args.Hash); err != nil || reply.BlockComplete.Block == nil {
 			return helpers.ReturnErrorIfNot(err, "Block was not found")
 		}
 
+		if api.ApiStore.chain.IsBlockPruned(reader, reply.BlockComplete.Block.Height) {
+			return errors.New("Block data was pruned")
+		}
+
 		data := reader.Get("blockTxs" + strconv.FormatUint(reply.BlockComplete.Block.Height, 10))
 		if data == nil {
 			return errors.New("Strange. blockTxs was not found")
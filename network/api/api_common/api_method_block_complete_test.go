@@ -0,0 +1,54 @@
+package api_common
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestPrunedBlockAndTx(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("blockchain")
+	assert.Nil(t, err)
+	store.StoreBlockchain = &store.Store{Name: "blockchain", Opened: true, DB: db}
+
+	blk := &block.Block{
+		BlockHeader:    &block.BlockHeader{Version: 0, Height: 1},
+		MerkleHash:     cryptography.SHA3([]byte("MerkleHash")),
+		PrevHash:       cryptography.SHA3([]byte("PrevHash")),
+		PrevKernelHash: cryptography.SHA3([]byte("PrevKernelHash")),
+		StakingNonce:   cryptography.SHA3([]byte("StakingNonce")),
+	}
+	blkHash := cryptography.SHA3(blk.SerializeManualToBytes())
+	txHash := helpers.RandomBytes(cryptography.HashSize)
+
+	//the header and the tx hash are kept for the blocks below the pruned height
+	assert.Nil(t, store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Put("block_ByHash"+string(blkHash), blk.SerializeManualToBytes())
+		writer.Put("blockHash_ByHeight1", blkHash)
+		writer.Put("txHash:"+string(txHash), []byte{1})
+		writer.Put("txHash_ByHeight0", txHash)
+
+		buf := make([]byte, binary.MaxVarintLen64)
+		writer.Put("chainPrunedHeight", buf[:binary.PutUvarint(buf, 5)])
+		return nil
+	}))
+
+	api := &APICommon{ApiStore: NewAPIStore(&blockchain.Blockchain{})}
+
+	assert.EqualError(t, api.GetBlockComplete(nil, &APIBlockCompleteRequest{Height: 1}, &APIBlockCompleteReply{}), "Block data was pruned")
+	assert.EqualError(t, api.GetBlockComplete(nil, &APIBlockCompleteRequest{Hash: blkHash}, &APIBlockCompleteReply{}), "Block data was pruned")
+
+	assert.EqualError(t, api.openLoadTx(&APITxRequest{Hash: txHash}, &APITxReply{}), "Tx data was pruned")
+	assert.EqualError(t, api.openLoadTx(&APITxRequest{Height: 0}, &APITxReply{}), "Tx data was pruned")
+	assert.EqualError(t, api.openLoadTxOnly(&APITxRawRequest{Hash: txHash}, &APITxRawReply{}), "Tx data was pruned")
+
+	assert.EqualError(t, api.openLoadTx(&APITxRequest{Hash: helpers.RandomBytes(cryptography.HashSize)}, &APITxReply{}), "Tx not found")
+}
@@ -0,0 +1,31 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+)
+
+type APIChainRollbackRequest struct {
+	Height uint64 `json:"height" msgpack:"height"`
+}
+
+type APIChainRollbackReply struct {
+	Height uint64 `json:"height" msgpack:"height"`
+	Hash   []byte `json:"hash" msgpack:"hash"`
+}
+
+func (api *APICommon) ChainRollback(r *http.Request, args *APIChainRollbackRequest, reply *APIChainRollbackReply, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err := api.chain.RollbackToHeight(args.Height); err != nil {
+		return err
+	}
+
+	chainData := api.chain.GetChainData()
+	reply.Height = chainData.Height
+	reply.Hash = chainData.Hash
+	return nil
+}
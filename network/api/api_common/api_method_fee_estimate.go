@@ -0,0 +1,180 @@
+package api_common
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_fees"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+)
+
+type APIFeeEstimateRequest struct {
+	Asset  helpers.Base64 `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	Blocks uint64         `json:"blocks,omitempty" msgpack:"blocks,omitempty"`
+}
+
+type APIFeeEstimateReply struct {
+	Asset        []byte `json:"asset" msgpack:"asset"`
+	Fast         uint64 `json:"fast" msgpack:"fast"` //fee per byte in asset units
+	Normal       uint64 `json:"normal" msgpack:"normal"`
+	Slow         uint64 `json:"slow" msgpack:"slow"`
+	Rate         uint64 `json:"rate,omitempty" msgpack:"rate,omitempty"` //asset fee liquidity used to convert the fees for non native assets
+	LeadingZeros byte   `json:"leadingZeros,omitempty" msgpack:"leadingZeros,omitempty"`
+	Samples      int    `json:"samples" msgpack:"samples"`
+}
+
+//returns the native fee per byte of a zether tx and the assets of its payloads. The fee paid for the extra space is not a fee per byte
+func getFeeEstimateSample(tx *transaction.Transaction) (uint64, [][]byte, bool) {
+
+	if tx.Version != transaction_type.TX_ZETHER {
+		return 0, nil, false
+	}
+
+	assets := make([][]byte, 0)
+	for _, payload := range tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads {
+		if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING || payload.PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING_REWARD {
+			return 0, nil, false
+		}
+		assets = append(assets, payload.Asset)
+	}
+
+	fee, err := tx.GetAllFee()
+	if err != nil {
+		return 0, nil, false
+	}
+	if err = helpers.SafeUint64Sub(&fee, tx.SpaceExtra*config_fees.FEE_PER_BYTE_EXTRA_SPACE); err != nil {
+		return 0, nil, false
+	}
+
+	return fee / tx.Bloom.Size, assets, true
+}
+
+//the samples of a tx are counted once per asset
+func getFeeEstimateSamples(txs []*transaction.Transaction) map[string][]uint64 {
+
+	out := make(map[string][]uint64)
+	for _, tx := range txs {
+		sample, assets, ok := getFeeEstimateSample(tx)
+		if !ok {
+			continue
+		}
+		added := make(map[string]bool)
+		for _, asset := range assets {
+			if !added[string(asset)] {
+				added[string(asset)] = true
+				out[string(asset)] = append(out[string(asset)], sample)
+			}
+		}
+	}
+
+	return out
+}
+
+func getFeeEstimatePercentile(samples []uint64, percentile int) uint64 {
+	out := samples[(len(samples)-1)*percentile/100]
+	if out < config_fees.FEE_PER_BYTE_ZETHER {
+		return config_fees.FEE_PER_BYTE_ZETHER
+	}
+	return out
+}
+
+func (api *APICommon) GetFeeEstimate(r *http.Request, args *APIFeeEstimateRequest, reply *APIFeeEstimateReply) error {
+
+	if len(args.Asset) == 0 {
+		args.Asset = config_coins.NATIVE_ASSET_FULL
+	}
+	if len(args.Asset) != config_coins.ASSET_LENGTH {
+		return errors.New("Invalid asset")
+	}
+
+	if args.Blocks == 0 {
+		args.Blocks = config.API_FEE_ESTIMATE_BLOCKS
+	}
+	if args.Blocks > config.API_FEE_ESTIMATE_MAX_BLOCKS {
+		return errors.New("Too many blocks")
+	}
+
+	samples := getFeeEstimateSamples(api.mempool.Txs.GetTxsOnlyList())[string(args.Asset)]
+
+	chainHeight := api.chain.GetChainData().Height
+
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		start := uint64(0)
+		if chainHeight > args.Blocks {
+			start = chainHeight - args.Blocks
+		}
+
+		for height := start; height < chainHeight; height++ {
+
+			var feeBlock *feeEstimateBlock
+			if feeBlock, err = api.getFeeEstimateBlock(reader, height); err != nil {
+				return
+			}
+			if feeBlock != nil {
+				samples = append(samples, feeBlock.samples[string(args.Asset)]...)
+			}
+		}
+
+		if !bytes.Equal(args.Asset, config_coins.NATIVE_ASSET_FULL) {
+
+			dataStorage := data_storage.NewDataStorage(reader)
+
+			var plainAcc *plain_account.PlainAccount
+			if plainAcc, err = dataStorage.GetWhoHasAssetTopLiquidity(args.Asset); err != nil || plainAcc == nil {
+				return helpers.ReturnErrorIfNot(err, "There is no Asset Fee Liquidity Available")
+			}
+
+			var liquidity *asset_fee_liquidity.AssetFeeLiquidity
+			if liquidity = plainAcc.AssetFeeLiquidities.GetLiquidity(args.Asset); liquidity == nil || liquidity.Rate == 0 {
+				return errors.New("There is no Asset Fee Liquidity Available")
+			}
+
+			reply.Rate = liquidity.Rate
+			reply.LeadingZeros = liquidity.LeadingZeros
+		}
+
+		return
+	}); err != nil {
+		return err
+	}
+
+	reply.Asset = args.Asset
+	reply.Samples = len(samples)
+
+	if len(samples) == 0 {
+		reply.Fast, reply.Normal, reply.Slow = config_fees.FEE_PER_BYTE_ZETHER, config_fees.FEE_PER_BYTE_ZETHER, config_fees.FEE_PER_BYTE_ZETHER
+	} else {
+		sort.Slice(samples, func(i, j int) bool {
+			return samples[i] < samples[j]
+		})
+		reply.Fast = getFeeEstimatePercentile(samples, 90)
+		reply.Normal = getFeeEstimatePercentile(samples, 50)
+		reply.Slow = getFeeEstimatePercentile(samples, 10)
+	}
+
+	//native fee = asset fee * rate / 10^leadingZeros
+	if reply.Rate > 0 {
+		for _, fee := range []*uint64{&reply.Fast, &reply.Normal, &reply.Slow} {
+			value := *fee
+			if err := helpers.SafeUint64Mul(&value, helpers.Pow10(reply.LeadingZeros)); err != nil {
+				return err
+			}
+			*fee = (value + reply.Rate - 1) / reply.Rate
+		}
+	}
+
+	return nil
+}
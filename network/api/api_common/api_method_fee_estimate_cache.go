@@ -0,0 +1,76 @@
+package api_common
+
+import (
+	"bytes"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+//fee samples of a block grouped by asset
+type feeEstimateBlock struct {
+	hash    []byte
+	samples map[string][]uint64
+}
+
+//the cached block is used only if it is still the block at this height, otherwise it is read again from the store. Pruned blocks have no samples
+func (api *APICommon) getFeeEstimateBlock(reader store_db_interface.StoreDBTransactionInterface, height uint64) (*feeEstimateBlock, error) {
+
+	heightStr := strconv.FormatUint(height, 10)
+
+	hash := reader.Get("blockHash_ByHeight" + heightStr)
+	if hash == nil {
+		return nil, nil
+	}
+
+	if feeBlock, ok := api.feeEstimateBlocks.Load(height); ok && bytes.Equal(feeBlock.hash, hash) {
+		return feeBlock, nil
+	}
+
+	data := reader.Get("blockTxs" + heightStr)
+	if data == nil { //pruned
+		return nil, nil
+	}
+
+	txHashes := [][]byte{}
+	if err := msgpack.Unmarshal(data, &txHashes); err != nil {
+		return nil, err
+	}
+
+	txs := make([]*transaction.Transaction, len(txHashes))
+	for i, txHash := range txHashes {
+		txs[i] = &transaction.Transaction{}
+		if err := txs[i].Deserialize(helpers.NewBufferReader(reader.Get("tx:" + string(txHash)))); err != nil {
+			return nil, err
+		}
+	}
+
+	feeBlock := &feeEstimateBlock{helpers.CloneBytes(hash), getFeeEstimateSamples(txs)}
+	api.feeEstimateBlocks.Store(height, feeBlock)
+
+	return feeBlock, nil
+}
+
+//the blocks removed by a rollback and the blocks too old to be estimated are dropped from the cache
+func (api *APICommon) updateFeeEstimateBlocks(update *blockchain_types.BlockchainUpdates) {
+
+	removedHeight := update.BlockHeight
+	if len(update.InsertedBlocks) > 0 {
+		removedHeight = update.InsertedBlocks[0].Height
+	}
+
+	api.feeEstimateBlocks.Range(func(height uint64, feeBlock *feeEstimateBlock) bool {
+		if height >= removedHeight || height+config.API_FEE_ESTIMATE_MAX_BLOCKS < update.BlockHeight {
+			api.feeEstimateBlocks.Delete(height)
+		}
+		return true
+	})
+
+	for _, blkComplete := range update.InsertedBlocks {
+		api.feeEstimateBlocks.Store(blkComplete.Height, &feeEstimateBlock{blkComplete.Bloom.Hash, getFeeEstimateSamples(blkComplete.Txs)})
+	}
+}
@@ -0,0 +1,77 @@
+package api_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"strconv"
+	"testing"
+)
+
+func TestFeeEstimateBlocksCache(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("blockchain")
+	assert.Nil(t, err)
+	store.StoreBlockchain = &store.Store{Name: "blockchain", Opened: true, DB: db}
+
+	hashes := make([][]byte, 3)
+	assert.Nil(t, store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		txs, err := msgpack.Marshal([][]byte{})
+		assert.Nil(t, err)
+		for height := range hashes {
+			hashes[height] = helpers.RandomBytes(cryptography.HashSize)
+			writer.Put("blockHash_ByHeight"+strconv.Itoa(height), hashes[height])
+			if height > 0 { //the block 0 was pruned
+				writer.Put("blockTxs"+strconv.Itoa(height), txs)
+			}
+		}
+		return nil
+	}))
+
+	api := &APICommon{feeEstimateBlocks: &generics.Map[uint64, *feeEstimateBlock]{}}
+
+	cached := &feeEstimateBlock{hashes[1], map[string][]uint64{"asset": {5}}}
+	api.feeEstimateBlocks.Store(1, cached)
+	api.feeEstimateBlocks.Store(2, &feeEstimateBlock{helpers.RandomBytes(cryptography.HashSize), map[string][]uint64{"asset": {5}}})
+
+	assert.Nil(t, store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		feeBlock, err := api.getFeeEstimateBlock(reader, 0)
+		assert.Nil(t, err)
+		assert.Nil(t, feeBlock)
+
+		//the cached samples are used only for the same block
+		feeBlock, err = api.getFeeEstimateBlock(reader, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, cached, feeBlock)
+
+		feeBlock, err = api.getFeeEstimateBlock(reader, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, hashes[2], feeBlock.hash)
+		assert.Empty(t, feeBlock.samples)
+
+		feeBlock, err = api.getFeeEstimateBlock(reader, 3)
+		assert.Nil(t, err)
+		assert.Nil(t, feeBlock)
+		return nil
+	}))
+
+	//rollback to the height 2
+	api.updateFeeEstimateBlocks(&blockchain_types.BlockchainUpdates{BlockHeight: 2})
+	_, ok := api.feeEstimateBlocks.Load(2)
+	assert.False(t, ok)
+	_, ok = api.feeEstimateBlocks.Load(1)
+	assert.True(t, ok)
+
+	//the blocks too old to be estimated are dropped
+	api.updateFeeEstimateBlocks(&blockchain_types.BlockchainUpdates{BlockHeight: config.API_FEE_ESTIMATE_MAX_BLOCKS + 2})
+	_, ok = api.feeEstimateBlocks.Load(1)
+	assert.False(t, ok)
+}
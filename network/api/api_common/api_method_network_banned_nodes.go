@@ -0,0 +1,71 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/network/banned_nodes"
+	"time"
+)
+
+type APINetworkBannedNodesReply struct {
+	BannedNodes []*banned_nodes.BannedNode `json:"bannedNodes" msgpack:"bannedNodes"`
+}
+
+type APINetworkBanNodeRequest struct {
+	URL      string `json:"url" msgpack:"url"` //url, ip or subnet (CIDR)
+	Message  string `json:"message" msgpack:"message"`
+	Duration uint64 `json:"duration" msgpack:"duration"` //seconds
+}
+
+type APINetworkBanNodeReply struct {
+	BannedNode *banned_nodes.BannedNode `json:"bannedNode" msgpack:"bannedNode"`
+}
+
+type APINetworkUnbanNodeRequest struct {
+	URL string `json:"url" msgpack:"url"`
+}
+
+type APINetworkUnbanNodeReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) GetNetworkBannedNodes(r *http.Request, args *struct{}, reply *APINetworkBannedNodesReply, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.BannedNodes = api.bannedNodes.GetList()
+	return nil
+}
+
+func (api *APICommon) NetworkBanNode(r *http.Request, args *APINetworkBanNodeRequest, reply *APINetworkBanNodeReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if args.Duration == 0 {
+		return errors.New("Duration must be positive")
+	}
+	if args.Duration > uint64(banned_nodes.BAN_MAX_DURATION/time.Second) {
+		return errors.New("Duration is too long")
+	}
+
+	reply.BannedNode, err = api.bannedNodes.Ban(args.URL, args.Message, time.Duration(args.Duration)*time.Second, true)
+	return
+}
+
+func (api *APICommon) NetworkUnbanNode(r *http.Request, args *APINetworkUnbanNodeRequest, reply *APINetworkUnbanNodeReply, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err := api.bannedNodes.Unban(args.URL); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
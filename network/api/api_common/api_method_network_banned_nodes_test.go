@@ -0,0 +1,38 @@
+package api_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/network/banned_nodes"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestNetworkBanNode(t *testing.T) {
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.Nil(t, err)
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+
+	api := &APICommon{}
+	api.bannedNodes, err = banned_nodes.NewBannedNodes()
+	assert.Nil(t, err)
+
+	reply := &APINetworkBanNodeReply{}
+	assert.EqualError(t, api.NetworkBanNode(nil, &APINetworkBanNodeRequest{"10.0.0.1", "", 60}, reply, false), "Invalid User or Password")
+	assert.EqualError(t, api.NetworkBanNode(nil, &APINetworkBanNodeRequest{"10.0.0.1", "", 0}, reply, true), "Duration must be positive")
+
+	//it would overflow time.Duration
+	assert.EqualError(t, api.NetworkBanNode(nil, &APINetworkBanNodeRequest{"10.0.0.1", "", 1 << 62}, reply, true), "Duration is too long")
+	assert.False(t, api.bannedNodes.IsBanned("10.0.0.1"))
+
+	assert.Nil(t, api.NetworkBanNode(nil, &APINetworkBanNodeRequest{"10.0.0.1", "spam", 60}, reply, true))
+	assert.True(t, api.bannedNodes.IsBanned("10.0.0.1"))
+	assert.True(t, reply.BannedNode.Persistent)
+}
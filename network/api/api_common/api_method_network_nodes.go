@@ -12,8 +12,21 @@ import (
 )
 
 type APINetworkNode struct {
-	URL   string `json:"url" msgpack:"url"`
-	Score int    `json:"score" msgpack:"score"`
+	URL      string `json:"url" msgpack:"url"`
+	Score    int    `json:"score" msgpack:"score"`
+	LastSeen int64  `json:"lastSeen,omitempty" msgpack:"lastSeen,omitempty"` //unix timestamp of the last connection
+	Failures int32  `json:"failures,omitempty" msgpack:"failures,omitempty"`
+	Version  string `json:"version,omitempty" msgpack:"version,omitempty"`
+}
+
+func newAPINetworkNode(knownNode *known_nodes.KnownNodeScored) *APINetworkNode {
+	return &APINetworkNode{
+		knownNode.URL,
+		int(atomic.LoadInt32(&knownNode.Score)),
+		atomic.LoadInt64(&knownNode.LastSeen),
+		atomic.LoadInt32(&knownNode.Failures),
+		knownNode.GetVersion(),
+	}
 }
 
 type APINetworkNodesReply struct {
@@ -43,6 +56,9 @@ func (api *APICommon) GetList(reply *APINetworkNodesReply) (err error) {
 			newTemporaryList.Nodes[0] = &APINetworkNode{
 				config.NETWORK_ADDRESS_URL_STRING,
 				3000,
+				now.Unix(),
+				0,
+				config.VERSION_STRING,
 			}
 			index = 1
 			includedMap[config.NETWORK_ADDRESS_URL_STRING] = true
@@ -73,10 +89,7 @@ func (api *APICommon) GetList(reply *APINetworkNodesReply) (err error) {
 			if !includedMap[string(element.Key)] {
 
 				node := allKnowNodes[string(element.Key)]
-				newTemporaryList.Nodes[index] = &APINetworkNode{
-					node.URL,
-					int(atomic.LoadInt32(&node.Score)),
-				}
+				newTemporaryList.Nodes[index] = newAPINetworkNode(node)
 				includedMap[node.URL] = true
 				index += 1
 			}
@@ -93,10 +106,7 @@ func (api *APICommon) GetList(reply *APINetworkNodesReply) (err error) {
 				} else {
 					includedMap[node.URL] = true
 
-					newTemporaryList.Nodes[index] = &APINetworkNode{
-						node.URL,
-						int(atomic.LoadInt32(&node.Score)),
-					}
+					newTemporaryList.Nodes[index] = newAPINetworkNode(node)
 					index += 1
 					break
 				}
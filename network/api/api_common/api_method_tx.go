@@ -42,6 +42,9 @@ func (api *APICommon) openLoadTx(args *APITxRequest, reply *APITxReply) error {
 		var data []byte
 
 		if data = reader.Get("tx:" + hashStr); data == nil {
+			if reader.Exists("txHash:" + hashStr) {
+				return errors.New("Tx data was pruned")
+			}
 			return errors.New("Tx not found")
 		}
 
@@ -30,6 +30,9 @@ func (api *APICommon) openLoadTxOnly(args *APITxRawRequest, reply *APITxRawReply
 		hashStr := string(args.Hash)
 
 		if reply.Tx = reader.Get("tx:" + hashStr); reply.Tx == nil {
+			if reader.Exists("txHash:" + hashStr) {
+				return errors.New("Tx data was pruned")
+			}
 			return errors.New("Tx not found")
 		}
 
@@ -0,0 +1,87 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/wallet/wallet_invoice"
+	"time"
+)
+
+type APIWalletInvoice struct {
+	*wallet_invoice.WalletInvoice
+	Status string `json:"status" msgpack:"status"`
+}
+
+type APIWalletInvoicesReply struct {
+	Invoices []*APIWalletInvoice `json:"invoices" msgpack:"invoices"`
+}
+
+type APIWalletCreateInvoiceRequest struct {
+	api_types.APIAccountBaseRequest
+	Amount uint64         `json:"amount" msgpack:"amount"`
+	Asset  helpers.Base64 `json:"asset" msgpack:"asset"`
+	Memo   string         `json:"memo" msgpack:"memo"`
+	Expiry uint64         `json:"expiry" msgpack:"expiry"` //unix timestamp. 0 means it never expires
+}
+
+type APIWalletInvoiceStatusRequest struct {
+	PaymentID helpers.Base64 `json:"paymentID" msgpack:"paymentID"`
+}
+
+func newAPIWalletInvoice(invoice *wallet_invoice.WalletInvoice, now uint64) *APIWalletInvoice {
+	return &APIWalletInvoice{invoice, invoice.GetStatus(now).String()}
+}
+
+func (api *APICommon) GetWalletInvoices(r *http.Request, args *struct{}, reply *APIWalletInvoicesReply, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	now := uint64(time.Now().Unix())
+	invoices := api.wallet.GetInvoices()
+
+	reply.Invoices = make([]*APIWalletInvoice, len(invoices))
+	for i, invoice := range invoices {
+		reply.Invoices[i] = newAPIWalletInvoice(invoice, now)
+	}
+
+	return nil
+}
+
+func (api *APICommon) WalletCreateInvoice(r *http.Request, args *APIWalletCreateInvoiceRequest, reply *APIWalletInvoice, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	publicKey, err := args.GetPublicKey(true)
+	if err != nil {
+		return err
+	}
+
+	invoice, err := api.wallet.CreateInvoice(publicKey, args.Amount, args.Asset, args.Memo, args.Expiry)
+	if err != nil {
+		return err
+	}
+
+	*reply = *newAPIWalletInvoice(invoice, uint64(time.Now().Unix()))
+	return nil
+}
+
+func (api *APICommon) GetWalletInvoiceStatus(r *http.Request, args *APIWalletInvoiceStatusRequest, reply *APIWalletInvoice, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	invoice := api.wallet.GetInvoice(args.PaymentID)
+	if invoice == nil {
+		return errors.New("Invoice was not found")
+	}
+
+	*reply = *newAPIWalletInvoice(invoice, uint64(time.Now().Unix()))
+	return nil
+}
@@ -0,0 +1,140 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/blockchain/blockchain_sync"
+	"pandora-pay/blockchain/info"
+	"pandora-pay/config"
+	"pandora-pay/network/api/api_common/api_delegator_node"
+	"pandora-pay/network/api/api_common/api_faucet"
+	"reflect"
+)
+
+//method exposed by all the transports (HTTP, JSON-RPC and Websockets)
+type APIMethod struct {
+	Name          string
+	Summary       string
+	Authenticated bool
+	Post          bool //HTTP accepts it only using POST
+	Params        reflect.Type
+	Result        reflect.Type
+	NewArgs       func() any //returns a pointer to a new Params
+	Call          func(args any, authenticated bool) (any, error)
+}
+
+func newMethod[T any, B any](name, summary string, callback func(r *http.Request, args *T, reply *B) error) *APIMethod {
+	return &APIMethod{
+		name,
+		summary,
+		false,
+		false,
+		reflect.TypeOf((*T)(nil)).Elem(),
+		reflect.TypeOf((*B)(nil)).Elem(),
+		func() any { return new(T) },
+		func(args any, authenticated bool) (any, error) {
+			reply := new(B)
+			return reply, callback(nil, args.(*T), reply)
+		},
+	}
+}
+
+func newMethodAuthenticated[T any, B any](name, summary string, callback func(r *http.Request, args *T, reply *B, authenticated bool) error) *APIMethod {
+	return &APIMethod{
+		name,
+		summary,
+		true,
+		false,
+		reflect.TypeOf((*T)(nil)).Elem(),
+		reflect.TypeOf((*B)(nil)).Elem(),
+		func() any { return new(T) },
+		func(args any, authenticated bool) (any, error) {
+			reply := new(B)
+			return reply, callback(nil, args.(*T), reply, authenticated)
+		},
+	}
+}
+
+func (api *APICommon) initMethods() {
+
+	walletPrivateTransfer := newMethodAuthenticated[APIWalletPrivateTransferRequest, APIWalletPrivateTransferReply]("wallet/private-transfer", "Create a private Transfer", api.WalletPrivateTransfer)
+	walletPrivateTransfer.Post = true
+
+	chainRollback := newMethodAuthenticated[APIChainRollbackRequest, APIChainRollbackReply]("chain/rollback", "Revert the chain to a given height", api.ChainRollback)
+	chainRollback.Post = true
+
+	api.Methods = []*APIMethod{
+		newMethod[struct{}, APIPingReply]("ping", "Ping/Pong", api.GetPing),
+		newMethod[struct{}, APIInfoReply]("", "Node Info", api.GetInfo),
+		newMethod[struct{}, APIBlockchain]("chain", "Blockchain summary", api.GetBlockchain),
+		newMethod[struct{}, APIBlockchain]("blockchain", "Alias for chain", api.GetBlockchain),
+		chainRollback,
+		newMethod[APIStakingInfoRequest, APIStakingInfoReply]("blockchain/staking-info", "Staking info", api.GetStakingInfo),
+		newMethod[APIGenesisInfoRequest, APIGenesisInfoReply]("blockchain/genesis-info", "Genesis info", api.GetGenesisInfo),
+		newMethod[struct{}, APISupply]("blockchain/supply", "Supply", api.GetSupply),
+		newMethod[APIFeeEstimateRequest, APIFeeEstimateReply]("blockchain/fee-estimate", "Fast, normal and slow fee per byte for an asset based on the last blocks and the mempool", api.GetFeeEstimate),
+		newMethod[struct{}, blockchain_sync.BlockchainSyncData]("sync", "Sync Info", api.GetBlockchainSync),
+		newMethod[APIBlockHashRequest, APIBlockHashReply]("block-hash", "Block hash from height", api.GetBlockHash),
+		newMethod[APIBlockRequest, APIBlockReply]("block", "Block with Txs hashes only", api.GetBlock),
+		newMethod[APIBlockExistsRequest, APIBlockExistsReply]("block/exists", "Existence of a block", api.GetBlockExists),
+		newMethod[APIBlockCompleteRequest, APIBlockCompleteReply]("block-complete", "Block with Txs", api.GetBlockComplete),
+		newMethod[APITxHashRequest, APITxHashReply]("tx-hash", "Tx hash from height", api.GetTxHash),
+		newMethod[APITxRequest, APITxReply]("tx", "Transaction", api.GetTx),
+		newMethod[APITxExistsRequest, APITxExistsReply]("tx/exists", "Existence of a transaction", api.GetTxExists),
+		newMethod[APITxRawRequest, APITxRawReply]("tx-raw", "Transaction serialized", api.GetTxRaw),
+		newMethod[APIAccountRequest, APIAccountReply]("account", "Account", api.GetAccount),
+		newMethod[APIAccountsCountRequest, APIAccountsCountReply]("accounts/count", "Number of accounts for an asset", api.GetAccountsCount),
+		newMethod[APIAccountsKeysByIndexRequest, APIAccountsKeysByIndexReply]("accounts/keys-by-index", "Accounts Keys for an asset specified by a list of indexes", api.GetAccountsKeysByIndex),
+		newMethod[APIAccountsByKeysRequest, APIAccountsByKeysReply]("accounts/by-keys", "Accounts for an asset specified by a list of Accounts Keys", api.GetAccountsByKeys),
+		newMethod[APIAssetRequest, APIAssetReply]("asset", "Asset", api.GetAsset),
+		newMethod[APIAssetExistsRequest, APIAssetExistsReply]("asset/exists", "Existence of an asset", api.GetAssetExists),
+		newMethod[APIAssetFeeLiquidityFeeRequest, APIAssetFeeLiquidityFeeReply]("asset/fee-liquidity", "Asset Fee Liquidity", api.GetAssetFeeLiquidity),
+		newMethod[APIMempoolRequest, APIMempoolReply]("mempool", "List of Tx Hashes that are in the mempool", api.GetMempool),
+		newMethod[APIMempoolExistsRequest, APIMempoolExistsReply]("mempool/tx-exists", "Existence of a Tx Hash in the mempool", api.GetMempoolExists),
+		newMethod[APIMempoolNewTxRequest, APIMempoolNewTxReply]("mempool/new-tx", "Validate, Include and Broadcast Tx", api.MempoolNewTx),
+		newMethod[struct{}, APINetworkNodesReply]("network/nodes", "List of peers", api.GetNetworkNodes),
+		newMethodAuthenticated[struct{}, APINetworkBannedNodesReply]("network/banned-nodes", "List of banned nodes", api.GetNetworkBannedNodes),
+		newMethodAuthenticated[APINetworkBanNodeRequest, APINetworkBanNodeReply]("network/ban-node", "Ban an url, ip or subnet (CIDR)", api.NetworkBanNode),
+		newMethodAuthenticated[APINetworkUnbanNodeRequest, APINetworkUnbanNodeReply]("network/unban-node", "Remove a ban", api.NetworkUnbanNode),
+		newMethodAuthenticated[struct{}, APIWalletGetAccountsReply]("wallet/get-addresses", "Get all wallet accounts", api.GetWalletAddresses),
+		newMethodAuthenticated[APIWalletGenerateAddressRequest, APIWalletGenerateAddressReply]("wallet/generate-address", "Generate a new address", api.GetWalletGenerateAddress),
+		newMethodAuthenticated[APIWalletCreateAddressRequest, APIWalletCreateAddressReply]("wallet/create-address", "Create a new empty address", api.GetWalletCreateAddress),
+		newMethodAuthenticated[APIWalletDeleteAddressRequest, APIWalletDeleteAddressReply]("wallet/delete-address", "Delete an address from the wallet", api.GetWalletDeleteAddress),
+		newMethodAuthenticated[APIWalletGetBalanceRequest, APIWalletGetBalancesReply]("wallet/get-balances", "Get the balances (decrypted) of the requested wallet addresses", api.GetWalletBalances),
+		newMethodAuthenticated[APIWalletDecryptTxRequest, APIWalletDecryptTxReply]("wallet/decrypt-tx", "Decrypt a transaction using wallet", api.GetWalletDecryptTx),
+		walletPrivateTransfer,
+		newMethodAuthenticated[struct{}, APIWalletInvoicesReply]("wallet/invoices", "List the wallet invoices", api.GetWalletInvoices),
+		newMethodAuthenticated[APIWalletCreateInvoiceRequest, APIWalletInvoice]("wallet/create-invoice", "Create an invoice and its pandorapay: payment request URI", api.WalletCreateInvoice),
+		newMethodAuthenticated[APIWalletInvoiceStatusRequest, APIWalletInvoice]("wallet/invoice-status", "Status and payments of an invoice by PaymentID", api.GetWalletInvoiceStatus),
+	}
+
+	if config.SEED_WALLET_NODES_INFO {
+		api.Methods = append(api.Methods,
+			newMethod[APIAssetInfoRequest, info.AssetInfo]("asset-info", "Shorter version of an Asset", api.GetAssetInfo),
+			newMethod[APIBlockInfoRequest, info.BlockInfo]("block-info", "Shorter version of a Block", api.GetBlockInfo),
+			newMethod[APITransactionInfoRequest, info.TxInfo]("tx-info", "Shorter version of a Tx", api.GetTxInfo),
+			newMethod[APITransactionPreviewRequest, APITransactionPreviewReply]("tx-preview", "Shorter version of a Tx", api.GetTxPreview),
+			newMethod[APIAccountTxsRequest, APIAccountTxsReply]("account/txs", "Account transactions", api.GetAccountTxs),
+			newMethod[APIAccountMempoolRequest, APIAccountMempoolReply]("account/mempool", "Account pending transactions in mempool", api.GetAccountMempool),
+			newMethod[APIAccountMempoolNonceRequest, APIAccountMempoolNonceReply]("account/mempool-nonce", "Account new nonce from the mempool", api.GetAccountMempoolNonce),
+		)
+	}
+
+	if config.INDEXER {
+		api.Methods = append(api.Methods, newMethod[APIAccountHistoryRequest, APIAccountHistoryReply]("account/history", "Account transactions from the indexer filtered by height range, asset and payload script", api.GetAccountHistory))
+	}
+
+	if api.Faucet != nil {
+		api.Methods = append(api.Methods, newMethod[struct{}, api_faucet.APIFaucetInfo]("faucet/info", "Faucet information", api.Faucet.GetFaucetInfo))
+		if config.FAUCET_TESTNET_ENABLED {
+			api.Methods = append(api.Methods, newMethod[api_faucet.APIFaucetCoinsRequest, api_faucet.APIFaucetCoinsReply]("faucet/coins", "Get Faucet coins", api.Faucet.GetFaucetCoins))
+		}
+	}
+
+	if api.DelegatorNode != nil {
+		api.Methods = append(api.Methods,
+			newMethod[struct{}, api_delegator_node.ApiDelegatorNodeInfoReply]("delegator-node/info", "Delegator Info", api.DelegatorNode.GetDelegatorNodeInfo),
+			newMethodAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply]("delegator-node/notify", "Notify the delegator node", api.DelegatorNode.DelegatorNotify),
+		)
+	}
+
+}
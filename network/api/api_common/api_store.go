@@ -45,7 +45,7 @@ func (apiStore *APIStore) loadTxHash(reader store_db_interface.StoreDBTransactio
 	return reader.Get("txHash_ByHeight" + strconv.FormatUint(height, 10)), nil
 }
 
-func (chain *APIStore) loadBlock(reader store_db_interface.StoreDBTransactionInterface, hash []byte) (*block.Block, error) {
+func (chain *APIStore) LoadBlock(reader store_db_interface.StoreDBTransactionInterface, hash []byte) (*block.Block, error) {
 	blockData := reader.Get("block_ByHash" + string(hash))
 	if blockData == nil {
 		return nil, errors.New("Block was not found")
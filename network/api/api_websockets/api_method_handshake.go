@@ -6,5 +6,5 @@ import (
 )
 
 func (api *APIWebsockets) handshake(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
-	return &connection.ConnectionHandshake{config.NAME, config.VERSION_STRING, config.NETWORK_SELECTED, config.CONSENSUS, config.NETWORK_ADDRESS_URL_STRING}, nil
+	return &connection.ConnectionHandshake{config.NAME, config.VERSION_STRING, config.NETWORK_SELECTED, config.CONSENSUS, config.NETWORK_ADDRESS_URL_STRING, config.PRUNE_BLOCKS}, nil
 }
@@ -34,6 +34,9 @@ func (api *Consensus) GetBlockCompleteMissingTxs(r *http.Request, args *APIBlock
 
 		data := reader.Get("blockTxs" + strconv.FormatUint(height, 10))
 		if data == nil {
+			if api.chain.IsBlockPruned(reader, height) {
+				return errors.New("Block data was pruned")
+			}
 			return errors.New("Block not found")
 		}
 
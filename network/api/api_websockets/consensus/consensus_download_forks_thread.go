@@ -43,6 +43,10 @@ func (thread *ConsensusProcessForksThread) downloadBlockHash(conn *connection.Ad
 
 func (thread *ConsensusProcessForksThread) downloadBlockComplete(conn *connection.AdvancedConnection, fork *Fork, height uint64) (*block_complete.BlockComplete, error) {
 
+	if !conn.Handshake.CanServeBlock(height, fork.End) {
+		return nil, errors.New("Block was pruned by the peer")
+	}
+
 	blkWithTx, err := connection.SendJSONAwaitAnswer[api_common.APIBlockReply](conn, []byte("block"), &api_common.APIBlockRequest{height, nil, api_types.RETURN_SERIALIZED}, nil, 0)
 	if err != nil {
 		return nil, err
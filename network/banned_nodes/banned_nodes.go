@@ -1,44 +1,283 @@
 package banned_nodes
 
 import (
+	"errors"
+	"net"
 	"net/url"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/recovery"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
+const (
+	BAN_MAX_DURATION        = 10 * 365 * 24 * time.Hour
+	BAN_PERSISTENT_DURATION = time.Hour //automatic bans shorter than it are kept only in memory
+)
+
+//BannedNode bans an url, an ip or a subnet (CIDR)
 type BannedNode struct {
-	URL        *url.URL
-	Timestamp  time.Time
-	Expiration time.Time
-	Message    string
+	URL        string `json:"url" msgpack:"url"`
+	Timestamp  int64  `json:"timestamp" msgpack:"timestamp"`
+	Expiration int64  `json:"expiration" msgpack:"expiration"`
+	Message    string `json:"message" msgpack:"message"`
+	Persistent bool   `json:"persistent" msgpack:"persistent"`
+}
+
+type bannedSubnet struct {
+	subnet     *net.IPNet
+	bannedNode *BannedNode
 }
 
 type BannedNodes struct {
-	bannedMap *generics.Map[string, *BannedNode]
+	bannedMap   *generics.Map[string, *BannedNode]
+	subnets     []*bannedSubnet
+	subnetsLock *sync.RWMutex
+	saveLock    *sync.Mutex
+}
+
+func (bannedNode *BannedNode) IsExpired(now time.Time) bool {
+	return now.Unix() >= bannedNode.Expiration
+}
+
+//GetIP returns the host of a remote address ("ip:port") or of a websocket url
+func GetIP(remoteAddr string) string {
+	if u, err := url.Parse(remoteAddr); err == nil && u.Host != "" {
+		remoteAddr = u.Host
+	}
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+//NormalizeURL accepts an url, an ip or a subnet (CIDR)
+func NormalizeURL(urlStr string) (string, error) {
+
+	urlStr = strings.TrimSpace(urlStr)
+
+	if _, subnet, err := net.ParseCIDR(urlStr); err == nil {
+		return subnet.String(), nil
+	}
+	if ip := net.ParseIP(urlStr); ip != nil {
+		return ip.String(), nil
+	}
+	if u, err := url.Parse(urlStr); err == nil && u.Scheme != "" && u.Host != "" {
+		return urlStr, nil
+	}
+
+	return "", errors.New("Invalid ip, subnet or url")
+}
+
+func (self *BannedNodes) load(key string) *BannedNode {
+	if bannedNode, found := self.bannedMap.Load(key); found {
+		if !bannedNode.IsExpired(time.Now()) {
+			return bannedNode
+		}
+	}
+	return nil
+}
+
+//GetBan checks the url, its ip and the banned subnets
+func (self *BannedNodes) GetBan(urlStr string) *BannedNode {
+
+	if bannedNode := self.load(urlStr); bannedNode != nil {
+		return bannedNode
+	}
+
+	host := GetIP(urlStr)
+	if host != urlStr {
+		if bannedNode := self.load(host); bannedNode != nil {
+			return bannedNode
+		}
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	self.subnetsLock.RLock()
+	defer self.subnetsLock.RUnlock()
+
+	for _, it := range self.subnets {
+		if it.subnet.Contains(ip) && !it.bannedNode.IsExpired(now) {
+			return it.bannedNode
+		}
+	}
+
+	return nil
 }
 
 func (self *BannedNodes) IsBanned(urlStr string) bool {
-	if _, found := self.bannedMap.Load(urlStr); found {
+	return self.GetBan(urlStr) != nil
+}
+
+func (self *BannedNodes) GetCount() (count int) {
+	now := time.Now()
+	self.bannedMap.Range(func(key string, value *BannedNode) bool {
+		if !value.IsExpired(now) {
+			count += 1
+		}
 		return true
+	})
+	return
+}
+
+//GetList returns the bans sorted by timestamp
+func (self *BannedNodes) GetList() []*BannedNode {
+
+	now := time.Now()
+
+	list := make([]*BannedNode, 0)
+	self.bannedMap.Range(func(key string, value *BannedNode) bool {
+		if !value.IsExpired(now) {
+			list = append(list, value)
+		}
+		return true
+	})
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Timestamp < list[j].Timestamp
+	})
+
+	return list
+}
+
+//returns the replaced ban
+func (self *BannedNodes) store(bannedNode *BannedNode) *BannedNode {
+
+	old, _ := self.bannedMap.Load(bannedNode.URL)
+	self.bannedMap.Store(bannedNode.URL, bannedNode)
+
+	if _, subnet, err := net.ParseCIDR(bannedNode.URL); err == nil {
+		self.subnetsLock.Lock()
+		self.removeSubnet(bannedNode.URL)
+		self.subnets = append(self.subnets, &bannedSubnet{subnet, bannedNode})
+		self.subnetsLock.Unlock()
 	}
-	return false
+
+	return old
 }
 
-func (self *BannedNodes) Ban(url *url.URL, urlStr, message string, duration time.Duration) {
-	if urlStr == "" {
-		urlStr = url.String()
+//subnetsLock must be locked
+func (self *BannedNodes) removeSubnet(urlStr string) {
+	for i, it := range self.subnets {
+		if it.bannedNode.URL == urlStr {
+			self.subnets = append(self.subnets[:i], self.subnets[i+1:]...)
+			return
+		}
 	}
-	time := time.Now()
-	self.bannedMap.Store(urlStr, &BannedNode{
-		URL:        url,
-		Message:    message,
-		Timestamp:  time,
-		Expiration: time.Add(duration),
-	})
 }
 
-func NewBannedNodes() *BannedNodes {
-	return &BannedNodes{
-		bannedMap: &generics.Map[string, *BannedNode]{},
+func (self *BannedNodes) remove(urlStr string) (*BannedNode, bool) {
+
+	bannedNode, found := self.bannedMap.LoadAndDelete(urlStr)
+	if !found {
+		return nil, false
 	}
+
+	self.subnetsLock.Lock()
+	self.removeSubnet(urlStr)
+	self.subnetsLock.Unlock()
+
+	return bannedNode, true
+}
+
+//Ban bans an url, an ip or a subnet (CIDR). Persistent bans are kept after restart
+func (self *BannedNodes) Ban(urlStr, message string, duration time.Duration, persistent bool) (*BannedNode, error) {
+
+	urlStr, err := NormalizeURL(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if duration > BAN_MAX_DURATION {
+		return nil, errors.New("Ban duration is too long")
+	}
+
+	now := time.Now()
+	bannedNode := &BannedNode{
+		urlStr,
+		now.Unix(),
+		now.Add(duration).Unix(),
+		message,
+		persistent,
+	}
+
+	if old := self.store(bannedNode); persistent || (old != nil && old.Persistent) {
+		if err = self.saveBannedNodes(); err != nil {
+			return nil, err
+		}
+	}
+
+	return bannedNode, nil
+}
+
+func (self *BannedNodes) Unban(urlStr string) error {
+
+	urlStr, err := NormalizeURL(urlStr)
+	if err != nil {
+		return err
+	}
+
+	bannedNode, found := self.remove(urlStr)
+	if !found {
+		return errors.New("Node is not banned")
+	}
+
+	if bannedNode.Persistent {
+		return self.saveBannedNodes()
+	}
+	return nil
+}
+
+func (self *BannedNodes) removeExpired() {
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		<-ticker.C
+
+		now := time.Now()
+		var changed bool
+
+		self.bannedMap.Range(func(key string, value *BannedNode) bool {
+			if value.IsExpired(now) {
+				if _, found := self.remove(key); found && value.Persistent {
+					changed = true
+				}
+			}
+			return true
+		})
+
+		if changed {
+			self.saveBannedNodes()
+		}
+	}
+}
+
+func NewBannedNodes() (*BannedNodes, error) {
+
+	bannedNodes := &BannedNodes{
+		&generics.Map[string, *BannedNode]{},
+		make([]*bannedSubnet, 0),
+		&sync.RWMutex{},
+		&sync.Mutex{},
+	}
+
+	if err := bannedNodes.loadBannedNodes(); err != nil {
+		return nil, err
+	}
+
+	recovery.SafeGo(bannedNodes.removeExpired)
+
+	bannedNodes.initCLI()
+
+	return bannedNodes, nil
 }
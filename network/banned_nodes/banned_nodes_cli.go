@@ -0,0 +1,68 @@
+package banned_nodes
+
+import (
+	"context"
+	"fmt"
+	"pandora-pay/gui"
+	"time"
+)
+
+func (self *BannedNodes) initCLI() {
+
+	cliListBannedNodes := func(cmd string, ctx context.Context) (err error) {
+
+		list := self.GetList()
+		if len(list) == 0 {
+			gui.GUI.OutputWrite("No banned nodes")
+			return
+		}
+
+		gui.GUI.OutputWrite("Banned Nodes:")
+		for _, bannedNode := range list {
+			persistent := ""
+			if bannedNode.Persistent {
+				persistent = "persistent"
+			}
+			gui.GUI.OutputWrite(fmt.Sprintf("%-40s %-20s %-10s %s", bannedNode.URL, time.Unix(bannedNode.Expiration, 0).UTC().Format(time.RFC822), persistent, bannedNode.Message))
+		}
+
+		return
+	}
+
+	cliBanNode := func(cmd string, ctx context.Context) (err error) {
+
+		urlStr := gui.GUI.OutputReadString("Url, ip or subnet (CIDR)")
+		if urlStr, err = NormalizeURL(urlStr); err != nil {
+			return
+		}
+
+		message := gui.GUI.OutputReadString("Reason")
+
+		minutes := gui.GUI.OutputReadUint64("Duration in minutes", false, 0, func(value uint64) bool {
+			return value > 0 && value <= uint64(BAN_MAX_DURATION/time.Minute)
+		})
+
+		if _, err = self.Ban(urlStr, message, time.Duration(minutes)*time.Minute, true); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Banned " + urlStr)
+		return
+	}
+
+	cliUnbanNode := func(cmd string, ctx context.Context) (err error) {
+
+		urlStr := gui.GUI.OutputReadString("Url, ip or subnet (CIDR)")
+
+		if err = self.Unban(urlStr); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Unbanned " + urlStr)
+		return
+	}
+
+	gui.GUI.CommandDefineCallback("List Banned Nodes", cliListBannedNodes, true)
+	gui.GUI.CommandDefineCallback("Ban Node", cliBanNode, true)
+	gui.GUI.CommandDefineCallback("Unban Node", cliUnbanNode, true)
+}
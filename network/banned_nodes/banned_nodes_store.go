@@ -0,0 +1,57 @@
+package banned_nodes
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"time"
+)
+
+func (self *BannedNodes) saveBannedNodes() error {
+
+	self.saveLock.Lock()
+	defer self.saveLock.Unlock()
+
+	now := time.Now()
+
+	list := make([]*BannedNode, 0)
+	for _, bannedNode := range self.GetList() {
+		if bannedNode.Persistent && !bannedNode.IsExpired(now) {
+			list = append(list, bannedNode)
+		}
+	}
+
+	marshal, err := msgpack.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Put("bannedNodes", marshal)
+		return nil
+	})
+}
+
+func (self *BannedNodes) loadBannedNodes() error {
+	return store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		data := reader.Get("bannedNodes")
+		if data == nil {
+			return
+		}
+
+		list := make([]*BannedNode, 0)
+		if err = msgpack.Unmarshal(data, &list); err != nil {
+			return
+		}
+
+		now := time.Now()
+		for _, bannedNode := range list {
+			if !bannedNode.IsExpired(now) {
+				self.store(bannedNode)
+			}
+		}
+
+		return
+	})
+}
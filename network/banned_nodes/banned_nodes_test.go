@@ -0,0 +1,66 @@
+package banned_nodes
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+	"time"
+)
+
+func initTestStore(t *testing.T) {
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.Nil(t, err)
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+}
+
+func TestBannedNodes(t *testing.T) {
+
+	initTestStore(t)
+
+	bannedNodes, err := NewBannedNodes()
+	assert.Nil(t, err)
+
+	_, err = bannedNodes.Ban("10.1.0.0/16", "subnet", time.Hour, true)
+	assert.Nil(t, err)
+	_, err = bannedNodes.Ban("ws://example.com:8080/ws", "url", time.Hour, true)
+	assert.Nil(t, err)
+	_, err = bannedNodes.Ban("192.168.1.5", "temporary", time.Hour, false)
+	assert.Nil(t, err)
+	_, err = bannedNodes.Ban("10.2.0.1", "expired", -time.Second, true)
+	assert.Nil(t, err)
+	_, err = bannedNodes.Ban("not an ip", "", time.Hour, true)
+	assert.NotNil(t, err)
+	_, err = bannedNodes.Ban("10.4.0.1", "too long", BAN_MAX_DURATION+time.Hour, true)
+	assert.NotNil(t, err)
+
+	assert.True(t, bannedNodes.IsBanned("10.1.2.3:5000"))
+	assert.True(t, bannedNodes.IsBanned("ws://10.1.200.1:8080/ws"))
+	assert.False(t, bannedNodes.IsBanned("10.3.0.1:5000"))
+	assert.True(t, bannedNodes.IsBanned("ws://example.com:8080/ws"))
+	assert.True(t, bannedNodes.IsBanned("192.168.1.5:80"))
+	assert.False(t, bannedNodes.IsBanned("10.2.0.1"))
+	assert.Equal(t, 3, bannedNodes.GetCount())
+
+	//only the persistent bans are loaded again
+	reloaded, err := NewBannedNodes()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, reloaded.GetCount())
+	assert.True(t, reloaded.IsBanned("10.1.2.3"))
+	assert.False(t, reloaded.IsBanned("192.168.1.5"))
+
+	assert.Nil(t, reloaded.Unban("10.1.0.0/16"))
+	assert.False(t, reloaded.IsBanned("10.1.2.3"))
+	assert.NotNil(t, reloaded.Unban("10.1.0.0/16"))
+
+	reloaded, err = NewBannedNodes()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, reloaded.GetCount())
+	assert.Equal(t, "ws://example.com:8080/ws", reloaded.GetList()[0].URL)
+}
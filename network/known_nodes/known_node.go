@@ -1,17 +1,24 @@
 package known_nodes
 
 import (
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type KnownNode struct {
 	URL    string
-	IsSeed bool
+	IsSeed bool //use lock. Known nodes are marked as seeds when they are added again as seeds
 }
 
 type KnownNodeScored struct {
 	KnownNode
-	Score int32 //use atomic
+	Score    int32 //use atomic
+	Added    int64 //unix timestamp
+	LastSeen int64 //use atomic. Unix timestamp of the last connection. 0 means never
+	Failures int32 //use atomic. Consecutive failed connections
+	version  string
+	lock     sync.RWMutex
 }
 
 func (self *KnownNodeScored) IncreaseScore(delta int32, isServer bool) bool {
@@ -34,10 +41,55 @@ func (self *KnownNodeScored) DecreaseScore(delta int32, isServer bool) bool {
 
 	newScore := atomic.AddInt32(&self.Score, delta)
 	if newScore < -100 {
-		if !self.IsSeed {
+		if !self.GetIsSeed() {
 			return true
 		}
 		atomic.StoreInt32(&self.Score, -100)
 	}
 	return false
 }
+
+func (self *KnownNodeScored) GetVersion() string {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.version
+}
+
+func (self *KnownNodeScored) GetIsSeed() bool {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.IsSeed
+}
+
+func (self *KnownNodeScored) SetSeed() {
+	self.lock.Lock()
+	self.IsSeed = true
+	self.lock.Unlock()
+}
+
+func (self *KnownNodeScored) UpdateLastSeen() {
+	atomic.StoreInt64(&self.LastSeen, time.Now().Unix())
+}
+
+//Connected is called after a successful handshake
+func (self *KnownNodeScored) Connected(version string) {
+	self.lock.Lock()
+	self.version = version
+	self.lock.Unlock()
+
+	self.UpdateLastSeen()
+	atomic.StoreInt32(&self.Failures, 0)
+}
+
+func (self *KnownNodeScored) Failed() {
+	atomic.AddInt32(&self.Failures, 1)
+}
+
+//IsDead returns true when the node had only failed connections since expiration
+func (self *KnownNodeScored) IsDead(expiration int64) bool {
+	lastSeen := atomic.LoadInt64(&self.LastSeen)
+	if lastSeen == 0 {
+		lastSeen = self.Added
+	}
+	return !self.GetIsSeed() && lastSeen < expiration && atomic.LoadInt32(&self.Failures) > 0
+}
@@ -4,9 +4,12 @@ import (
 	"errors"
 	"math/rand"
 	"pandora-pay/config"
+	"pandora-pay/gui"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/recovery"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type KnownNodes struct {
@@ -36,6 +39,24 @@ func (self *KnownNodes) GetRandomKnownNode() *KnownNodeScored {
 	return self.knownList[rand.Intn(len(self.knownList))]
 }
 
+//GetBestKnownNode picks a few random nodes and returns the one with the highest score
+func (self *KnownNodes) GetBestKnownNode() *KnownNodeScored {
+	self.knownListMutex.RLock()
+	defer self.knownListMutex.RUnlock()
+	if len(self.knownList) == 0 {
+		return nil
+	}
+
+	var best *KnownNodeScored
+	for i := 0; i < config.NETWORK_KNOWN_NODES_SELECTION; i++ {
+		knownNode := self.knownList[rand.Intn(len(self.knownList))]
+		if best == nil || atomic.LoadInt32(&knownNode.Score) > atomic.LoadInt32(&best.Score) {
+			best = knownNode
+		}
+	}
+	return best
+}
+
 func (self *KnownNodes) AddKnownNode(url string, isSeed bool) (*KnownNodeScored, error) {
 
 	if url == "" {
@@ -52,9 +73,13 @@ func (self *KnownNodes) AddKnownNode(url string, isSeed bool) (*KnownNodeScored,
 			IsSeed: isSeed,
 		},
 		Score: 0,
+		Added: time.Now().Unix(),
 	}
 
-	if _, exists := self.knownMap.LoadOrStore(url, knownNode); exists {
+	if existing, exists := self.knownMap.LoadOrStore(url, knownNode); exists {
+		if isSeed {
+			existing.SetSeed()
+		}
 		return nil, errors.New("Already exists")
 	}
 
@@ -84,14 +109,45 @@ func (self *KnownNodes) RemoveKnownNode(knownNode *KnownNodeScored) {
 
 }
 
-func NewKnownNodes() (knownNodes *KnownNodes) {
+func (self *KnownNodes) removeDeadKnownNodes() {
+	expiration := time.Now().Add(-config.NETWORK_KNOWN_NODES_EXPIRATION).Unix()
+	for _, knownNode := range self.GetList() {
+		if knownNode.IsDead(expiration) {
+			self.RemoveKnownNode(knownNode)
+		}
+	}
+}
+
+func (self *KnownNodes) processKnownNodes() {
+	for {
+		time.Sleep(config.NETWORK_KNOWN_NODES_SAVE_INTERVAL)
+
+		self.removeDeadKnownNodes()
+		if err := self.saveKnownNodes(); err != nil {
+			gui.GUI.Error("Error saving known nodes", err)
+		}
+	}
+}
 
-	knownNodes = &KnownNodes{
+//Close saves the known nodes, so their scores are kept after restart
+func (self *KnownNodes) Close() error {
+	return self.saveKnownNodes()
+}
+
+func NewKnownNodes() (*KnownNodes, error) {
+
+	knownNodes := &KnownNodes{
 		&generics.Map[string, *KnownNodeScored]{},
 		make([]*KnownNodeScored, 0),
 		sync.RWMutex{},
 		0,
 	}
 
-	return
+	if err := knownNodes.loadKnownNodes(); err != nil {
+		return nil, err
+	}
+
+	recovery.SafeGo(knownNodes.processKnownNodes)
+
+	return knownNodes, nil
 }
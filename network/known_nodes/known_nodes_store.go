@@ -0,0 +1,74 @@
+package known_nodes
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sync/atomic"
+)
+
+type knownNodeSaved struct {
+	URL      string `msgpack:"url"`
+	Score    int32  `msgpack:"score"`
+	Added    int64  `msgpack:"added"`
+	LastSeen int64  `msgpack:"lastSeen"`
+	Failures int32  `msgpack:"failures"`
+	Version  string `msgpack:"version"`
+}
+
+func (self *KnownNodes) saveKnownNodes() error {
+
+	knownList := self.GetList()
+
+	list := make([]*knownNodeSaved, len(knownList))
+	for i, knownNode := range knownList {
+		list[i] = &knownNodeSaved{
+			knownNode.URL,
+			atomic.LoadInt32(&knownNode.Score),
+			knownNode.Added,
+			atomic.LoadInt64(&knownNode.LastSeen),
+			atomic.LoadInt32(&knownNode.Failures),
+			knownNode.GetVersion(),
+		}
+	}
+
+	marshal, err := msgpack.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Put("knownNodes", marshal)
+		return nil
+	})
+}
+
+//the seeds are marked again when they are added
+func (self *KnownNodes) loadKnownNodes() error {
+	return store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		data := reader.Get("knownNodes")
+		if data == nil {
+			return
+		}
+
+		list := make([]*knownNodeSaved, 0)
+		if err = msgpack.Unmarshal(data, &list); err != nil {
+			return
+		}
+
+		for _, saved := range list {
+			knownNode, err := self.AddKnownNode(saved.URL, false)
+			if err != nil {
+				continue
+			}
+			knownNode.Score = saved.Score
+			knownNode.Added = saved.Added
+			knownNode.LastSeen = saved.LastSeen
+			knownNode.Failures = saved.Failures
+			knownNode.version = saved.Version
+		}
+
+		return
+	})
+}
@@ -0,0 +1,103 @@
+package known_nodes
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKnownNodes_Store(t *testing.T) {
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.Nil(t, err)
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+
+	knownNodes, err := NewKnownNodes()
+	assert.Nil(t, err)
+
+	good, err := knownNodes.AddKnownNode("ws://10.0.0.1:8080/ws", false)
+	assert.Nil(t, err)
+	good.Connected("1.2.0")
+	good.IncreaseScore(50, false)
+
+	dead, err := knownNodes.AddKnownNode("ws://10.0.0.2:8080/ws", false)
+	assert.Nil(t, err)
+	dead.Added = time.Now().Add(-10 * 24 * time.Hour).Unix()
+	dead.Failed()
+
+	seed, err := knownNodes.AddKnownNode("ws://10.0.0.3:8080/ws", true)
+	assert.Nil(t, err)
+	seed.Added = dead.Added
+	seed.Failed()
+
+	//the known nodes are saved when the app is closed
+	assert.Nil(t, knownNodes.Close())
+
+	loaded, err := NewKnownNodes()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(loaded.GetList()))
+
+	node, _ := loaded.knownMap.Load(good.URL)
+	assert.Equal(t, int32(50), node.Score)
+	assert.Equal(t, "1.2.0", node.GetVersion())
+	assert.Equal(t, good.LastSeen, node.LastSeen)
+	assert.Equal(t, int32(0), node.Failures)
+
+	node, _ = loaded.knownMap.Load(seed.URL)
+	assert.False(t, node.GetIsSeed())
+	_, err = loaded.AddKnownNode(seed.URL, true)
+	assert.NotNil(t, err)
+	assert.True(t, node.GetIsSeed())
+
+	loaded.removeDeadKnownNodes()
+	assert.Equal(t, 2, len(loaded.GetList()))
+	_, found := loaded.knownMap.Load(dead.URL)
+	assert.False(t, found)
+
+	for i := 0; i < 10; i++ {
+		assert.NotNil(t, loaded.GetBestKnownNode())
+	}
+}
+
+func TestKnownNodes_SeedConcurrently(t *testing.T) {
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.Nil(t, err)
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+
+	knownNodes, err := NewKnownNodes()
+	assert.Nil(t, err)
+
+	node, err := knownNodes.AddKnownNode("ws://10.0.0.1:8080/ws", false)
+	assert.Nil(t, err)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			knownNodes.AddKnownNode(node.URL, true)
+		}()
+		go func() {
+			defer wg.Done()
+			node.DecreaseScore(-10, false)
+			node.IsDead(time.Now().Unix())
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, node.GetIsSeed())
+}
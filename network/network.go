@@ -27,12 +27,18 @@ type Network struct {
 
 func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*Network, error) {
 
-	knownNodes := known_nodes.NewKnownNodes()
+	knownNodes, err := known_nodes.NewKnownNodes()
+	if err != nil {
+		return nil, err
+	}
 	for _, seed := range config.NETWORK_SELECTED_SEEDS {
 		knownNodes.AddKnownNode(seed.Url, true)
 	}
 
-	bannedNodes := banned_nodes.NewBannedNodes()
+	bannedNodes, err := banned_nodes.NewBannedNodes()
+	if err != nil {
+		return nil, err
+	}
 
 	tcpServer, err := node_tcp.NewTcpServer(bannedNodes, knownNodes, settings, chain, mempool, wallet, txsValidator, txsBuilder)
 	if err != nil {
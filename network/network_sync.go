@@ -21,7 +21,7 @@ func (network *Network) continuouslyConnectNewPeers() {
 				continue
 			}
 
-			knownNode := network.KnownNodes.GetRandomKnownNode()
+			knownNode := network.KnownNodes.GetBestKnownNode()
 			if knownNode == nil {
 				continue
 			}
@@ -38,6 +38,7 @@ func (network *Network) continuouslyConnectNewPeers() {
 					if err != nil {
 
 						if err.Error() != "Already connected" {
+							knownNode.Failed()
 							if knownNode.DecreaseScore(-5, false) {
 								network.KnownNodes.RemoveKnownNode(knownNode)
 							}
@@ -0,0 +1,162 @@
+package rate_limiter
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"pandora-pay/config/config_rate_limit"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/network/banned_nodes"
+	"pandora-pay/network/known_nodes"
+	"pandora-pay/recovery"
+	"sync/atomic"
+	"time"
+)
+
+type remoteIP struct {
+	bucket     *TokenBucket
+	violations *TokenBucket //the rejected requests. It refills over time, so only the sustained violations lead to a ban
+	lastSeen   int64        //use atomic
+}
+
+//RateLimiter charges the cost of every api method to the connection and to its remote ip
+type RateLimiter struct {
+	ips         *generics.Map[string, *remoteIP]
+	bannedNodes *banned_nodes.BannedNodes
+}
+
+//loopback connections are not limited
+func isLoopback(ip string) bool {
+	if ip == "localhost" {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.IsLoopback()
+}
+
+func (limiter *RateLimiter) IsBanned(remoteAddr string) bool {
+	return limiter.bannedNodes.IsBanned(remoteAddr)
+}
+
+//NewConnectionBucket returns nil when the rate limit is disabled
+func (limiter *RateLimiter) NewConnectionBucket() *TokenBucket {
+	if !config_rate_limit.RATE_LIMIT_ENABLED {
+		return nil
+	}
+	return NewTokenBucket(config_rate_limit.RATE_LIMIT_CONNECTION_RATE, config_rate_limit.RATE_LIMIT_CONNECTION_BURST)
+}
+
+func (limiter *RateLimiter) getRemoteIP(ip string) *remoteIP {
+	remote, found := limiter.ips.Load(ip)
+	if !found {
+		remote, _ = limiter.ips.LoadOrStore(ip, &remoteIP{
+			bucket:     NewTokenBucket(config_rate_limit.RATE_LIMIT_IP_RATE, config_rate_limit.RATE_LIMIT_IP_BURST),
+			violations: NewTokenBucket(config_rate_limit.RATE_LIMIT_BAN_DECAY, config_rate_limit.RATE_LIMIT_BAN_VIOLATIONS),
+		})
+	}
+	atomic.StoreInt64(&remote.lastSeen, time.Now().Unix())
+	return remote
+}
+
+//only the long bans are stored, so the automatic bans don't write the settings store
+func (limiter *RateLimiter) ban(ip, url string) {
+	persistent := config_rate_limit.RATE_LIMIT_BAN_DURATION >= banned_nodes.BAN_PERSISTENT_DURATION
+	limiter.bannedNodes.Ban(ip, "Rate limit exceeded", config_rate_limit.RATE_LIMIT_BAN_DURATION, persistent)
+	if url != "" {
+		limiter.bannedNodes.Ban(url, "Rate limit exceeded", config_rate_limit.RATE_LIMIT_BAN_DURATION, persistent)
+	}
+}
+
+//Allow charges the cost of the method. The connection bucket and the known node are optional.
+//The consensus methods are cheap over websockets, so syncing peers are not limited.
+//Every violation lowers the score of the known node. The peer is banned once the score is too low or the remote ip exceeded the limit too many times recently.
+//The bans set by the admin are enforced even when the rate limit is disabled
+func (limiter *RateLimiter) Allow(remoteAddr string, connection *TokenBucket, knownNode *known_nodes.KnownNodeScored, isServer bool, method string) (banned bool, err error) {
+
+	if limiter.bannedNodes.IsBanned(remoteAddr) {
+		return true, errors.New("Banned")
+	}
+
+	if !config_rate_limit.RATE_LIMIT_ENABLED {
+		return
+	}
+
+	ip := banned_nodes.GetIP(remoteAddr)
+	if isLoopback(ip) {
+		return
+	}
+
+	cost := config_rate_limit.GetMethodCost(method, connection != nil)
+	remote := limiter.getRemoteIP(ip)
+
+	if connection == nil || connection.Take(cost) {
+		if remote.bucket.Take(cost) {
+			return
+		}
+		if connection != nil {
+			connection.Refund(cost)
+		}
+	}
+
+	var url string
+	if knownNode != nil {
+		url = knownNode.URL
+		if knownNode.DecreaseScore(-config_rate_limit.RATE_LIMIT_SCORE_PENALTY, isServer) {
+			banned = true
+		}
+	}
+
+	if !remote.violations.Take(1) {
+		banned = true
+	}
+
+	if banned {
+		limiter.ban(ip, url)
+	}
+
+	return banned, errors.New("Rate limit exceeded")
+}
+
+//AllowHTTP writes the error when the request is rejected
+func (limiter *RateLimiter) AllowHTTP(w http.ResponseWriter, req *http.Request, method string) bool {
+	banned, err := limiter.Allow(req.RemoteAddr, nil, nil, false, method)
+	if err == nil {
+		return true
+	}
+	if banned {
+		http.Error(w, err.Error(), http.StatusForbidden)
+	} else {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	}
+	return false
+}
+
+func (limiter *RateLimiter) removeIdleIPs() {
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		<-ticker.C
+
+		expiration := time.Now().Add(-config_rate_limit.RATE_LIMIT_IP_EXPIRATION).Unix()
+		limiter.ips.Range(func(ip string, remote *remoteIP) bool {
+			if atomic.LoadInt64(&remote.lastSeen) < expiration {
+				limiter.ips.Delete(ip)
+			}
+			return true
+		})
+	}
+}
+
+func NewRateLimiter(bannedNodes *banned_nodes.BannedNodes) *RateLimiter {
+
+	limiter := &RateLimiter{
+		&generics.Map[string, *remoteIP]{},
+		bannedNodes,
+	}
+
+	recovery.SafeGo(limiter.removeIdleIPs)
+
+	return limiter
+}
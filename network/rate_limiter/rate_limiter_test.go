@@ -0,0 +1,171 @@
+package rate_limiter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"pandora-pay/config/config_rate_limit"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/network/banned_nodes"
+	"pandora-pay/network/known_nodes"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+
+	bucket := NewTokenBucket(0, 10)
+
+	assert.True(t, bucket.Take(6))
+	assert.False(t, bucket.Take(6))
+	assert.True(t, bucket.Take(4))
+	assert.False(t, bucket.Take(1))
+
+	bucket.Refund(100)
+	assert.True(t, bucket.Take(10))
+	assert.False(t, bucket.Take(1))
+}
+
+func createTestRateLimiter(t *testing.T) (*RateLimiter, *banned_nodes.BannedNodes) {
+
+	config_rate_limit.RATE_LIMIT_ENABLED = true
+	config_rate_limit.RATE_LIMIT_CONNECTION_RATE = 0
+	config_rate_limit.RATE_LIMIT_CONNECTION_BURST = 10
+	config_rate_limit.RATE_LIMIT_IP_RATE = 0
+	config_rate_limit.RATE_LIMIT_IP_BURST = 10
+	config_rate_limit.RATE_LIMIT_BAN_VIOLATIONS = 3
+	config_rate_limit.RATE_LIMIT_BAN_DECAY = 0
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.Nil(t, err)
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+
+	bannedNodes, err := banned_nodes.NewBannedNodes()
+	assert.Nil(t, err)
+	return NewRateLimiter(bannedNodes), bannedNodes
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+
+	limiter, bannedNodes := createTestRateLimiter(t)
+	var err error
+
+	knownNode := &known_nodes.KnownNodeScored{KnownNode: known_nodes.KnownNode{URL: "ws://10.0.0.1:8080/ws"}}
+
+	for i := 0; i < 10; i++ {
+		_, err = limiter.Allow("10.0.0.1:5000", nil, knownNode, true, "ping")
+		assert.Nil(t, err)
+	}
+
+	banned, err := limiter.Allow("10.0.0.1:5001", nil, knownNode, true, "ping")
+	assert.NotNil(t, err)
+	assert.False(t, banned)
+	assert.Equal(t, -config_rate_limit.RATE_LIMIT_SCORE_PENALTY, knownNode.Score)
+
+	limiter.Allow("10.0.0.1:5001", nil, knownNode, true, "ping")
+	limiter.Allow("10.0.0.1:5001", nil, knownNode, true, "ping")
+	banned, err = limiter.Allow("10.0.0.1:5001", nil, knownNode, true, "ping")
+	assert.NotNil(t, err)
+	assert.True(t, banned)
+	assert.True(t, bannedNodes.IsBanned("10.0.0.1"))
+	assert.True(t, bannedNodes.IsBanned(knownNode.URL))
+	assert.True(t, limiter.IsBanned("ws://10.0.0.1:8080/ws"))
+
+	//the short automatic bans are not stored
+	reloaded, err := banned_nodes.NewBannedNodes()
+	assert.Nil(t, err)
+	assert.False(t, reloaded.IsBanned("10.0.0.1"))
+
+	_, err = limiter.Allow("127.0.0.1:5000", nil, nil, false, "block-complete")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "10.0.0.1", banned_nodes.GetIP("10.0.0.1:5000"))
+	assert.Equal(t, "10.0.0.1", banned_nodes.GetIP("ws://10.0.0.1:8080/ws"))
+	assert.Equal(t, "::1", banned_nodes.GetIP("[::1]:5000"))
+}
+
+func TestRateLimiter_ViolationsDecay(t *testing.T) {
+
+	limiter, bannedNodes := createTestRateLimiter(t)
+	config_rate_limit.RATE_LIMIT_BAN_DECAY = 100
+
+	for i := 0; i < 10; i++ {
+		limiter.Allow("10.0.0.2:5000", nil, nil, false, "ping")
+	}
+
+	//the violations are forgiven over time, so the ip is never banned
+	for i := 0; i < 10; i++ {
+		banned, err := limiter.Allow("10.0.0.2:5000", nil, nil, false, "ping")
+		assert.NotNil(t, err)
+		assert.False(t, banned)
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.False(t, bannedNodes.IsBanned("10.0.0.2"))
+
+	for i := 0; i < 4; i++ {
+		limiter.Allow("10.0.0.2:5000", nil, nil, false, "ping")
+	}
+	assert.True(t, bannedNodes.IsBanned("10.0.0.2"))
+}
+
+func TestRateLimiter_ConsensusMethods(t *testing.T) {
+
+	limiter, _ := createTestRateLimiter(t)
+
+	//the peers sync the chain over websockets
+	connection := limiter.NewConnectionBucket()
+	for i := 0; i < 50; i++ {
+		_, err := limiter.Allow("10.0.0.3:5000", connection, nil, true, "block-complete")
+		assert.Nil(t, err)
+	}
+
+	//the same method is expensive over http
+	_, err := limiter.Allow("10.0.0.4:5000", nil, nil, false, "block-complete")
+	assert.Nil(t, err)
+	_, err = limiter.Allow("10.0.0.4:5000", nil, nil, false, "block-complete")
+	assert.Nil(t, err)
+	_, err = limiter.Allow("10.0.0.4:5000", nil, nil, false, "block-complete")
+	assert.NotNil(t, err)
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+
+	limiter, _ := createTestRateLimiter(t)
+	config_rate_limit.RATE_LIMIT_ENABLED = false
+
+	assert.Nil(t, limiter.NewConnectionBucket())
+	for i := 0; i < 20; i++ {
+		_, err := limiter.Allow("10.0.0.5:5000", nil, nil, false, "faucet/coins")
+		assert.Nil(t, err)
+	}
+}
+
+func TestRateLimiter_DisabledBanned(t *testing.T) {
+
+	limiter, bannedNodes := createTestRateLimiter(t)
+	config_rate_limit.RATE_LIMIT_ENABLED = false
+
+	_, err := bannedNodes.Ban("10.0.0.6", "banned by the admin", time.Hour, true)
+	assert.Nil(t, err)
+
+	banned, err := limiter.Allow("10.0.0.6:5000", nil, nil, false, "ping")
+	assert.NotNil(t, err)
+	assert.True(t, banned)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = "10.0.0.6:5000"
+	assert.False(t, limiter.AllowHTTP(w, req, "ping"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	req.RemoteAddr = "10.0.0.7:5000"
+	assert.True(t, limiter.AllowHTTP(w, req, "ping"))
+}
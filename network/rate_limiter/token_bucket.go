@@ -0,0 +1,59 @@
+package rate_limiter
+
+import (
+	"sync"
+	"time"
+)
+
+//TokenBucket refills rate tokens per second up to burst
+type TokenBucket struct {
+	rate    float64
+	burst   float64
+	tokens  float64
+	updated time.Time
+	lock    *sync.Mutex
+}
+
+func (bucket *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(bucket.updated).Seconds(); elapsed > 0 {
+		bucket.tokens += elapsed * bucket.rate
+		if bucket.tokens > bucket.burst {
+			bucket.tokens = bucket.burst
+		}
+	}
+	bucket.updated = now
+}
+
+//Take removes cost tokens. It returns false when there are not enough tokens
+func (bucket *TokenBucket) Take(cost float64) bool {
+	bucket.lock.Lock()
+	defer bucket.lock.Unlock()
+
+	bucket.refill(time.Now())
+	if bucket.tokens < cost {
+		return false
+	}
+	bucket.tokens -= cost
+	return true
+}
+
+//Refund returns tokens previously taken
+func (bucket *TokenBucket) Refund(cost float64) {
+	bucket.lock.Lock()
+	defer bucket.lock.Unlock()
+
+	bucket.tokens += cost
+	if bucket.tokens > bucket.burst {
+		bucket.tokens = bucket.burst
+	}
+}
+
+func NewTokenBucket(rate, burst float64) *TokenBucket {
+	return &TokenBucket{
+		rate,
+		burst,
+		burst,
+		time.Now(),
+		&sync.Mutex{},
+	}
+}
@@ -0,0 +1,681 @@
+package node_http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/config/globals"
+	"pandora-pay/cryptography"
+	"pandora-pay/mempool"
+	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/rate_limiter"
+	"pandora-pay/store"
+	"pandora-pay/store/hash_map"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	httpEventsClientBuffer        = 1024
+	httpEventsMaxSubscriptions    = 100
+	httpEventsMaxReplay           = 1000
+	httpEventsKeepAliveInterval   = 15 * time.Second
+	httpEventsMaxClientsPerServer = 1000
+)
+
+type HttpEventBlock struct {
+	Height    uint64 `json:"height"`
+	Hash      []byte `json:"hash"`
+	PrevHash  []byte `json:"prevHash,omitempty"`
+	Timestamp uint64 `json:"timestamp,omitempty"`
+}
+
+type HttpEventReorg struct {
+	ForkHeight uint64 `json:"forkHeight"` //first block that was replaced
+	PrevHeight uint64 `json:"prevHeight"` //chain height before the reorg
+	Height     uint64 `json:"height"`
+	Hash       []byte `json:"hash"`
+}
+
+type HttpEventMempool struct {
+	Hash     []byte `json:"hash"`
+	Inserted bool   `json:"inserted"`
+	Included bool   `json:"included,omitempty"` //removed because it was included in a block
+}
+
+type HttpEventResync struct {
+	Height uint64 `json:"height"`
+}
+
+type HttpEventSubscription struct {
+	Key   []byte `json:"key"`
+	Data  any    `json:"data,omitempty"`
+	Extra any    `json:"extra,omitempty"`
+}
+
+//the event id is the cursor "height:hash" of the last block
+type httpEvent struct {
+	height uint64
+	hash   []byte
+	name   string
+	data   []byte
+}
+
+type httpEventsClient struct {
+	blocks    bool
+	mempool   bool
+	invoices  bool
+	accounts  map[string]bool
+	assets    map[string]bool
+	txs       map[string]bool
+	eventsCn  chan *httpEvent
+	connected bool
+}
+
+//streams the chain, mempool and subscriptions notifications as Server-Sent Events
+type HttpEvents struct {
+	chain          *blockchain.Blockchain
+	mempool        *mempool.Mempool
+	apiStore       *api_common.APIStore
+	rateLimiter    *rate_limiter.RateLimiter
+	newClientCn    chan *httpEventsClient
+	removeClientCn chan *httpEventsClient
+	closeCn        chan struct{} //closed when processEvents exits
+	clients        map[*httpEventsClient]bool
+	clientsCount   int32 //use atomic
+	chainHeight    uint64
+	chainHash      []byte
+	recentBlocks   map[uint64][]byte //hashes of the last blocks streamed
+	orphans        map[string]uint64 //hashes of the blocks replaced by a reorg and their fork height
+	orphansLock    *sync.RWMutex
+}
+
+func newHttpEvent(height uint64, hash []byte, name string, data any) *httpEvent {
+	marshalled, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+	return &httpEvent{height, hash, name, marshalled}
+}
+
+func (events *HttpEvents) newTipEvent(name string, data any) *httpEvent {
+	height := uint64(0)
+	if events.chainHeight > 0 {
+		height = events.chainHeight - 1
+	}
+	return newHttpEvent(height, events.chainHash, name, data)
+}
+
+//the blocks starting with forkHeight were replaced, so the clients resuming from them are resent the blocks from forkHeight
+func (events *HttpEvents) addOrphans(forkHeight, prevHeight uint64) {
+
+	events.orphansLock.Lock()
+	defer events.orphansLock.Unlock()
+
+	for hash, height := range events.orphans {
+		if height > forkHeight {
+			events.orphans[hash] = forkHeight
+		} else if height+httpEventsMaxReplay < forkHeight {
+			delete(events.orphans, hash)
+		}
+	}
+
+	for height := forkHeight; height < prevHeight; height++ {
+		if hash, found := events.recentBlocks[height]; found {
+			events.orphans[string(hash)] = forkHeight
+			delete(events.recentBlocks, height)
+		}
+	}
+}
+
+func (events *HttpEvents) getOrphanForkHeight(hash []byte) (uint64, bool) {
+	events.orphansLock.RLock()
+	defer events.orphansLock.RUnlock()
+	forkHeight, found := events.orphans[string(hash)]
+	return forkHeight, found
+}
+
+//slow clients are disconnected
+func (events *HttpEvents) send(client *httpEventsClient, event *httpEvent) {
+	select {
+	case client.eventsCn <- event:
+	default:
+		events.removeClient(client)
+	}
+}
+
+func (events *HttpEvents) removeClient(client *httpEventsClient) {
+	if events.clients[client] {
+		delete(events.clients, client)
+		close(client.eventsCn)
+	}
+}
+
+func (events *HttpEvents) sendSubscription(name string, key string, element any, extra any, filter func(client *httpEventsClient) map[string]bool) {
+	var event *httpEvent
+	for client := range events.clients {
+		if filter(client)[key] {
+			if event == nil {
+				event = events.newTipEvent(name, &HttpEventSubscription{[]byte(key), element, extra})
+			}
+			events.send(client, event)
+		}
+	}
+}
+
+func getElement(element hash_map.HashMapElementSerializableInterface) (any, uint64) {
+	if element == nil {
+		return nil, 0
+	}
+	return element, element.GetIndex()
+}
+
+func clientAccounts(client *httpEventsClient) map[string]bool { return client.accounts }
+func clientAssets(client *httpEventsClient) map[string]bool   { return client.assets }
+func clientTxs(client *httpEventsClient) map[string]bool      { return client.txs }
+
+func (events *HttpEvents) processChainUpdate(update *blockchain_types.BlockchainUpdates) {
+
+	prevHeight := events.chainHeight
+	events.chainHeight = update.BlockHeight
+	events.chainHash = update.BlockHash
+
+	forkHeight := update.BlockHeight
+	if len(update.InsertedBlocks) > 0 {
+		forkHeight = update.InsertedBlocks[0].Block.Height
+	}
+
+	if forkHeight < prevHeight {
+
+		events.addOrphans(forkHeight, prevHeight)
+
+		//the cursor is the last common block
+		var event *httpEvent
+		if forkHeight > 0 {
+			hash, found := events.recentBlocks[forkHeight-1]
+			if !found {
+				hash, _ = events.chain.OpenLoadBlockHash(forkHeight - 1)
+			}
+			event = newHttpEvent(forkHeight-1, hash, "reorg", &HttpEventReorg{forkHeight, prevHeight, update.BlockHeight, update.BlockHash})
+		} else {
+			event = newHttpEvent(0, nil, "reorg", &HttpEventReorg{forkHeight, prevHeight, update.BlockHeight, update.BlockHash})
+		}
+
+		for client := range events.clients {
+			if client.blocks {
+				events.send(client, event)
+			}
+		}
+	}
+
+	for _, blkComplete := range update.InsertedBlocks {
+		blk := blkComplete.Block
+
+		events.recentBlocks[blk.Height] = blk.Bloom.Hash
+		if blk.Height >= httpEventsMaxReplay {
+			delete(events.recentBlocks, blk.Height-httpEventsMaxReplay)
+		}
+
+		event := newHttpEvent(blk.Height, blk.Bloom.Hash, "block", &HttpEventBlock{blk.Height, blk.Bloom.Hash, blk.PrevHash, blk.Timestamp})
+		for client := range events.clients {
+			if client.blocks {
+				events.send(client, event)
+			}
+		}
+	}
+}
+
+func (events *HttpEvents) processEvents() {
+
+	defer close(events.closeCn)
+
+	updateNewChainCn := events.chain.UpdateNewChainUpdate.AddListener()
+	defer events.chain.UpdateNewChainUpdate.RemoveChannel(updateNewChainCn)
+
+	updateNotificationsCn := events.chain.UpdateSocketsSubscriptionsNotifications.AddListener()
+	defer events.chain.UpdateSocketsSubscriptionsNotifications.RemoveChannel(updateNotificationsCn)
+
+	updateTransactionsCn := events.chain.UpdateSocketsSubscriptionsTransactions.AddListener()
+	defer events.chain.UpdateSocketsSubscriptionsTransactions.RemoveChannel(updateTransactionsCn)
+
+	updateMempoolTransactionsCn := events.mempool.Txs.UpdateMempoolTransactions.AddListener()
+	defer events.mempool.Txs.UpdateMempoolTransactions.RemoveChannel(updateMempoolTransactionsCn)
+
+	mainEventsCn := globals.MainEvents.AddListener()
+	defer globals.MainEvents.RemoveChannel(mainEventsCn)
+
+	chainData := events.chain.GetChainData()
+	events.chainHeight, events.chainHash = chainData.Height, chainData.Hash
+
+	for {
+		select {
+		case client := <-events.newClientCn:
+			events.clients[client] = true
+
+		case client := <-events.removeClientCn:
+			events.removeClient(client)
+
+		case update, ok := <-updateNewChainCn:
+			if !ok {
+				return
+			}
+			events.processChainUpdate(update)
+
+		case dataStorage, ok := <-updateNotificationsCn:
+			if !ok {
+				return
+			}
+			if len(events.clients) == 0 {
+				continue
+			}
+
+			for _, accs := range dataStorage.AccsCollection.GetAllMaps() {
+				for k, v := range accs.HashMap.Committed {
+					element, index := getElement(v.Element)
+					events.sendSubscription("account", k, element, &api_types.APISubscriptionNotificationAccountExtra{accs.Asset, index}, clientAccounts)
+				}
+			}
+			for k, v := range dataStorage.PlainAccs.HashMap.Committed {
+				element, index := getElement(v.Element)
+				events.sendSubscription("plainAccount", k, element, &api_types.APISubscriptionNotificationPlainAccExtra{index}, clientAccounts)
+			}
+			for k, v := range dataStorage.Asts.HashMap.Committed {
+				element, index := getElement(v.Element)
+				events.sendSubscription("asset", k, element, &api_types.APISubscriptionNotificationAssetExtra{index}, clientAssets)
+			}
+			for k, v := range dataStorage.Regs.HashMap.Committed {
+				element, index := getElement(v.Element)
+				events.sendSubscription("registration", k, element, &api_types.APISubscriptionNotificationRegistrationExtra{index}, clientAccounts)
+			}
+
+		case txsUpdates, ok := <-updateTransactionsCn:
+			if !ok {
+				return
+			}
+			if len(events.clients) == 0 {
+				continue
+			}
+
+			for _, v := range txsUpdates {
+				for _, key := range v.Keys {
+					events.sendSubscription("accountTx", string(key.PublicKey), v.TxHash, &api_types.APISubscriptionNotificationAccountTxExtra{
+						Blockchain: &api_types.APISubscriptionNotificationAccountTxExtraBlockchain{v.Inserted, key.TxsCount, v.BlockHeight, v.BlockTimestamp, v.Height},
+					}, clientAccounts)
+				}
+				events.sendSubscription("tx", v.TxHashStr, nil, &api_types.APISubscriptionNotificationTxExtra{
+					Blockchain: &api_types.APISubscriptionNotificationTxExtraBlockchain{v.Inserted, v.BlockHeight, v.BlockTimestamp, v.Height},
+				}, clientTxs)
+			}
+
+		case data, ok := <-mainEventsCn:
+			if !ok {
+				return
+			}
+
+			var name string
+			switch data.Name {
+			case "wallet/invoice-created":
+				name = "invoiceCreated"
+			case "wallet/invoice-paid":
+				name = "invoicePaid"
+			default:
+				continue
+			}
+
+			var event *httpEvent
+			for client := range events.clients {
+				if client.invoices {
+					if event == nil {
+						event = events.newTipEvent(name, data.Data)
+					}
+					events.send(client, event)
+				}
+			}
+
+		case txUpdate, ok := <-updateMempoolTransactionsCn:
+			if !ok {
+				return
+			}
+			if len(events.clients) == 0 {
+				continue
+			}
+
+			var event *httpEvent
+			for client := range events.clients {
+				if client.mempool {
+					if event == nil {
+						event = events.newTipEvent("mempool", &HttpEventMempool{txUpdate.Tx.Bloom.Hash, txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification})
+					}
+					events.send(client, event)
+				}
+			}
+
+			for key := range txUpdate.Keys {
+				events.sendSubscription("accountTx", key, txUpdate.Tx.Bloom.Hash, &api_types.APISubscriptionNotificationAccountTxExtra{
+					Mempool: &api_types.APISubscriptionNotificationAccountTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification},
+				}, clientAccounts)
+			}
+			events.sendSubscription("tx", txUpdate.Tx.Bloom.HashStr, nil, &api_types.APISubscriptionNotificationTxExtra{
+				Mempool: &api_types.APISubscriptionNotificationTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification},
+			}, clientTxs)
+		}
+	}
+}
+
+func decodeEventsKey(value string, size int) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != size {
+		return nil, errors.New("Invalid key length")
+	}
+	return key, nil
+}
+
+func newHttpEventsClient(req *http.Request) (*httpEventsClient, error) {
+
+	query := req.URL.Query()
+
+	client := &httpEventsClient{
+		accounts: make(map[string]bool),
+		assets:   make(map[string]bool),
+		txs:      make(map[string]bool),
+		eventsCn: make(chan *httpEvent, httpEventsClientBuffer),
+	}
+
+	names := query.Get("events")
+	if names == "" {
+		names = "blocks"
+	}
+	for _, name := range strings.Split(names, ",") {
+		switch name {
+		case "blocks":
+			client.blocks = true
+		case "mempool":
+			client.mempool = true
+		case "invoices":
+			auth := &api_types.APIAuthenticated[struct{}]{User: query.Get("user"), Pass: query.Get("pass")}
+			if !auth.CheckAuthenticated() {
+				return nil, errors.New("Invalid User or Password")
+			}
+			client.invoices = true
+		case "none":
+		default:
+			return nil, fmt.Errorf("Invalid event %s", name)
+		}
+	}
+
+	if len(query["account"])+len(query["asset"])+len(query["tx"]) > httpEventsMaxSubscriptions {
+		return nil, errors.New("Too many subscriptions")
+	}
+
+	for _, value := range query["account"] {
+		if address, err := addresses.DecodeAddr(value); err == nil {
+			client.accounts[string(address.PublicKey)] = true
+			continue
+		}
+		publicKey, err := decodeEventsKey(value, cryptography.PublicKeySize)
+		if err != nil {
+			return nil, errors.New("Invalid account")
+		}
+		client.accounts[string(publicKey)] = true
+	}
+
+	for _, value := range query["asset"] {
+		asset, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, errors.New("Invalid asset")
+		}
+		client.assets[string(asset)] = true
+	}
+
+	for _, value := range query["tx"] {
+		hash, err := decodeEventsKey(value, cryptography.HashSize)
+		if err != nil {
+			return nil, errors.New("Invalid tx")
+		}
+		client.txs[string(hash)] = true
+	}
+
+	return client, nil
+}
+
+func writeHttpEvent(w http.ResponseWriter, event *httpEvent) (err error) {
+	_, err = fmt.Fprintf(w, "id: %d:%s\nevent: %s\ndata: %s\n\n", event.height, base64.StdEncoding.EncodeToString(event.hash), event.name, event.data)
+	return
+}
+
+//the cursor is the id of the last event received by the client
+func parseHttpEventsCursor(value string) (height uint64, hash []byte, err error) {
+
+	heightStr, hashStr, found := strings.Cut(value, ":")
+	if !found {
+		return 0, nil, errors.New("Invalid Last-Event-ID")
+	}
+	if height, err = strconv.ParseUint(heightStr, 10, 64); err != nil {
+		return 0, nil, errors.New("Invalid Last-Event-ID")
+	}
+	if hash, err = decodeEventsKey(hashStr, cryptography.HashSize); err != nil {
+		return 0, nil, errors.New("Invalid Last-Event-ID")
+	}
+
+	return
+}
+
+//block events missed since the cursor. When the cursor block was replaced by a reorg, a reorg event is sent and the blocks are resent from the fork point.
+//Returns the first height that was not replayed
+func (events *HttpEvents) replay(w http.ResponseWriter, cursorHeight uint64, cursorHash []byte) (next uint64, err error) {
+
+	chainData := events.chain.GetChainData()
+	if chainData.Height == 0 {
+		return 0, nil
+	}
+
+	resync := false
+	start := cursorHeight + 1
+
+	var list []*httpEvent
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		onChain := false
+		if cursorHeight < chainData.Height {
+			var hash []byte
+			if hash, err = events.chain.LoadBlockHash(reader, cursorHeight); err != nil {
+				return
+			}
+			onChain = bytes.Equal(hash, cursorHash)
+		}
+
+		if !onChain {
+
+			forkHeight, found := events.getOrphanForkHeight(cursorHash)
+			if !found {
+				resync = true
+				return
+			}
+			if forkHeight > chainData.Height {
+				forkHeight = chainData.Height
+			}
+			start = forkHeight
+
+			event := newHttpEvent(0, nil, "reorg", &HttpEventReorg{forkHeight, cursorHeight + 1, chainData.Height, chainData.Hash})
+			if forkHeight > 0 {
+				event.height = forkHeight - 1
+				if event.hash, err = events.chain.LoadBlockHash(reader, forkHeight-1); err != nil {
+					return
+				}
+			}
+			list = append(list, event)
+		}
+
+		if start < chainData.Height && chainData.Height-start > httpEventsMaxReplay {
+			resync = true
+			return
+		}
+
+		for height := start; height < chainData.Height; height++ {
+
+			var hash []byte
+			if hash, err = events.chain.LoadBlockHash(reader, height); err != nil {
+				return
+			}
+
+			var blk *block.Block
+			if blk, err = events.apiStore.LoadBlock(reader, hash); err != nil {
+				return
+			}
+
+			list = append(list, newHttpEvent(height, hash, "block", &HttpEventBlock{height, hash, blk.PrevHash, blk.Timestamp}))
+		}
+		return
+	}); err != nil {
+		return
+	}
+
+	if resync {
+		return chainData.Height, writeHttpEvent(w, newHttpEvent(chainData.Height-1, chainData.Hash, "resync", &HttpEventResync{chainData.Height - 1}))
+	}
+
+	for _, event := range list {
+		if err = writeHttpEvent(w, event); err != nil {
+			return
+		}
+	}
+
+	return chainData.Height, nil
+}
+
+func (events *HttpEvents) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	if !events.rateLimiter.AllowHTTP(w, req, "events") {
+		return
+	}
+
+	client, err := newHttpEventsClient(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lastEventIdStr := req.Header.Get("Last-Event-ID")
+	if lastEventIdStr == "" {
+		lastEventIdStr = req.URL.Query().Get("lastEventId")
+	}
+
+	var cursorHeight uint64
+	var cursorHash []byte
+	resume := lastEventIdStr != "" && client.blocks
+	if resume {
+		if cursorHeight, cursorHash, err = parseHttpEventsCursor(lastEventIdStr); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if atomic.AddInt32(&events.clientsCount, 1) > httpEventsMaxClientsPerServer {
+		atomic.AddInt32(&events.clientsCount, -1)
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "Too many clients", http.StatusServiceUnavailable)
+		return
+	}
+	defer atomic.AddInt32(&events.clientsCount, -1)
+
+	//registered before the replay to not miss any event
+	select {
+	case events.newClientCn <- client:
+	case <-events.closeCn:
+		http.Error(w, "Events are closed", http.StatusServiceUnavailable)
+		return
+	}
+	defer func() {
+		select {
+		case events.removeClientCn <- client:
+		case <-events.closeCn:
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	//the block events that were already replayed are skipped until a reorg
+	var next uint64
+	if resume {
+		if next, err = events.replay(w, cursorHeight, cursorHash); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(httpEventsKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-events.closeCn:
+			return
+		case <-keepAlive.C:
+			if _, err = fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-client.eventsCn:
+			if !ok {
+				return
+			}
+			if resume {
+				if event.name == "reorg" {
+					resume = false
+				} else if event.name == "block" && event.height < next {
+					continue
+				}
+			}
+			if err = writeHttpEvent(w, event); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func newHttpEvents(chain *blockchain.Blockchain, mempool *mempool.Mempool, apiStore *api_common.APIStore, rateLimiter *rate_limiter.RateLimiter) *HttpEvents {
+	return &HttpEvents{
+		chain,
+		mempool,
+		apiStore,
+		rateLimiter,
+		make(chan *httpEventsClient),
+		make(chan *httpEventsClient),
+		make(chan struct{}),
+		make(map[*httpEventsClient]bool),
+		0,
+		0,
+		nil,
+		make(map[uint64][]byte),
+		make(map[string]uint64),
+		&sync.RWMutex{},
+	}
+}
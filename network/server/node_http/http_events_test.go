@@ -0,0 +1,174 @@
+package node_http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"testing"
+	"time"
+)
+
+func createTestEventsUpdate(startHeight, count uint64) *blockchain_types.BlockchainUpdates {
+	update := &blockchain_types.BlockchainUpdates{}
+	for height := startHeight; height < startHeight+count; height++ {
+		blk := &block.Block{BlockHeader: &block.BlockHeader{Height: height}, Bloom: &block.BlockBloom{Hash: helpers.RandomBytes(cryptography.HashSize)}}
+		update.InsertedBlocks = append(update.InsertedBlocks, &block_complete.BlockComplete{Block: blk})
+		update.BlockHeight, update.BlockHash = height+1, blk.Bloom.Hash
+	}
+	return update
+}
+
+func TestHttpEventsOrphans(t *testing.T) {
+
+	events := newHttpEvents(nil, nil, nil, nil)
+
+	client := &httpEventsClient{blocks: true, eventsCn: make(chan *httpEvent, httpEventsClientBuffer)}
+	events.clients[client] = true
+
+	mainUpdate := createTestEventsUpdate(0, 10)
+	events.processChainUpdate(mainUpdate)
+	assert.Equal(t, 10, len(client.eventsCn))
+	for i := 0; i < 10; i++ {
+		<-client.eventsCn
+	}
+
+	//the blocks 7, 8, 9 are replaced
+	forkUpdate := createTestEventsUpdate(7, 4)
+	events.processChainUpdate(forkUpdate)
+
+	reorg := <-client.eventsCn
+	assert.Equal(t, "reorg", reorg.name)
+	assert.Equal(t, uint64(6), reorg.height)
+	assert.Equal(t, mainUpdate.InsertedBlocks[6].Block.Bloom.Hash, reorg.hash)
+	assert.Equal(t, "block", (<-client.eventsCn).name)
+
+	for height := 0; height < 10; height++ {
+		forkHeight, found := events.getOrphanForkHeight(mainUpdate.InsertedBlocks[height].Block.Bloom.Hash)
+		assert.Equal(t, height >= 7, found)
+		if found {
+			assert.Equal(t, uint64(7), forkHeight)
+		}
+	}
+
+	//a deeper reorg moves the fork point of the older orphans
+	events.processChainUpdate(createTestEventsUpdate(5, 1))
+	forkHeight, found := events.getOrphanForkHeight(mainUpdate.InsertedBlocks[8].Block.Bloom.Hash)
+	assert.True(t, found)
+	assert.Equal(t, uint64(5), forkHeight)
+	forkHeight, found = events.getOrphanForkHeight(forkUpdate.InsertedBlocks[0].Block.Bloom.Hash)
+	assert.True(t, found)
+	assert.Equal(t, uint64(5), forkHeight)
+}
+
+//a rollback update has no inserted blocks
+func TestHttpEventsRollback(t *testing.T) {
+
+	events := newHttpEvents(nil, nil, nil, nil)
+
+	client := &httpEventsClient{blocks: true, eventsCn: make(chan *httpEvent, httpEventsClientBuffer)}
+	events.clients[client] = true
+
+	mainUpdate := createTestEventsUpdate(0, 10)
+	events.processChainUpdate(mainUpdate)
+	for i := 0; i < 10; i++ {
+		<-client.eventsCn
+	}
+
+	events.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 6, BlockHash: mainUpdate.InsertedBlocks[5].Block.Bloom.Hash})
+	assert.Equal(t, 1, len(client.eventsCn))
+
+	reorg := <-client.eventsCn
+	assert.Equal(t, "reorg", reorg.name)
+	assert.Equal(t, uint64(5), reorg.height)
+	assert.Equal(t, mainUpdate.InsertedBlocks[5].Block.Bloom.Hash, reorg.hash)
+	data := &HttpEventReorg{}
+	assert.NoError(t, json.Unmarshal(reorg.data, data))
+	assert.Equal(t, &HttpEventReorg{6, 10, 6, mainUpdate.InsertedBlocks[5].Block.Bloom.Hash}, data)
+	assert.Equal(t, uint64(6), events.chainHeight)
+
+	for height := 0; height < 10; height++ {
+		forkHeight, found := events.getOrphanForkHeight(mainUpdate.InsertedBlocks[height].Block.Bloom.Hash)
+		assert.Equal(t, height >= 6, found)
+		if found {
+			assert.Equal(t, uint64(6), forkHeight)
+		}
+	}
+
+	//the chain continues from the rollback height
+	events.processChainUpdate(createTestEventsUpdate(6, 1))
+	assert.Equal(t, "block", (<-client.eventsCn).name)
+	assert.Equal(t, 0, len(client.eventsCn))
+}
+
+func TestParseHttpEventsCursor(t *testing.T) {
+
+	hash := helpers.RandomBytes(cryptography.HashSize)
+
+	height, cursorHash, err := parseHttpEventsCursor("12:" + base64.StdEncoding.EncodeToString(hash))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), height)
+	assert.Equal(t, hash, cursorHash)
+
+	for _, cursor := range []string{"12", "x:" + base64.StdEncoding.EncodeToString(hash), "12:AAAA"} {
+		_, _, err = parseHttpEventsCursor(cursor)
+		assert.EqualError(t, err, "Invalid Last-Event-ID")
+	}
+}
+
+func TestHttpEventsMaxClients(t *testing.T) {
+
+	events := newHttpEvents(nil, nil, nil, nil)
+	events.clientsCount = httpEventsMaxClientsPerServer
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.RemoteAddr = "127.0.0.1:1000"
+
+	w := httptest.NewRecorder()
+	events.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, int32(httpEventsMaxClientsPerServer), events.clientsCount)
+}
+
+func TestHttpEventsClosed(t *testing.T) {
+
+	serve := func(events *HttpEvents) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		req.RemoteAddr = "127.0.0.1:1000"
+
+		w := httptest.NewRecorder()
+		done := make(chan struct{})
+		go func() {
+			events.ServeHTTP(w, req)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("ServeHTTP is blocked")
+		}
+		return w
+	}
+
+	//processEvents exited before the client connected
+	events := newHttpEvents(nil, nil, nil, nil)
+	close(events.closeCn)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(events).Code)
+	assert.Equal(t, int32(0), events.clientsCount)
+
+	//processEvents exits while the client is connected, so nobody removes the client
+	events = newHttpEvents(nil, nil, nil, nil)
+	go func() {
+		<-events.newClientCn
+		close(events.closeCn)
+	}()
+	assert.Equal(t, http.StatusOK, serve(events).Code)
+	assert.Equal(t, int32(0), events.clientsCount)
+}
@@ -8,6 +8,9 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/recovery"
+	"time"
 )
 
 func (server *HttpServer) get(w http.ResponseWriter, req *http.Request) {
@@ -26,12 +29,18 @@ func (server *HttpServer) get(w http.ResponseWriter, req *http.Request) {
 	callback := server.GetMap[req.URL.Path]
 	if callback != nil {
 
+		if !server.RateLimiter.AllowHTTP(w, req, req.URL.Path[1:]) {
+			return
+		}
+
 		var args url.Values
 		if args, err = url.ParseQuery(req.URL.RawQuery); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		start := time.Now()
 		output, err = callback(args)
+		metrics.ObserveAPILatency("http", req.URL.Path[1:], time.Since(start))
 	} else {
 		err = errors.New("Unknown request")
 	}
@@ -73,7 +82,13 @@ func (server *HttpServer) post(w http.ResponseWriter, req *http.Request) {
 
 	callback := server.PostMap[req.URL.Path]
 	if callback != nil {
+
+		if !server.RateLimiter.AllowHTTP(w, req, req.URL.Path[1:]) {
+			return
+		}
+		start := time.Now()
 		output, err = callback(req.Body)
+		metrics.ObserveAPILatency("http", req.URL.Path[1:], time.Since(start))
 	} else {
 		err = errors.New("Unknown request")
 	}
@@ -100,8 +115,20 @@ func (server *HttpServer) post(w http.ResponseWriter, req *http.Request) {
 	w.Write(final)
 }
 
+func (server *HttpServer) metrics(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := metrics.Write(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (server *HttpServer) Initialize() {
 
+	http.HandleFunc("/metrics", server.metrics)
+
+	recovery.SafeGo(server.Events.processEvents)
+	http.Handle("/events", server.Events)
+
 	for key, callback := range server.Api.GetMap {
 		http.HandleFunc("/"+key, server.get)
 		server.GetMap["/"+key] = callback
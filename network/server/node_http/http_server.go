@@ -10,6 +10,7 @@ import (
 	"pandora-pay/network/api/api_websockets"
 	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/rate_limiter"
 	"pandora-pay/network/server/node_http_rpc"
 	"pandora-pay/network/websocks"
 	"pandora-pay/settings"
@@ -24,6 +25,8 @@ type HttpServer struct {
 	Api             *api_http.API
 	ApiWebsockets   *api_websockets.APIWebsockets
 	ApiStore        *api_common.APIStore
+	Events          *HttpEvents
+	RateLimiter     *rate_limiter.RateLimiter
 	GetMap          map[string]func(values url.Values) (any, error)
 	PostMap         map[string]func(values io.ReadCloser) (any, error)
 }
@@ -31,7 +34,7 @@ type HttpServer struct {
 func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, bannedNodes *banned_nodes.BannedNodes, knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*HttpServer, error) {
 
 	apiStore := api_common.NewAPIStore(chain)
-	apiCommon, err := api_common.NewAPICommon(knownNodes, mempool, chain, wallet, txsValidator, txsBuilder, apiStore)
+	apiCommon, err := api_common.NewAPICommon(knownNodes, bannedNodes, mempool, chain, wallet, txsValidator, txsBuilder, apiStore)
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +42,9 @@ func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, ba
 	apiWebsockets := api_websockets.NewWebsocketsAPI(apiStore, apiCommon, chain, settings, mempool, txsValidator)
 	api := api_http.NewAPI(apiStore, apiCommon, chain)
 
-	websockets := websocks.NewWebsockets(chain, mempool, settings, bannedNodes, api, apiWebsockets)
+	rateLimiter := rate_limiter.NewRateLimiter(bannedNodes)
+
+	websockets := websocks.NewWebsockets(chain, mempool, settings, bannedNodes, rateLimiter, api, apiWebsockets)
 
 	server := &HttpServer{
 		websocketServer: websocks.NewWebsocketServer(websockets, knownNodes),
@@ -49,9 +54,11 @@ func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, ba
 		Api:             api,
 		ApiWebsockets:   apiWebsockets,
 		ApiStore:        apiStore,
+		Events:          newHttpEvents(chain, mempool, apiStore, rateLimiter),
+		RateLimiter:     rateLimiter,
 	}
 
-	if err = node_http_rpc.InitializeRPC(apiCommon); err != nil {
+	if err = node_http_rpc.InitializeRPC(apiCommon, rateLimiter); err != nil {
 		return nil, err
 	}
 
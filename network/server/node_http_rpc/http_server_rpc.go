@@ -1,16 +1,228 @@
 package node_http_rpc
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"github.com/gorilla/rpc"
+	"io"
 	"net/http"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/rate_limiter"
+	"time"
 )
 
-type HTTPServerRPCRoutes struct {
-	apiCommon *api_common.APICommon
+const maxBatchSize = 100
+const maxRequestSize = 4 << 20 //bytes
+
+var nullID = json.RawMessage("null")
+
+//JSON-RPC 2.0 server for all the methods of api_common
+type RPCServer struct {
+	methods     map[string]*api_common.APIMethod
+	discover    *OpenRPCDocument
+	rateLimiter *rate_limiter.RateLimiter //optional
+}
+
+func newError(code int, message string, data any) *RPCError {
+	return &RPCError{code, message, data}
+}
+
+func (server *RPCServer) call(method *api_common.APIMethod, params json.RawMessage) (result any, rpcErr *RPCError) {
+
+	defer func() {
+		if err := recover(); err != nil {
+			result, rpcErr = nil, newError(RPC_INTERNAL_ERROR, "Internal error", fmt.Sprint(err))
+		}
+	}()
+
+	//by-position params are accepted only as a single object
+	params = bytes.TrimSpace(params)
+	if len(params) > 0 && params[0] == '[' {
+		var list []json.RawMessage
+		if err := json.Unmarshal(params, &list); err != nil {
+			return nil, newError(RPC_INVALID_PARAMS, "Invalid params", err.Error())
+		}
+		switch len(list) {
+		case 0:
+			params = nil
+		case 1:
+			params = bytes.TrimSpace(list[0])
+		default:
+			return nil, newError(RPC_INVALID_PARAMS, "Invalid params", "params must be an object")
+		}
+	}
+	if len(params) == 0 || bytes.Equal(params, nullID) {
+		params = []byte("{}")
+	}
+	if params[0] != '{' {
+		return nil, newError(RPC_INVALID_PARAMS, "Invalid params", "params must be an object")
+	}
+
+	//authenticated params are {"user", "pass", "req"} like in the HTTP POST
+	authenticated := false
+	if method.Authenticated {
+		auth := &api_types.APIAuthenticated[json.RawMessage]{}
+		if err := json.Unmarshal(params, auth); err != nil {
+			return nil, newError(RPC_INVALID_PARAMS, "Invalid params", err.Error())
+		}
+		authenticated = auth.CheckAuthenticated()
+		params = []byte("{}")
+		if auth.Data != nil {
+			params = *auth.Data
+		}
+	}
+
+	args := method.NewArgs()
+	if err := json.Unmarshal(params, args); err != nil {
+		return nil, newError(RPC_INVALID_PARAMS, "Invalid params", err.Error())
+	}
+
+	start := time.Now()
+	result, err := method.Call(args, authenticated)
+	metrics.ObserveAPILatency("rpc", method.Name, time.Since(start))
+	if err != nil {
+		return nil, newError(RPC_SERVER_ERROR, err.Error(), nil)
+	}
+
+	return result, nil
+}
+
+//returns nil for notifications
+func (server *RPCServer) processRequest(data json.RawMessage, remoteAddr string) *RPCResponse {
+
+	request := &RPCRequest{}
+	if err := json.Unmarshal(data, request); err != nil {
+		return &RPCResponse{"2.0", nil, newError(RPC_INVALID_REQUEST, "Invalid Request", err.Error()), nullID}
+	}
+
+	id := request.ID
+	if len(id) == 0 {
+		id = nullID
+	}
+
+	if request.Version != "2.0" || request.Method == nil {
+		return &RPCResponse{"2.0", nil, newError(RPC_INVALID_REQUEST, "Invalid Request", nil), id}
+	}
+
+	var result any
+	var rpcErr *RPCError
+
+	if *request.Method == "rpc.discover" {
+		result = server.discover
+	} else if method := server.methods[*request.Method]; method != nil {
+		if server.rateLimiter != nil {
+			if _, err := server.rateLimiter.Allow(remoteAddr, nil, nil, false, method.Name); err != nil {
+				rpcErr = newError(RPC_LIMIT_EXCEEDED, err.Error(), nil)
+			}
+		}
+		if rpcErr == nil {
+			result, rpcErr = server.call(method, request.Params)
+		}
+	} else {
+		rpcErr = newError(RPC_METHOD_NOT_FOUND, "Method not found", nil)
+	}
+
+	if len(request.ID) == 0 {
+		return nil
+	}
+
+	return &RPCResponse{"2.0", result, rpcErr, id}
+}
+
+//returns nil when there is nothing to answer
+func (server *RPCServer) Process(data []byte) any {
+	return server.process(data, "")
+}
+
+func (server *RPCServer) process(data []byte, remoteAddr string) any {
+
+	data = bytes.TrimSpace(data)
+
+	if len(data) == 0 || data[0] != '[' {
+		if !json.Valid(data) {
+			return &RPCResponse{"2.0", nil, newError(RPC_PARSE_ERROR, "Parse error", nil), nullID}
+		}
+		if response := server.processRequest(data, remoteAddr); response != nil {
+			return response
+		}
+		return nil
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return &RPCResponse{"2.0", nil, newError(RPC_PARSE_ERROR, "Parse error", err.Error()), nullID}
+	}
+	if len(batch) == 0 {
+		return &RPCResponse{"2.0", nil, newError(RPC_INVALID_REQUEST, "Invalid Request", "empty batch"), nullID}
+	}
+	if len(batch) > maxBatchSize {
+		return &RPCResponse{"2.0", nil, newError(RPC_INVALID_REQUEST, "Invalid Request", fmt.Sprintf("batch exceeds %d requests", maxBatchSize)), nullID}
+	}
+
+	responses := make([]*RPCResponse, 0, len(batch))
+	for _, data := range batch {
+		if response := server.processRequest(data, remoteAddr); response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
 }
 
-func InitializeRPC(apiCommon *api_common.APICommon) (err error) {
+func (server *RPCServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if req.Method != http.MethodPost {
+		http.Error(w, "JSON-RPC requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output := server.process(data, req.RemoteAddr)
+	if output == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	final, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(final)
+}
+
+func NewRPCServer(methods []*api_common.APIMethod, rateLimiter *rate_limiter.RateLimiter) *RPCServer {
+
+	server := &RPCServer{
+		make(map[string]*api_common.APIMethod),
+		newOpenRPCDocument(methods),
+		rateLimiter,
+	}
+
+	for _, method := range methods {
+		server.methods[method.Name] = method
+	}
+
+	return server
+}
+
+//the gorilla JSON-RPC 1.0 is kept in v1 for the existing clients
+func initializeRPCV1(apiCommon *api_common.APICommon, rateLimiter *rate_limiter.RateLimiter) (err error) {
 
 	s := rpc.NewServer()
 
@@ -19,7 +231,24 @@ func InitializeRPC(apiCommon *api_common.APICommon) (err error) {
 		return
 	}
 
-	http.Handle("/rpc/api/v1", s)
+	http.HandleFunc("/rpc/api/v1", func(w http.ResponseWriter, req *http.Request) {
+		if !rateLimiter.AllowHTTP(w, req, "rpc") {
+			return
+		}
+		req.Body = http.MaxBytesReader(w, req.Body, maxRequestSize)
+		s.ServeHTTP(w, req)
+	})
+
+	return
+}
+
+func InitializeRPC(apiCommon *api_common.APICommon, rateLimiter *rate_limiter.RateLimiter) (err error) {
+
+	if err = initializeRPCV1(apiCommon, rateLimiter); err != nil {
+		return
+	}
+
+	http.Handle("/rpc/api/v2", NewRPCServer(apiCommon.Methods, rateLimiter))
 
 	return
 }
@@ -0,0 +1,124 @@
+package node_http_rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/network/api/api_common"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testArgs struct {
+	Value int `json:"value"`
+}
+
+type testReply struct {
+	Value int `json:"value"`
+}
+
+func newTestServer() *RPCServer {
+	return NewRPCServer([]*api_common.APIMethod{
+		{
+			Name:    "double",
+			Params:  reflect.TypeOf(testArgs{}),
+			Result:  reflect.TypeOf(testReply{}),
+			NewArgs: func() any { return &testArgs{} },
+			Call: func(args any, authenticated bool) (any, error) {
+				if args.(*testArgs).Value < 0 {
+					return nil, errors.New("Negative value")
+				}
+				return &testReply{args.(*testArgs).Value * 2}, nil
+			},
+		},
+		{
+			Name:          "secret",
+			Authenticated: true,
+			Params:        reflect.TypeOf(testArgs{}),
+			Result:        reflect.TypeOf(testReply{}),
+			NewArgs:       func() any { return &testArgs{} },
+			Call: func(args any, authenticated bool) (any, error) {
+				if !authenticated {
+					return nil, errors.New("Unauthorized")
+				}
+				return &testReply{args.(*testArgs).Value}, nil
+			},
+		},
+	}, nil)
+}
+
+func process(t *testing.T, server *RPCServer, request string) string {
+	output := server.Process([]byte(request))
+	if output == nil {
+		return ""
+	}
+	data, err := json.Marshal(output)
+	assert.Nil(t, err)
+	return string(data)
+}
+
+func TestRPCServer(t *testing.T) {
+
+	server := newTestServer()
+
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"value":4},"id":1}`, process(t, server, `{"jsonrpc":"2.0","method":"double","params":{"value":2},"id":1}`))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"value":6},"id":"a"}`, process(t, server, `{"jsonrpc":"2.0","method":"double","params":[{"value":3}],"id":"a"}`))
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32000,"message":"Negative value"},"id":2}`, process(t, server, `{"jsonrpc":"2.0","method":"double","params":{"value":-1},"id":2}`))
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":3}`, process(t, server, `{"jsonrpc":"2.0","method":"missing","id":3}`))
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":4}`, process(t, server, `{"jsonrpc":"1.0","method":"double","id":4}`))
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":null}`, process(t, server, `{"jsonrpc":"2.0","method"`))
+	assert.Contains(t, process(t, server, `{"jsonrpc":"2.0","method":"double","params":{"value":"x"},"id":5}`), `"code":-32602`)
+
+	//notification
+	assert.Equal(t, "", process(t, server, `{"jsonrpc":"2.0","method":"double","params":{"value":2}}`))
+
+	//batch
+	assert.Equal(t, `[{"jsonrpc":"2.0","result":{"value":2},"id":1},{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request","data":"json: cannot unmarshal number into Go value of type node_http_rpc.RPCRequest"},"id":null}]`,
+		process(t, server, `[{"jsonrpc":"2.0","method":"double","params":{"value":1},"id":1},{"jsonrpc":"2.0","method":"double"},1]`))
+	assert.Contains(t, process(t, server, `[]`), `"code":-32600`)
+	assert.Equal(t, "", process(t, server, `[{"jsonrpc":"2.0","method":"double"}]`))
+
+	discover := process(t, server, `{"jsonrpc":"2.0","method":"rpc.discover","id":6}`)
+	assert.Contains(t, discover, `"openrpc":"1.2.6"`)
+	assert.Contains(t, discover, `{"name":"double","paramStructure":"by-name","params":[{"name":"value","schema":{"type":"integer"}}],"result":{"name":"result","schema":{"properties":{"value":{"type":"integer"}},"type":"object"}}}`)
+}
+
+func TestRPCServerAuthenticated(t *testing.T) {
+
+	config_auth.CONFIG_AUTH_USERS_MAP = map[string]*config_auth.ConfigAuth{"user": {"user", "pass"}}
+	defer func() {
+		config_auth.CONFIG_AUTH_USERS_MAP = map[string]*config_auth.ConfigAuth{}
+	}()
+
+	server := newTestServer()
+
+	//same params as the HTTP POST
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"value":7},"id":1}`, process(t, server, `{"jsonrpc":"2.0","method":"secret","params":{"user":"user","pass":"pass","req":{"value":7}},"id":1}`))
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32000,"message":"Unauthorized"},"id":2}`, process(t, server, `{"jsonrpc":"2.0","method":"secret","params":{"user":"user","pass":"wrong","req":{"value":7}},"id":2}`))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"value":0},"id":3}`, process(t, server, `{"jsonrpc":"2.0","method":"secret","params":{"user":"user","pass":"pass"},"id":3}`))
+
+	discover := process(t, server, `{"jsonrpc":"2.0","method":"rpc.discover","id":4}`)
+	assert.Contains(t, discover, `{"name":"secret","paramStructure":"by-name","params":[{"name":"user","schema":{"type":"string"}},{"name":"pass","schema":{"type":"string"}},{"name":"req","schema":{"properties":{"value":{"type":"integer"}},"type":"object"}}]`)
+}
+
+func TestRPCServerHTTP(t *testing.T) {
+
+	server := newTestServer()
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rpc/api/v2", strings.NewReader(`{"jsonrpc":"2.0","method":"double","params":{"value":2},"id":1}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"value":4},"id":1}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rpc/api/v2", strings.NewReader(strings.Repeat(" ", maxRequestSize+1))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rpc/api/v2", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,149 @@
+package node_http_rpc
+
+import (
+	"encoding/json"
+	"pandora-pay/config"
+	"pandora-pay/network/api/api_common"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenRPCContentDescriptor struct {
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema"`
+}
+
+type OpenRPCMethod struct {
+	Name           string                      `json:"name"`
+	Summary        string                      `json:"summary,omitempty"`
+	ParamStructure string                      `json:"paramStructure"`
+	Params         []*OpenRPCContentDescriptor `json:"params"`
+	Result         *OpenRPCContentDescriptor   `json:"result"`
+}
+
+type OpenRPCDocument struct {
+	OpenRPC string           `json:"openrpc"`
+	Info    *OpenRPCInfo     `json:"info"`
+	Methods []*OpenRPCMethod `json:"methods"`
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+//json name of a struct field. Empty when the field is skipped
+func jsonFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func jsonSchemaProperties(t reflect.Type, properties map[string]any, visited map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				jsonSchemaProperties(embedded, properties, visited)
+				continue
+			}
+		}
+		if name := jsonFieldName(field); name != "" {
+			properties[name] = jsonSchema(field.Type, visited)
+		}
+	}
+}
+
+func jsonSchema(t reflect.Type, visited map[reflect.Type]bool) map[string]any {
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	//custom json encoding
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem(), visited)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchema(t.Elem(), visited)}
+	case reflect.Struct:
+		if visited[t] {
+			return map[string]any{"type": "object"}
+		}
+		visited[t] = true
+		defer delete(visited, t)
+
+		properties := map[string]any{}
+		jsonSchemaProperties(t, properties, visited)
+		return map[string]any{"type": "object", "properties": properties}
+	}
+
+	return map[string]any{}
+}
+
+func newOpenRPCDocument(methods []*api_common.APIMethod) *OpenRPCDocument {
+
+	doc := &OpenRPCDocument{
+		"1.2.6",
+		&OpenRPCInfo{"PandoraPay", config.VERSION_STRING},
+		make([]*OpenRPCMethod, len(methods)),
+	}
+
+	for i, method := range methods {
+
+		params := make([]*OpenRPCContentDescriptor, 0)
+
+		paramsSchema := jsonSchema(method.Params, map[reflect.Type]bool{})
+		if method.Authenticated {
+			params = append(params, &OpenRPCContentDescriptor{"user", map[string]any{"type": "string"}}, &OpenRPCContentDescriptor{"pass", map[string]any{"type": "string"}}, &OpenRPCContentDescriptor{"req", paramsSchema})
+		} else if properties, ok := paramsSchema["properties"].(map[string]any); ok {
+			names := make([]string, 0, len(properties))
+			for name := range properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				params = append(params, &OpenRPCContentDescriptor{name, properties[name].(map[string]any)})
+			}
+		}
+		doc.Methods[i] = &OpenRPCMethod{
+			method.Name,
+			method.Summary,
+			"by-name",
+			params,
+			&OpenRPCContentDescriptor{"result", jsonSchema(method.Result, map[reflect.Type]bool{})},
+		}
+	}
+
+	return doc
+}
@@ -84,10 +84,14 @@ func NewTcpServer(bannedNodes *banned_nodes.BannedNodes, knownNodes *known_nodes
 		server.Address = address
 		server.URL = &url.URL{Scheme: "ws", Host: address + ":" + port, Path: "/ws"}
 		config.NETWORK_ADDRESS_URL_STRING = server.URL.String()
-		bannedNodes.Ban(server.URL, "", "You can't connect to yourself", 10*365*24*time.Hour)
+		if _, err = bannedNodes.Ban(server.URL.String(), "You can't connect to yourself", 10*365*24*time.Hour, false); err != nil {
+			return nil, err
+		}
 	}
 
-	bannedNodes.Ban(&url.URL{Scheme: "ws", Host: "127.0.0.1:" + port, Path: "/ws"}, "", "You can't connect to yourself", 10*365*24*time.Hour)
+	if _, err = bannedNodes.Ban((&url.URL{Scheme: "ws", Host: "127.0.0.1:" + port, Path: "/ws"}).String(), "You can't connect to yourself", 10*365*24*time.Hour, false); err != nil {
+		return nil, err
+	}
 
 	var certPath, keyPath string
 	if globals.Arguments["--tcp-server-tls-cert-file"] != nil {
@@ -10,7 +10,9 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/rate_limiter"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/recovery"
 	"sync"
@@ -46,6 +48,8 @@ type AdvancedConnection struct {
 	answerMapLock          *sync.Mutex
 	Subscriptions          *Subscriptions
 	ConnectionType         bool
+	rateLimiter            *rate_limiter.RateLimiter
+	rateLimit              *rate_limiter.TokenBucket
 	onClosedConnection     func(c *AdvancedConnection)
 }
 
@@ -187,7 +191,16 @@ func (c *AdvancedConnection) get(message *advanced_connection_types.AdvancedConn
 	route := string(message.Name)
 	var callback func(conn *AdvancedConnection, values []byte) (interface{}, error)
 	if callback = c.getMap[route]; callback != nil {
+		var banned bool
+		if banned, err = c.rateLimiter.Allow(c.RemoteAddr, c.rateLimit, c.KnownNode, c.ConnectionType, route); err != nil {
+			if banned {
+				c.Close("Banned")
+			}
+			return nil, err
+		}
+		start := time.Now()
 		output, err = callback(c, message.Data)
+		metrics.ObserveAPILatency("websocket", route, time.Since(start))
 		if err != nil {
 			return nil, err
 		}
@@ -320,6 +333,7 @@ func (c *AdvancedConnection) IncreaseKnownNodeScore() {
 			return
 		}
 
+		c.KnownNode.UpdateLastSeen()
 		if c.KnownNode.IncreaseScore(1, c.ConnectionType) {
 			break
 		}
@@ -327,7 +341,7 @@ func (c *AdvancedConnection) IncreaseKnownNodeScore() {
 
 }
 
-func NewAdvancedConnection(conn *websocket.Conn, remoteAddr string, knownNode *known_nodes.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (interface{}, error), connectionType bool, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(c *AdvancedConnection)) (*AdvancedConnection, error) {
+func NewAdvancedConnection(conn *websocket.Conn, remoteAddr string, knownNode *known_nodes.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (interface{}, error), connectionType bool, rateLimiter *rate_limiter.RateLimiter, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(c *AdvancedConnection)) (*AdvancedConnection, error) {
 
 	//making sure u is not collided with UUID_ALL and UUID_SKIP_ALL
 	uuid := advanced_connection_types.UUID(atomic.AddUint32(&uuidGenerator, 1))
@@ -353,6 +367,8 @@ func NewAdvancedConnection(conn *websocket.Conn, remoteAddr string, knownNode *k
 		&sync.Mutex{},
 		nil,
 		connectionType,
+		rateLimiter,
+		rateLimiter.NewConnectionBucket(),
 		onClosedConnection,
 	}
 	advancedConnection.Subscriptions = NewSubscriptions(advancedConnection, newSubscriptionCn, removeSubscriptionCn)
@@ -12,6 +12,12 @@ type ConnectionHandshake struct {
 	Network   uint64               `json:"network" msgpack:"network"`
 	Consensus config.ConsensusType `json:"consensus" msgpack:"consensus"`
 	URL       string               `json:"url" msgpack:"url"`
+	Prune     uint64               `json:"prune,omitempty" msgpack:"prune,omitempty"` //number of recent blocks stored by a pruned node. 0 means all blocks
+}
+
+//pruned nodes are not able to serve the blocks older than the last Prune blocks
+func (handshake *ConnectionHandshake) CanServeBlock(height, chainHeight uint64) bool {
+	return handshake.Prune == 0 || height+handshake.Prune >= chainHeight
 }
 
 func (handshake *ConnectionHandshake) ValidateHandshake() (*semver.Version, error) {
@@ -25,6 +25,11 @@ func (wserver *WebsocketServer) handleUpgradeConnection(w http.ResponseWriter, r
 		return
 	}
 
+	if wserver.websockets.rateLimiter.IsBanned(r.RemoteAddr) {
+		http.Error(w, "Banned", http.StatusForbidden)
+		return
+	}
+
 	var err error
 
 	var c *websocket.Conn
@@ -42,6 +47,7 @@ func (wserver *WebsocketServer) handleUpgradeConnection(w http.ResponseWriter, r
 	if conn.Handshake.URL != "" {
 		conn.KnownNode, err = wserver.knownNodes.AddKnownNode(conn.Handshake.URL, false)
 		if conn.KnownNode != nil {
+			conn.KnownNode.Connected(conn.Handshake.Version)
 			recovery.SafeGo(conn.IncreaseKnownNodeScore)
 		}
 	}
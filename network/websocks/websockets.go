@@ -18,6 +18,7 @@ import (
 	"pandora-pay/network/api/api_websockets"
 	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/rate_limiter"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/recovery"
@@ -36,6 +37,7 @@ type Websockets struct {
 	totalSockets                 int64 //use atomic
 	UpdateNewConnectionMulticast *multicast.MulticastChannel[*connection.AdvancedConnection]
 	bannedNodes                  *banned_nodes.BannedNodes
+	rateLimiter                  *rate_limiter.RateLimiter
 	subscriptions                *WebsocketSubscriptions
 	api                          *api_http.API
 	settings                     *settings.Settings
@@ -179,7 +181,7 @@ func (websockets *Websockets) closedConnection(conn *connection.AdvancedConnecti
 
 func (websockets *Websockets) NewConnection(c *websocket.Conn, remoteAddr string, knownNode *known_nodes.KnownNodeScored, connectionType bool) (*connection.AdvancedConnection, error) {
 
-	conn, err := connection.NewAdvancedConnection(c, remoteAddr, knownNode, websockets.ApiWebsockets.GetMap, connectionType, websockets.subscriptions.newSubscriptionCn, websockets.subscriptions.removeSubscriptionCn, websockets.closedConnection)
+	conn, err := connection.NewAdvancedConnection(c, remoteAddr, knownNode, websockets.ApiWebsockets.GetMap, connectionType, websockets.rateLimiter, websockets.subscriptions.newSubscriptionCn, websockets.subscriptions.removeSubscriptionCn, websockets.closedConnection)
 	if err != nil {
 		return nil, err
 	}
@@ -236,6 +238,10 @@ func (websockets *Websockets) InitializeConnection(conn *connection.AdvancedConn
 	conn.Handshake = handshakeReceived
 	conn.Version = version
 
+	if conn.KnownNode != nil {
+		conn.KnownNode.Connected(handshakeReceived.Version)
+	}
+
 	if conn.IsClosed.IsSet() {
 		return
 	}
@@ -259,7 +265,7 @@ func (websockets *Websockets) InitializeConnection(conn *connection.AdvancedConn
 	return nil
 }
 
-func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, settings *settings.Settings, bannedNodes *banned_nodes.BannedNodes, api *api_http.API, apiWebsockets *api_websockets.APIWebsockets) *Websockets {
+func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, settings *settings.Settings, bannedNodes *banned_nodes.BannedNodes, rateLimiter *rate_limiter.RateLimiter, api *api_http.API, apiWebsockets *api_websockets.APIWebsockets) *Websockets {
 
 	websockets := &Websockets{
 		AllAddresses:                 &generics.Map[string, *connection.AdvancedConnection]{},
@@ -271,6 +277,7 @@ func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, setti
 		ApiWebsockets:                apiWebsockets,
 		settings:                     settings,
 		bannedNodes:                  bannedNodes,
+		rateLimiter:                  rateLimiter,
 	}
 
 	websockets.subscriptions = newWebsocketSubscriptions(websockets, chain, mempool)
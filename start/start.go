@@ -2,6 +2,8 @@ package start
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"os"
 	"pandora-pay/address_balance_decryptor"
 	"pandora-pay/app"
@@ -15,6 +17,7 @@ import (
 	"pandora-pay/helpers/debugging_pprof"
 	"pandora-pay/mempool"
 	"pandora-pay/network"
+	"pandora-pay/recovery"
 	"pandora-pay/settings"
 	"pandora-pay/store"
 	"pandora-pay/testnet"
@@ -23,6 +26,7 @@ import (
 	"pandora-pay/wallet"
 	"runtime"
 	"strconv"
+	"strings"
 )
 
 func _startMain() (err error) {
@@ -85,10 +89,65 @@ func _startMain() (err error) {
 	if err = genesis.GenesisInit(app.Wallet.GetFirstAddressForDevnetGenesisAirdrop); err != nil {
 		return
 	}
+	if path := globals.Arguments["--import-snapshot"]; path != nil {
+		if globals.Arguments["--import-snapshot-signer"] == nil {
+			return errors.New("--import-snapshot requires the trusted --import-snapshot-signer")
+		}
+		var signer, checkpointHash []byte
+		if signer, err = base64.StdEncoding.DecodeString(globals.Arguments["--import-snapshot-signer"].(string)); err != nil {
+			return
+		}
+		if hash := globals.Arguments["--import-snapshot-hash"]; hash != nil {
+			if checkpointHash, err = base64.StdEncoding.DecodeString(hash.(string)); err != nil {
+				return
+			}
+		}
+		if err = app.Chain.ImportSnapshot(path.(string), signer, checkpointHash); err != nil {
+			return
+		}
+	}
+	if path := globals.Arguments["--replay-blocks"]; path != nil {
+		if err = app.Chain.ReplayBlocks(path.(string)); err != nil {
+			return
+		}
+	}
 	if err = app.Chain.InitializeChain(); err != nil {
 		return
 	}
 
+	if globals.Arguments["--verify-chain"] != nil {
+		var fromHeight uint64
+		if fromHeight, err = strconv.ParseUint(globals.Arguments["--verify-chain"].(string), 10, 64); err != nil {
+			return
+		}
+		if err = app.Chain.VerifyChainAndRepair(fromHeight, globals.Arguments["--verify-chain-rollback"] == true); err != nil {
+			return
+		}
+	}
+
+	if path := globals.Arguments["--import-blocks"]; path != nil {
+		if err = app.Chain.ImportBlocks(path.(string)); err != nil {
+			return
+		}
+	}
+
+	if dataArgument := globals.Arguments["--export-blocks"]; dataArgument != nil {
+		v := strings.SplitN(dataArgument.(string), ",", 3)
+		if len(v) != 3 {
+			return errors.New("--export-blocks argument must be \"from,to,path\"")
+		}
+		var start, end uint64
+		if start, err = strconv.ParseUint(v[0], 10, 64); err != nil {
+			return
+		}
+		if end, err = strconv.ParseUint(v[1], 10, 64); err != nil {
+			return
+		}
+		if err = app.Chain.ExportBlocks(start, end, v[2]); err != nil {
+			return
+		}
+	}
+
 	if runtime.GOARCH != "wasm" && globals.Arguments["--balance-decryptor-disable-init"] == false {
 		var tableSize int
 		if globals.Arguments["--balance-decryptor-table-size"] != nil {
@@ -140,6 +199,14 @@ func _startMain() (err error) {
 	}
 	globals.MainEvents.BroadcastEvent("main", "network initialized")
 
+	initMetrics()
+
+	recovery.SafeGo(func() {
+		if err := app.Mempool.LoadTxsFromStore(app.Chain.GetChainData().Height); err != nil {
+			gui.GUI.Error("Error loading mempool txs", err)
+		}
+	})
+
 	gui.GUI.Log("Main Loop")
 	globals.MainEvents.BroadcastEvent("main", "initialized")
 
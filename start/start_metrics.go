@@ -0,0 +1,71 @@
+package start
+
+import (
+	"math/big"
+	"pandora-pay/app"
+	"pandora-pay/helpers/metrics"
+)
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func initMetrics() {
+
+	metrics.AddGauge("pandora_chain_height", "Height of the blockchain", func() float64 {
+		return float64(app.Chain.GetChainData().Height)
+	})
+	metrics.AddGauge("pandora_chain_total_difficulty", "Total difficulty of the blockchain", func() float64 {
+		value, _ := new(big.Float).SetInt(app.Chain.GetChainData().BigTotalDifficulty).Float64()
+		return value
+	})
+
+	metrics.AddGauge("pandora_sync_time", "Unix time when the blockchain was last considered synced", func() float64 {
+		return float64(app.Chain.Sync.GetSyncData().SyncTime)
+	})
+	metrics.AddGauge("pandora_sync_blocks_changed_last_interval", "Blocks changed in the current sync interval", func() float64 {
+		return float64(app.Chain.Sync.GetSyncData().BlocksChangedLastInterval)
+	})
+	metrics.AddGauge("pandora_sync_blocks_changed_previous_interval", "Blocks changed in the previous sync interval", func() float64 {
+		return float64(app.Chain.Sync.GetSyncData().BlocksChangedPreviousInterval)
+	})
+	metrics.AddGauge("pandora_sync", "Is the blockchain synced", func() float64 {
+		return boolToFloat(app.Chain.Sync.GetSyncData().Sync)
+	})
+	metrics.AddGauge("pandora_sync_started", "Has the blockchain sync started", func() float64 {
+		return boolToFloat(app.Chain.Sync.GetSyncData().Started)
+	})
+
+	metrics.AddGauge("pandora_mempool_txs", "Number of transactions in mempool", func() float64 {
+		count, _ := app.Mempool.Txs.GetCountAndSize()
+		return float64(count)
+	})
+	metrics.AddGauge("pandora_mempool_bytes", "Size in bytes of the transactions in mempool", func() float64 {
+		_, size := app.Mempool.Txs.GetCountAndSize()
+		return float64(size)
+	})
+
+	metrics.AddGauge("pandora_txs_validator_queue", "Transactions waiting to be validated", func() float64 {
+		return float64(app.TxsValidator.GetPendingCount())
+	})
+	metrics.AddGauge("pandora_balance_decryptor_queue", "Balances waiting to be decrypted", func() float64 {
+		return float64(app.AddressBalanceDecryptor.GetPendingCount())
+	})
+	metrics.AddGauge("pandora_forging_hashrate", "Forging hashes per second", func() float64 {
+		return float64(app.Forging.GetHashrate())
+	})
+
+	metrics.AddGauge("pandora_websockets_clients", "Number of outgoing websocket connections", func() float64 {
+		return float64(app.Network.Websockets.GetClients())
+	})
+	metrics.AddGauge("pandora_websockets_servers", "Number of incoming websocket connections", func() float64 {
+		return float64(app.Network.Websockets.GetServerSockets())
+	})
+	metrics.AddGauge("pandora_banned_nodes", "Number of banned nodes", func() float64 {
+		return float64(app.Network.BannedNodes.GetCount())
+	})
+
+}
@@ -0,0 +1,70 @@
+package hash_map
+
+import (
+	"sort"
+)
+
+//iterates over all elements sorted by key, including the uncommitted changes
+//start is the first key visited (included) and it is ignored when empty
+func (hashMap *HashMap) Iterate(start string, reverse bool, callback func(key string, element HashMapElementSerializableInterface) error) (err error) {
+
+	prefix := hashMap.name + ":map:"
+
+	storeStart := ""
+	if start != "" {
+		storeStart = prefix + start
+	}
+
+	inRange := func(key string) bool {
+		if start == "" {
+			return true
+		}
+		if !reverse {
+			return key >= start
+		}
+		return key <= start
+	}
+
+	keys := make(map[string]bool)
+	if err = hashMap.Tx.IteratePrefix(prefix, storeStart, reverse, func(key string, value []byte) error {
+		keys[key[len(prefix):]] = true
+		return nil
+	}); err != nil {
+		return
+	}
+
+	for key := range hashMap.Committed {
+		if inRange(key) {
+			keys[key] = true
+		}
+	}
+	for key := range hashMap.Changes {
+		if inRange(key) {
+			keys[key] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	if !reverse {
+		sort.Strings(sorted)
+	} else {
+		sort.Sort(sort.Reverse(sort.StringSlice(sorted)))
+	}
+
+	var element HashMapElementSerializableInterface
+	for _, key := range sorted {
+		if element, err = hashMap.Get(key); err != nil {
+			return
+		}
+		if element != nil {
+			if err = callback(key, element); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
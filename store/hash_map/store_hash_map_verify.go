@@ -0,0 +1,77 @@
+package hash_map
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+//checks that the stored count, the stored elements and the :list: indexes agree
+//only the committed data is verified
+func (hashMap *HashMap) VerifyStore() (problems []string, err error) {
+
+	count := uint64(0)
+	if buffer := hashMap.Tx.Get(hashMap.name + ":count"); buffer != nil {
+		var p int
+		if count, p = binary.Uvarint(buffer); p <= 0 {
+			problems = append(problems, "count can not be read")
+		}
+	}
+
+	stored := uint64(0)
+	prefix := hashMap.name + ":exists:"
+	if err = hashMap.Tx.IteratePrefix(prefix, "", false, func(key string, value []byte) error {
+
+		stored += 1
+		k := key[len(prefix):]
+		keyStr := base64.StdEncoding.EncodeToString([]byte(k))
+
+		if hashMap.keyLength != 0 && len(k) != hashMap.keyLength {
+			problems = append(problems, "key "+keyStr+" length is invalid")
+		}
+		if !hashMap.Tx.Exists(hashMap.name + ":map:" + k) {
+			problems = append(problems, "key "+keyStr+" has no element stored")
+		}
+
+		if hashMap.Indexable {
+			data := hashMap.Tx.Get(hashMap.name + ":listKeys:" + k)
+			if data == nil {
+				problems = append(problems, "key "+keyStr+" has no index")
+				return nil
+			}
+			index, err := strconv.ParseUint(string(data), 10, 64)
+			if err != nil {
+				problems = append(problems, "key "+keyStr+" index can not be read")
+				return nil
+			}
+			if index >= count {
+				problems = append(problems, fmt.Sprintf("key %s index %d exceeds count %d", keyStr, index, count))
+			}
+			if listKey := hashMap.Tx.Get(hashMap.name + ":list:" + string(data)); string(listKey) != k {
+				problems = append(problems, fmt.Sprintf("key %s index %d is not matching the list", keyStr, index))
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return
+	}
+
+	if stored != count {
+		problems = append(problems, fmt.Sprintf("count %d is different than the %d stored elements", count, stored))
+	}
+
+	if hashMap.Indexable {
+		for i := uint64(0); i < count; i++ {
+			key := hashMap.Tx.Get(hashMap.name + ":list:" + strconv.FormatUint(i, 10))
+			if key == nil {
+				problems = append(problems, fmt.Sprintf("index %d is missing from the list", i))
+			} else if !hashMap.Tx.Exists(hashMap.name + ":exists:" + string(key)) {
+				problems = append(problems, fmt.Sprintf("index %d points to the missing key %s", i, base64.StdEncoding.EncodeToString(key)))
+			}
+		}
+	}
+
+	return
+}
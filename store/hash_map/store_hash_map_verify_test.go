@@ -0,0 +1,51 @@
+package hash_map_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/data_storage/registrations"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestVerifyStore(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("hashmap")
+	assert.NoError(t, err)
+
+	keys := make([][]byte, 3)
+	for i := range keys {
+		keys[i] = helpers.RandomBytes(cryptography.PublicKeySize)
+	}
+
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		regs := registrations.NewRegistrations(writer)
+		for _, key := range keys {
+			if _, err = regs.CreateNewRegistration(key, false, nil); err != nil {
+				return
+			}
+		}
+		return regs.CommitChanges()
+	}))
+
+	assert.NoError(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		problems, err := registrations.NewRegistrations(reader).VerifyStore()
+		assert.Empty(t, problems)
+		return err
+	}))
+
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Delete("registrations:list:1")
+		writer.Delete("registrations:map:" + string(keys[2]))
+		return nil
+	}))
+
+	assert.NoError(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		problems, err := registrations.NewRegistrations(reader).VerifyStore()
+		assert.Len(t, problems, 3)
+		return err
+	}))
+
+}
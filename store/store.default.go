@@ -10,6 +10,7 @@ import (
 	"pandora-pay/store/store_db/store_db_bunt"
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/store/store_db/store_db_pebble"
 )
 
 func createStoreNow(name, storeType string) (*Store, error) {
@@ -26,6 +27,8 @@ func createStoreNow(name, storeType string) (*Store, error) {
 		db, err = store_db_bunt.CreateStoreDBBunt(name, true)
 	case "memory":
 		db, err = store_db_memory.CreateStoreDBMemory(name)
+	case "pebble":
+		db, err = store_db_pebble.CreateStoreDBPebble(name)
 	default:
 		err = errors.New("Invalid --store-type argument")
 	}
@@ -46,9 +49,14 @@ func create_db() (err error) {
 
 	var prefix = ""
 
-	allowedStores := map[string]bool{"bolt": true, "bunt": true, "bunt-memory": true, "memory": true}
+	allowedStores := map[string]bool{"bolt": true, "bunt": true, "bunt-memory": true, "memory": true, "pebble": true}
 
-	if StoreBlockchain, err = createStoreNow(prefix+"/blockchain", getStoreType(globals.Arguments["--store-chain-type"].(string), allowedStores)); err != nil {
+	chainStoreType := getStoreType(globals.Arguments["--store-chain-type"].(string), allowedStores)
+	if globals.Arguments["--replay-blocks"] != nil { //the replay requires a fresh store
+		chainStoreType = "memory"
+	}
+
+	if StoreBlockchain, err = createStoreNow(prefix+"/blockchain", chainStoreType); err != nil {
 		return
 	}
 	if StoreWallet, err = createStoreNow(prefix+"/wallet", getStoreType(globals.Arguments["--store-wallet-type"].(string), allowedStores)); err != nil {
@@ -1,6 +1,7 @@
 package store_db_bolt
 
 import (
+	"bytes"
 	bolt "go.etcd.io/bbolt"
 	"pandora-pay/helpers"
 	"pandora-pay/store/store_db/store_db_interface"
@@ -36,3 +37,38 @@ func (tx *StoreDBBoltTransaction) Exists(key string) bool {
 func (tx *StoreDBBoltTransaction) Delete(key string) {
 	tx.bucket.Delete([]byte(key))
 }
+
+func (tx *StoreDBBoltTransaction) IteratePrefix(prefix, start string, reverse bool, callback func(key string, value []byte) error) error {
+
+	lower, upper, ok := store_db_interface.IteratePrefixBounds(prefix, start, reverse)
+	if !ok {
+		return nil
+	}
+
+	c := tx.bucket.Cursor()
+
+	var k, v []byte
+	if !reverse {
+		for k, v = c.Seek(lower); k != nil && (upper == nil || bytes.Compare(k, upper) < 0); k, v = c.Next() {
+			if err := callback(string(k), helpers.CloneBytes(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if upper == nil {
+		k, v = c.Last()
+	} else if k, v = c.Seek(upper); k == nil {
+		k, v = c.Last()
+	} else {
+		k, v = c.Prev()
+	}
+
+	for ; k != nil && bytes.Compare(k, lower) >= 0; k, v = c.Prev() {
+		if err := callback(string(k), helpers.CloneBytes(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
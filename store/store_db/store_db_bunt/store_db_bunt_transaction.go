@@ -41,7 +41,53 @@ func (tx *StoreDBBuntTransaction) Exists(key string) bool {
 
 func (tx *StoreDBBuntTransaction) Delete(key string) {
 	_, err := tx.buntTx.Delete(key)
-	if err != buntdb.ErrNotFound {
+	if err != nil && err != buntdb.ErrNotFound {
 		panic(err)
 	}
 }
+
+func (tx *StoreDBBuntTransaction) IteratePrefix(prefix, start string, reverse bool, callback func(key string, value []byte) error) (err error) {
+
+	lower, upper, ok := store_db_interface.IteratePrefixBounds(prefix, start, reverse)
+	if !ok {
+		return nil
+	}
+
+	//AscendKeys is not used because the keys are binary and they could contain pattern characters
+	if !reverse {
+		if err2 := tx.buntTx.AscendGreaterOrEqual("", string(lower), func(key, value string) bool {
+			if upper != nil && key >= string(upper) {
+				return false
+			}
+			//value is cloned
+			err = callback(key, []byte(value))
+			return err == nil
+		}); err2 != nil {
+			return err2
+		}
+		return
+	}
+
+	iterator := func(key, value string) bool {
+		if upper != nil && key == string(upper) { //DescendLessOrEqual includes the pivot
+			return true
+		}
+		if key < string(lower) {
+			return false
+		}
+		//value is cloned
+		err = callback(key, []byte(value))
+		return err == nil
+	}
+
+	var err2 error
+	if upper == nil {
+		err2 = tx.buntTx.Descend("", iterator)
+	} else {
+		err2 = tx.buntTx.DescendLessOrEqual("", string(upper), iterator)
+	}
+	if err2 != nil {
+		return err2
+	}
+	return
+}
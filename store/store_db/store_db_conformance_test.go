@@ -0,0 +1,309 @@
+package store_db_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"pandora-pay/store/store_db/store_db_bolt"
+	"pandora-pay/store/store_db/store_db_bunt"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/store/store_db/store_db_pebble"
+	"testing"
+)
+
+var backends = map[string]func(name string) (store_db_interface.StoreDBInterface, error){
+	"bolt": func(name string) (store_db_interface.StoreDBInterface, error) {
+		return store_db_bolt.CreateStoreDBBolt(name)
+	},
+	"bunt": func(name string) (store_db_interface.StoreDBInterface, error) {
+		return store_db_bunt.CreateStoreDBBunt(name, false)
+	},
+	"bunt-memory": func(name string) (store_db_interface.StoreDBInterface, error) {
+		return store_db_bunt.CreateStoreDBBunt(name, true)
+	},
+	"memory": func(name string) (store_db_interface.StoreDBInterface, error) {
+		return store_db_memory.CreateStoreDBMemory(name)
+	},
+	"pebble": func(name string) (store_db_interface.StoreDBInterface, error) {
+		return store_db_pebble.CreateStoreDBPebble(name)
+	},
+}
+
+var conformanceTests = map[string]func(t *testing.T, db store_db_interface.StoreDBInterface){
+	"PutGetDelete":      testPutGetDelete,
+	"Writable":          testWritable,
+	"RollbackOnError":   testRollbackOnError,
+	"ReadOwnWrites":     testReadOwnWrites,
+	"ClonedValues":      testClonedValues,
+	"IteratePrefix":     testIteratePrefix,
+	"IteratePrefixStop": testIteratePrefixStop,
+	"IterateOrdered":    testIterateOrdered,
+}
+
+//every backend must behave the same way
+func TestStoreDBConformance(t *testing.T) {
+
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+	defer os.Chdir(cwd)
+
+	//the disk backends are creating the files in ./store
+	assert.Nil(t, os.Chdir(t.TempDir()))
+
+	for backendName, create := range backends {
+		for testName, test := range conformanceTests {
+			t.Run(backendName+"/"+testName, func(t *testing.T) {
+				db, err := create("/" + testName)
+				assert.Nil(t, err)
+				defer db.Close()
+				test(t, db)
+			})
+		}
+	}
+}
+
+func testPutGetDelete(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("a", []byte{1, 2, 3})
+		tx.Put("b", []byte{4})
+		tx.Delete("missing") //deleting a missing key is allowed
+		return nil
+	}))
+
+	assert.Nil(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.Equal(t, []byte{1, 2, 3}, tx.Get("a"))
+		assert.True(t, tx.Exists("a"))
+		assert.Equal(t, []byte{4}, tx.Get("b"))
+		assert.Nil(t, tx.Get("missing"))
+		assert.False(t, tx.Exists("missing"))
+		return nil
+	}))
+
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Delete("a")
+		tx.Put("b", []byte{5, 6})
+		return nil
+	}))
+
+	assert.Nil(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.Nil(t, tx.Get("a"))
+		assert.False(t, tx.Exists("a"))
+		assert.Equal(t, []byte{5, 6}, tx.Get("b"))
+		return nil
+	}))
+}
+
+func testWritable(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+	assert.Nil(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.False(t, tx.IsWritable())
+		return nil
+	}))
+
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.True(t, tx.IsWritable())
+		return nil
+	}))
+}
+
+func testRollbackOnError(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("a", []byte{1})
+		return nil
+	}))
+
+	errRollback := errors.New("Rollback")
+	assert.Equal(t, errRollback, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("a", []byte{2})
+		tx.Put("b", []byte{3})
+		return errRollback
+	}))
+
+	assert.Equal(t, errRollback, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Delete("a")
+		return errRollback
+	}))
+
+	assert.Nil(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.Equal(t, []byte{1}, tx.Get("a"))
+		assert.Nil(t, tx.Get("b"))
+		return nil
+	}))
+
+	assert.Equal(t, errRollback, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		return errRollback
+	}))
+}
+
+func testReadOwnWrites(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("a", []byte{1})
+		assert.Equal(t, []byte{1}, tx.Get("a"))
+		assert.True(t, tx.Exists("a"))
+
+		tx.Put("a", []byte{2})
+		assert.Equal(t, []byte{2}, tx.Get("a"))
+
+		tx.Delete("a")
+		assert.Nil(t, tx.Get("a"))
+		assert.False(t, tx.Exists("a"))
+
+		tx.Put("a", []byte{3})
+		return nil
+	}))
+
+	assert.Nil(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.Equal(t, []byte{3}, tx.Get("a"))
+		return nil
+	}))
+}
+
+func testClonedValues(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+	value := []byte{1, 2, 3}
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("a", value)
+		value[0] = 9
+
+		out := tx.Get("a")
+		out[1] = 9
+		return nil
+	}))
+
+	assert.Nil(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		out := tx.Get("a")
+		assert.Equal(t, []byte{1, 2, 3}, out)
+		out[2] = 9
+		assert.Equal(t, []byte{1, 2, 3}, tx.Get("a"))
+		return nil
+	}))
+}
+
+func testIteratePrefix(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("map:b", []byte{2})
+		tx.Put("map:a", []byte{1})
+		tx.Put("map:*\xff", []byte{0})
+		tx.Put("map:d", []byte{4})
+		tx.Put("ma", []byte{5})
+		tx.Put("mapz", []byte{6})
+		tx.Put("other:a", []byte{7})
+		return nil
+	}))
+
+	type entry struct {
+		key   string
+		value []byte
+	}
+
+	iterate := func(tx store_db_interface.StoreDBTransactionInterface, prefix string) (list []entry) {
+		assert.Nil(t, tx.IteratePrefix(prefix, "", false, func(key string, value []byte) error {
+			list = append(list, entry{key, value})
+			return nil
+		}))
+		return
+	}
+
+	assert.Nil(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.Equal(t, []entry{{"map:*\xff", []byte{0}}, {"map:a", []byte{1}}, {"map:b", []byte{2}}, {"map:d", []byte{4}}}, iterate(tx, "map:"))
+		assert.Nil(t, iterate(tx, "missing:"))
+		return nil
+	}))
+
+	//uncommitted changes are visible
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Delete("map:a")
+		tx.Put("map:c", []byte{3})
+		tx.Put("map:d", []byte{8})
+		assert.Equal(t, []entry{{"map:*\xff", []byte{0}}, {"map:b", []byte{2}}, {"map:c", []byte{3}}, {"map:d", []byte{8}}}, iterate(tx, "map:"))
+		return nil
+	}))
+}
+
+func testIteratePrefixStop(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("list:0", []byte{0})
+		tx.Put("list:1", []byte{1})
+		tx.Put("list:2", []byte{2})
+		return nil
+	}))
+
+	errStop := errors.New("Stop")
+	assert.Nil(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		count := 0
+		assert.Equal(t, errStop, tx.IteratePrefix("list:", "", false, func(key string, value []byte) error {
+			count++
+			if count == 2 {
+				return errStop
+			}
+			return nil
+		}))
+		assert.Equal(t, 2, count)
+		return nil
+	}))
+}
+
+func testIterateOrdered(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("a", []byte{0})
+		tx.Put("k:1", []byte{1})
+		tx.Put("k:3", []byte{3})
+		tx.Put("k:5", []byte{5})
+		tx.Put("k:7", []byte{7})
+		tx.Put("l", []byte{8})
+		tx.Put("\xff\xff", []byte{9})
+		return nil
+	}))
+
+	keys := func(tx store_db_interface.StoreDBTransactionInterface, prefix, start string, reverse bool) (list []string) {
+		assert.Nil(t, tx.IteratePrefix(prefix, start, reverse, func(key string, value []byte) error {
+			list = append(list, key)
+			return nil
+		}))
+		return
+	}
+
+	check := func(tx store_db_interface.StoreDBTransactionInterface) {
+		assert.Equal(t, []string{"k:1", "k:3", "k:5", "k:7"}, keys(tx, "k:", "", false))
+		assert.Equal(t, []string{"k:7", "k:5", "k:3", "k:1"}, keys(tx, "k:", "", true))
+
+		assert.Equal(t, []string{"k:3", "k:5", "k:7"}, keys(tx, "k:", "k:3", false))
+		assert.Equal(t, []string{"k:5", "k:7"}, keys(tx, "k:", "k:4", false))
+		assert.Equal(t, []string{"k:3", "k:1"}, keys(tx, "k:", "k:3", true))
+		assert.Equal(t, []string{"k:3", "k:1"}, keys(tx, "k:", "k:4", true))
+
+		//start outside the prefix
+		assert.Equal(t, []string{"k:1", "k:3", "k:5", "k:7"}, keys(tx, "k:", "a", false))
+		assert.Nil(t, keys(tx, "k:", "l", false))
+		assert.Nil(t, keys(tx, "k:", "a", true))
+		assert.Equal(t, []string{"k:7", "k:5", "k:3", "k:1"}, keys(tx, "k:", "l", true))
+
+		//empty prefix and keys without upper bound
+		assert.Equal(t, []string{"a", "k:1", "k:3", "k:5", "k:7", "l", "\xff\xff"}, keys(tx, "", "", false))
+		assert.Equal(t, []string{"\xff\xff", "l", "k:7"}, keys(tx, "", "", true)[:3])
+		assert.Equal(t, []string{"\xff\xff"}, keys(tx, "\xff", "", true))
+		assert.Equal(t, []string{"l", "\xff\xff"}, keys(tx, "", "l", false))
+	}
+
+	assert.Nil(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		check(tx)
+		return nil
+	}))
+
+	//the same order must be kept with uncommitted changes
+	assert.Nil(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Delete("k:3")
+		tx.Put("k:4", []byte{4})
+		assert.Equal(t, []string{"k:7", "k:5", "k:4", "k:1"}, keys(tx, "k:", "", true))
+		assert.Equal(t, []string{"k:4", "k:5", "k:7"}, keys(tx, "k:", "k:3", false))
+		assert.Equal(t, []string{"k:4", "k:1"}, keys(tx, "k:", "k:4", true))
+		return nil
+	}))
+}
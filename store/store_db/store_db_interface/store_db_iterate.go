@@ -0,0 +1,39 @@
+package store_db_interface
+
+import "bytes"
+
+//returns the smallest key greater than all keys with the given prefix. nil means there is no upper bound
+func PrefixUpperBound(prefix []byte) []byte {
+	upper := make([]byte, len(prefix))
+	copy(upper, prefix)
+	for i := len(upper) - 1; i >= 0; i-- {
+		upper[i]++
+		if upper[i] != 0 {
+			return upper[:i+1]
+		}
+	}
+	return nil //prefix is empty or 0xFF...FF
+}
+
+//returns the range [lower, upper) of the keys visited by IteratePrefix. upper nil means unbounded. ok is false when the range is empty
+func IteratePrefixBounds(prefix, start string, reverse bool) (lower, upper []byte, ok bool) {
+
+	lower = []byte(prefix)
+	upper = PrefixUpperBound(lower)
+
+	if start != "" {
+		if !reverse {
+			if start > prefix {
+				lower = []byte(start)
+			}
+		} else {
+			//the start key is included
+			end := append([]byte(start), 0)
+			if upper == nil || bytes.Compare(end, upper) < 0 {
+				upper = end
+			}
+		}
+	}
+
+	return lower, upper, upper == nil || bytes.Compare(lower, upper) < 0
+}
@@ -5,5 +5,8 @@ type StoreDBTransactionInterface interface {
 	Get(key string) []byte
 	Exists(key string) bool
 	Delete(key string)
+	//iterates the keys with the given prefix ordered ascending, or descending when reverse is true
+	//start is the first key visited (included) and it is ignored when empty. The callback must not modify the store
+	IteratePrefix(prefix, start string, reverse bool, callback func(key string, value []byte) error) error
 	IsWritable() bool
 }
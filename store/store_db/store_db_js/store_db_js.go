@@ -40,15 +40,11 @@ func (store *StoreDBJS) Update(callback func(dbTx store_db_interface.StoreDBTran
 		write:   true,
 	}
 
-	err := callback(tx)
-
-	if err == nil {
-		if err = tx.writeTx(); err != nil {
-			return err
-		}
+	if err := callback(tx); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.writeTx()
 }
 
 func CreateStoreDBJS(name string) (*StoreDBJS, error) {
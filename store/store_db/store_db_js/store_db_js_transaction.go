@@ -6,6 +6,7 @@ import (
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
 	"syscall/js"
 )
 
@@ -88,6 +89,90 @@ func (tx *StoreDBJSTransaction) Delete(key string) {
 	tx.local.Store(key, &StoreDBJSTransactionData{nil, "del"})
 }
 
+func (tx *StoreDBJSTransaction) readKeys() ([]string, error) {
+
+	respCh := make(chan []string)
+	defer close(respCh)
+
+	errCh := make(chan error)
+	defer close(errCh)
+
+	promise := tx.jsStore.Call("keys")
+
+	promise.Call("then", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		var result []string
+		if !args[0].IsNull() && !args[0].IsUndefined() {
+			result = make([]string, args[0].Get("length").Int())
+			for i := range result {
+				result[i] = args[0].Index(i).String()
+			}
+		}
+		respCh <- result
+		return nil
+	}), js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		errCh <- fmt.Errorf("error reading keys js db %s", args[0].Get("message").String())
+		return nil
+	}))
+
+	select {
+	case resp := <-respCh:
+		return resp, nil
+	case err := <-errCh:
+		return nil, err
+	}
+}
+
+func (tx *StoreDBJSTransaction) IteratePrefix(prefix, start string, reverse bool, callback func(key string, value []byte) error) error {
+
+	lower, upper, ok := store_db_interface.IteratePrefixBounds(prefix, start, reverse)
+	if !ok {
+		return nil
+	}
+
+	inRange := func(key string) bool {
+		return key >= string(lower) && (upper == nil || key < string(upper))
+	}
+
+	list, err := tx.readKeys()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]bool)
+	for _, key := range list {
+		if inRange(key) {
+			keys[key] = true
+		}
+	}
+	tx.local.Range(func(key string, data *StoreDBJSTransactionData) bool {
+		if inRange(key) {
+			keys[key] = true
+		}
+		return true
+	})
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	if !reverse {
+		sort.Strings(sorted)
+	} else {
+		sort.Sort(sort.Reverse(sort.StringSlice(sorted)))
+	}
+
+	for _, key := range sorted {
+		//Get is used to take into consideration the local changes
+		if value := tx.Get(key); value != nil {
+			if err := callback(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (tx *StoreDBJSTransaction) writeTx() error {
 
 	if !tx.write {
@@ -5,6 +5,7 @@ import (
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
 )
 
 type StoreDBMemoryTransactionData struct {
@@ -40,9 +41,9 @@ func (tx *StoreDBMemoryTransaction) Get(key string) []byte {
 		return helpers.CloneBytes(data.value)
 	}
 
-	resp := helpers.CloneBytes(tx.store[key])
+	resp := tx.store[key]
 	tx.local.Store(key, &StoreDBMemoryTransactionData{resp, "get"})
-	return resp
+	return helpers.CloneBytes(resp)
 }
 
 func (tx *StoreDBMemoryTransaction) Exists(key string) bool {
@@ -60,6 +61,52 @@ func (tx *StoreDBMemoryTransaction) Delete(key string) {
 	tx.local.Store(key, &StoreDBMemoryTransactionData{nil, "del"})
 }
 
+func (tx *StoreDBMemoryTransaction) IteratePrefix(prefix, start string, reverse bool, callback func(key string, value []byte) error) error {
+
+	lower, upper, ok := store_db_interface.IteratePrefixBounds(prefix, start, reverse)
+	if !ok {
+		return nil
+	}
+
+	inRange := func(key string) bool {
+		return key >= string(lower) && (upper == nil || key < string(upper))
+	}
+
+	keys := make(map[string]bool)
+	for key := range tx.store {
+		if inRange(key) {
+			keys[key] = true
+		}
+	}
+	tx.local.Range(func(key string, data *StoreDBMemoryTransactionData) bool {
+		if inRange(key) {
+			keys[key] = true
+		}
+		return true
+	})
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	if !reverse {
+		sort.Strings(sorted)
+	} else {
+		sort.Sort(sort.Reverse(sort.StringSlice(sorted)))
+	}
+
+	for _, key := range sorted {
+		//Get is used to take into consideration the local changes
+		if value := tx.Get(key); value != nil {
+			if err := callback(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (tx *StoreDBMemoryTransaction) writeTx() error {
 
 	if !tx.write {
@@ -0,0 +1,78 @@
+//go:build !wasm
+// +build !wasm
+
+package store_db_pebble
+
+import (
+	"github.com/cockroachdb/pebble"
+	"os"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sync"
+)
+
+type StoreDBPebble struct {
+	store_db_interface.StoreDBInterface
+	DB    *pebble.DB
+	Name  []byte
+	mutex *sync.Mutex //pebble batches are not isolated, only one writer is allowed
+}
+
+func (store *StoreDBPebble) Close() error {
+	return store.DB.Close()
+}
+
+func (store *StoreDBPebble) View(callback func(dbTx store_db_interface.StoreDBTransactionInterface) error) error {
+
+	snapshot := store.DB.NewSnapshot()
+	defer snapshot.Close()
+
+	tx := &StoreDBPebbleTransaction{
+		reader: snapshot,
+	}
+	return callback(tx)
+}
+
+func (store *StoreDBPebble) Update(callback func(dbTx store_db_interface.StoreDBTransactionInterface) error) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	batch := store.DB.NewIndexedBatch()
+	defer batch.Close()
+
+	tx := &StoreDBPebbleTransaction{
+		reader: batch,
+		batch:  batch,
+		write:  true,
+	}
+
+	if err := callback(tx); err != nil {
+		return err
+	}
+
+	return batch.Commit(pebble.Sync)
+}
+
+func CreateStoreDBPebble(name string) (*StoreDBPebble, error) {
+
+	var err error
+
+	store := &StoreDBPebble{
+		Name:  []byte(name),
+		mutex: &sync.Mutex{},
+	}
+
+	prefix := "./store"
+	if _, err = os.Stat(prefix); os.IsNotExist(err) {
+		if err = os.Mkdir(prefix, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	// Open the my.store data directory in your current directory.
+	// It will be created if it doesn't exist.
+	if store.DB, err = pebble.Open(prefix+name+"_store"+".pebble", &pebble.Options{}); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
@@ -0,0 +1,105 @@
+//go:build !wasm
+// +build !wasm
+
+package store_db_pebble
+
+import (
+	"github.com/cockroachdb/pebble"
+	"io"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type pebbleReader interface {
+	Get(key []byte) ([]byte, io.Closer, error)
+	NewIter(o *pebble.IterOptions) *pebble.Iterator
+}
+
+type StoreDBPebbleTransaction struct {
+	store_db_interface.StoreDBTransactionInterface
+	reader pebbleReader
+	batch  *pebble.Batch
+	write  bool
+}
+
+func (tx *StoreDBPebbleTransaction) IsWritable() bool {
+	return tx.write
+}
+
+func (tx *StoreDBPebbleTransaction) Put(key string, value []byte) {
+	if !tx.write {
+		panic("Transaction is not writeable")
+	}
+	//value is copied into the batch
+	if err := tx.batch.Set([]byte(key), value, nil); err != nil {
+		panic(err)
+	}
+}
+
+//pebble requires the data to be cloned because it is valid only until the closer is closed
+func (tx *StoreDBPebbleTransaction) Get(key string) []byte {
+	data, closer, err := tx.reader.Get([]byte(key))
+	if err != nil {
+		if err != pebble.ErrNotFound {
+			panic(err)
+		}
+		return nil
+	}
+	defer closer.Close()
+	return helpers.CloneBytes(data)
+}
+
+func (tx *StoreDBPebbleTransaction) Exists(key string) bool {
+	_, closer, err := tx.reader.Get([]byte(key))
+	if err != nil {
+		if err != pebble.ErrNotFound {
+			panic(err)
+		}
+		return false
+	}
+	closer.Close()
+	return true
+}
+
+func (tx *StoreDBPebbleTransaction) Delete(key string) {
+	if !tx.write {
+		panic("Transaction is not writeable")
+	}
+	if err := tx.batch.Delete([]byte(key), nil); err != nil {
+		panic(err)
+	}
+}
+
+func (tx *StoreDBPebbleTransaction) IteratePrefix(prefix, start string, reverse bool, callback func(key string, value []byte) error) (err error) {
+
+	lower, upper, ok := store_db_interface.IteratePrefixBounds(prefix, start, reverse)
+	if !ok {
+		return nil
+	}
+
+	it := tx.reader.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: upper,
+	})
+	defer func() {
+		if err2 := it.Close(); err == nil {
+			err = err2
+		}
+	}()
+
+	if !reverse {
+		for it.First(); it.Valid(); it.Next() {
+			if err = callback(string(it.Key()), helpers.CloneBytes(it.Value())); err != nil {
+				return
+			}
+		}
+	} else {
+		for it.Last(); it.Valid(); it.Prev() {
+			if err = callback(string(it.Key()), helpers.CloneBytes(it.Value())); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
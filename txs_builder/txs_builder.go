@@ -59,8 +59,8 @@ func (builder *TxsBuilder) getWalletAddresses(senders []string) ([]*wallet_addre
 		if sendersWalletAddress[i], err = builder.wallet.GetWalletAddressByEncodedAddress(senderAddress, true); err != nil {
 			return nil, err
 		}
-		if sendersWalletAddress[i].PrivateKey == nil {
-			return nil, fmt.Errorf("Can't be used for transactions as the private key is missing for sender %s", senderAddress)
+		if err = sendersWalletAddress[i].CheckCanSpend(); err != nil {
+			return nil, fmt.Errorf("%s for sender %s", err.Error(), senderAddress)
 		}
 	}
 
@@ -23,6 +23,8 @@ import (
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/txs_builder/wizard"
+	"strings"
+	"time"
 )
 
 func (builder *TxsBuilder) showWarningIfNotSyncCLI() {
@@ -84,6 +86,7 @@ func (builder *TxsBuilder) readAddress(text string, leaveEmpty bool) (address *a
 	return
 }
 
+//accepts pandorapay: payment requests as well
 func (builder *TxsBuilder) readAddressOptional(text string, assetId []byte, allowRandomAddress bool) (address *addresses.Address, addressEncoded string, amount uint64, err error) {
 
 	text2 := text
@@ -97,15 +100,35 @@ func (builder *TxsBuilder) readAddressOptional(text string, assetId []byte, allo
 			return
 		}
 
-		address, err = addresses.DecodeAddr(str)
-		if err != nil {
+		if strings.HasPrefix(str, addresses.PAYMENT_REQUEST_URI_SCHEME+":") {
+			var req *addresses.PaymentRequest
+			if req, err = addresses.DecodePaymentRequestURI(str); err != nil {
+				gui.GUI.OutputWrite("Invalid Payment Request")
+				continue
+			}
+			if req.IsExpired(uint64(time.Now().Unix())) {
+				gui.GUI.OutputWrite("Payment Request expired")
+				continue
+			}
+			if req.Memo != "" {
+				gui.GUI.OutputWrite("Memo: " + req.Memo)
+			}
+			address = req.Address
+		} else if address, err = addresses.DecodeAddr(str); err != nil {
 			gui.GUI.OutputWrite("Invalid Address")
 			continue
 		}
 		break
 	}
 
-	if amount, err = builder.readAmount(assetId, text+" Amount"); err != nil {
+	if address.IsIntegratedPaymentAsset() && !bytes.Equal(address.PaymentAsset, assetId) {
+		return nil, "", 0, errors.New("The asset requested is different")
+	}
+
+	if address.IsIntegratedAmount() {
+		amount = address.PaymentAmount
+		gui.GUI.OutputWrite(fmt.Sprintf("%s Amount requested: %d", text, amount))
+	} else if amount, err = builder.readAmount(assetId, text+" Amount"); err != nil {
 		return
 	}
 
@@ -189,6 +212,34 @@ func (builder *TxsBuilder) initCLI() {
 	cliPrivateTransfer := func(cmd string, ctx context.Context) (err error) {
 		builder.showWarningIfNotSyncCLI()
 
+		//the pending tx is rebuilt from its own transfers, only the fee is asked
+		if replaceTxHash := gui.GUI.OutputReadBytes("Pending Tx Hash to be replaced with a higher fee. Leave empty for a new transfer", func(value []byte) bool {
+			return len(value) == 0 || len(value) == cryptography.HashSize
+		}); len(replaceTxHash) > 0 {
+
+			replaceTx := builder.mempool.Txs.Get(string(replaceTxHash))
+			if replaceTx == nil {
+				return errors.New("Transaction to be replaced was not found in mempool")
+			}
+			replaceTxBase, ok := replaceTx.Tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+			if !ok {
+				return errors.New("Transaction to be replaced is not a zether transaction")
+			}
+
+			fee := builder.readZetherFee(replaceTxBase.Payloads[0].Asset)
+			propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+			tx, err := builder.CreateZetherReplacementTx(replaceTxHash, fee, propagate, true, true, ctx, func(status string) {
+				gui.GUI.OutputWrite(status)
+			})
+			if err != nil {
+				return err
+			}
+
+			gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+			return builder.exportMultisigSessionsCLI(tx)
+		}
+
 		txData := &TxBuilderCreateZetherTxData{
 			Payloads: []*TxBuilderCreateZetherTxPayload{{}},
 		}
@@ -216,7 +267,7 @@ func (builder *TxsBuilder) initCLI() {
 		}
 
 		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
-		return
+		return builder.exportMultisigSessionsCLI(tx)
 	}
 
 	cliPrivateAssetCreate := func(cmd string, ctx context.Context) (err error) {
@@ -324,9 +375,14 @@ func (builder *TxsBuilder) initCLI() {
 
 		extra.AssetId = builder.readAsset("Asset", false)
 
-		extra.AssetSupplyPrivateKey = gui.GUI.OutputReadBytes("Asset Supply Update Private Key", func(value []byte) bool {
-			return len(value) == cryptography.PrivateKeySize
+		extra.AssetSupplyPrivateKey = gui.GUI.OutputReadBytes("Asset Supply Update Private Key. Leave empty for a Threshold Key (multisig)", func(value []byte) bool {
+			return len(value) == 0 || len(value) == cryptography.PrivateKeySize
 		})
+		if len(extra.AssetSupplyPrivateKey) == 0 {
+			extra.AssetSupplyThresholdKey = gui.GUI.OutputReadBytes("Asset Supply Threshold Public Key", func(value []byte) bool {
+				return len(value) == cryptography.PublicKeySize
+			})
+		}
 
 		var receiverAddress *addresses.Address
 		if receiverAddress, _, extra.Value, err = builder.readAddressOptional("Receiver Address", extra.AssetId, false); err != nil {
@@ -352,9 +408,176 @@ func (builder *TxsBuilder) initCLI() {
 
 		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
 
+		return builder.exportMultisigSessionsCLI(tx)
+	}
+
+	cliPrivateAssetSupplyDecrease := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		extra := &wizard.WizardZetherPayloadExtraAssetSupplyDecrease{}
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: extra,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will burn the asset", ctx); err != nil {
+			return
+		}
+
+		txData.Payloads[0].Asset = builder.readAsset("Asset", false)
+
+		if txData.Payloads[0].Burn, err = builder.readAmount(txData.Payloads[0].Asset, "Amount to burn"); err != nil {
+			return
+		}
+
+		extra.AssetSupplyPrivateKey = gui.GUI.OutputReadBytes("Asset Supply Update Private Key. Leave empty for a Threshold Key (multisig)", func(value []byte) bool {
+			return len(value) == 0 || len(value) == cryptography.PrivateKeySize
+		})
+		if len(extra.AssetSupplyPrivateKey) == 0 {
+			extra.AssetSupplyThresholdKey = gui.GUI.OutputReadBytes("Asset Supply Threshold Public Key", func(value []byte) bool {
+				return len(value) == cryptography.PublicKeySize
+			})
+		}
+
+		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Transfer Address", txData.Payloads[0].Asset, true); err != nil {
+			return
+		}
+
+		txData.Payloads[0].RingConfiguration = builder.readZetherRingConfiguration()
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(txData.Payloads[0].Asset)
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+
+		return builder.exportMultisigSessionsCLI(tx)
+	}
+
+	readAssetUpdateKeys := func() (privateKey, thresholdKey []byte) {
+		privateKey = gui.GUI.OutputReadBytes("Asset Update Private Key. Leave empty for a Threshold Key (multisig)", func(value []byte) bool {
+			return len(value) == 0 || len(value) == cryptography.PrivateKeySize
+		})
+		if len(privateKey) == 0 {
+			thresholdKey = gui.GUI.OutputReadBytes("Asset Update Threshold Public Key", func(value []byte) bool {
+				return len(value) == cryptography.PublicKeySize
+			})
+		}
 		return
 	}
 
+	createAssetUpdateTx := func(txData *TxBuilderCreateZetherTxData, cmd string, ctx context.Context) (err error) {
+
+		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Transfer Address", config_coins.NATIVE_ASSET_FULL, true); err != nil {
+			return
+		}
+
+		txData.Payloads[0].RingConfiguration = builder.readZetherRingConfiguration()
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(config_coins.NATIVE_ASSET_FULL)
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+
+		return builder.exportMultisigSessionsCLI(tx)
+	}
+
+	cliPrivateAssetPause := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		extra := &wizard.WizardZetherPayloadExtraAssetPause{}
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: extra,
+				Asset: config_coins.NATIVE_ASSET_FULL,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will pause the asset", ctx); err != nil {
+			return
+		}
+
+		extra.AssetId = builder.readAsset("Asset", false)
+		extra.Paused = gui.GUI.OutputReadBool("Pause? y/n. Use n to unpause", false, false)
+		extra.AssetUpdatePrivateKey, extra.AssetUpdateThresholdKey = readAssetUpdateKeys()
+
+		return createAssetUpdateTx(txData, cmd, ctx)
+	}
+
+	cliPrivateAssetFreezeSupply := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		extra := &wizard.WizardZetherPayloadExtraAssetFreezeSupply{}
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: extra,
+				Asset: config_coins.NATIVE_ASSET_FULL,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will freeze the supply of the asset", ctx); err != nil {
+			return
+		}
+
+		extra.AssetId = builder.readAsset("Asset", false)
+		if !gui.GUI.OutputReadBool("Freezing the supply is permanent. Continue? y/n", false, false) {
+			return
+		}
+		extra.AssetUpdatePrivateKey, extra.AssetUpdateThresholdKey = readAssetUpdateKeys()
+
+		return createAssetUpdateTx(txData, cmd, ctx)
+	}
+
+	cliPrivateAssetUpdate := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		extra := &wizard.WizardZetherPayloadExtraAssetUpdate{}
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: extra,
+				Asset: config_coins.NATIVE_ASSET_FULL,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will update the asset", ctx); err != nil {
+			return
+		}
+
+		extra.AssetId = builder.readAsset("Asset", false)
+
+		extra.UpdateInfo = gui.GUI.OutputReadBool("Update Name, Description and Data? y/n. Leave empty for n", true, false)
+		if extra.UpdateInfo {
+			extra.Name = gui.GUI.OutputReadString("New Name")
+			extra.Description = gui.GUI.OutputReadString("New Description")
+			extra.Data = []byte(gui.GUI.OutputReadString("New Data. Leave empty for none"))
+		}
+
+		extra.NewUpdatePublicKey = gui.GUI.OutputReadBytes("New Update Public Key. Leave empty for no change", func(value []byte) bool {
+			return len(value) == 0 || len(value) == cryptography.PublicKeySize
+		})
+		extra.NewSupplyPublicKey = gui.GUI.OutputReadBytes("New Supply Public Key. Leave empty for no change", func(value []byte) bool {
+			return len(value) == 0 || len(value) == cryptography.PublicKeySize
+		})
+
+		extra.AssetUpdatePrivateKey, extra.AssetUpdateThresholdKey = readAssetUpdateKeys()
+
+		return createAssetUpdateTx(txData, cmd, ctx)
+	}
+
 	cliPrivatePlainAccountFund := func(cmd string, ctx context.Context) (err error) {
 		builder.showWarningIfNotSyncCLI()
 
@@ -456,7 +679,12 @@ func (builder *TxsBuilder) initCLI() {
 	gui.GUI.CommandDefineCallback("Private Transfer", cliPrivateTransfer, true)
 	gui.GUI.CommandDefineCallback("Private Asset Create", cliPrivateAssetCreate, true)
 	gui.GUI.CommandDefineCallback("Private Asset Supply Increase", cliPrivateAssetSupplyIncrease, true)
+	gui.GUI.CommandDefineCallback("Private Asset Supply Decrease", cliPrivateAssetSupplyDecrease, true)
+	gui.GUI.CommandDefineCallback("Private Asset Pause", cliPrivateAssetPause, true)
+	gui.GUI.CommandDefineCallback("Private Asset Freeze Supply", cliPrivateAssetFreezeSupply, true)
+	gui.GUI.CommandDefineCallback("Private Asset Update", cliPrivateAssetUpdate, true)
 	gui.GUI.CommandDefineCallback("Private Plain Account Fund", cliPrivatePlainAccountFund, true)
 	gui.GUI.CommandDefineCallback("Update Asset Fee Liquidity", cliUpdateAssetFeeLiquidity, true)
 
+	builder.initMultisigCLI()
 }
@@ -0,0 +1,145 @@
+package txs_builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/multisig"
+	"pandora-pay/helpers"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+//payload of a tx that has to be signed by the co-signers of a threshold key
+type TxBuilderMultisigSession struct {
+	Tx           []byte `json:"tx" msgpack:"tx"` //serialized tx with the missing signatures
+	PayloadIndex byte   `json:"payloadIndex" msgpack:"payloadIndex"`
+	PublicKey    []byte `json:"publicKey" msgpack:"publicKey"`
+	Message      []byte `json:"message" msgpack:"message"` //hash signed by the co-signers
+}
+
+func getZetherPayloadSignature(extra any) (publicKey []byte, signature *[]byte) {
+	switch payloadExtra := extra.(type) {
+	case *transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend:
+		return payloadExtra.SenderSpendPublicKey.EncodeCompressed(), &payloadExtra.SenderSpendSignature
+	case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyIncrease:
+		return payloadExtra.AssetSupplyPublicKey, &payloadExtra.AssetSignature
+	case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease:
+		return payloadExtra.AssetSupplyPublicKey, &payloadExtra.AssetSignature
+	case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause:
+		return payloadExtra.AssetUpdatePublicKey, &payloadExtra.AssetSignature
+	case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreezeSupply:
+		return payloadExtra.AssetUpdatePublicKey, &payloadExtra.AssetSignature
+	case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate:
+		return payloadExtra.AssetUpdatePublicKey, &payloadExtra.AssetSignature
+	}
+	return nil, nil
+}
+
+//returns a session for every payload that still misses its signature
+func GetMultisigSessions(tx *transaction.Transaction) []*TxBuilderMultisigSession {
+
+	if tx.Version != transaction_type.TX_ZETHER {
+		return nil
+	}
+
+	var data, message []byte
+	out := make([]*TxBuilderMultisigSession, 0)
+
+	for t, payload := range tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads {
+		publicKey, signature := getZetherPayloadSignature(payload.Extra)
+		if signature == nil || !bytes.Equal(*signature, helpers.EmptyBytes(cryptography.SignatureSize)) {
+			continue
+		}
+		if data == nil {
+			data, message = helpers.SerializeToBytes(tx), tx.SerializeForSigning()
+		}
+		out = append(out, &TxBuilderMultisigSession{data, byte(t), publicKey, message})
+	}
+
+	return out
+}
+
+//deserializes the session tx and checks that the message is the hash signed by the threshold key for the payload. The message is never trusted without it
+func (session *TxBuilderMultisigSession) GetTx(thresholdPublicKey []byte) (*transaction.Transaction, error) {
+
+	if !bytes.Equal(session.PublicKey, thresholdPublicKey) {
+		return nil, errors.New("Threshold Key is not matching the session")
+	}
+
+	tx := &transaction.Transaction{}
+	if err := tx.Deserialize(helpers.NewBufferReader(session.Tx)); err != nil {
+		return nil, err
+	}
+	if tx.Version != transaction_type.TX_ZETHER {
+		return nil, errors.New("Transaction is not zether")
+	}
+
+	payloads := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads
+	if int(session.PayloadIndex) >= len(payloads) {
+		return nil, errors.New("Invalid Payload Index")
+	}
+
+	publicKey, signature := getZetherPayloadSignature(payloads[session.PayloadIndex].Extra)
+	if signature == nil || !bytes.Equal(publicKey, session.PublicKey) {
+		return nil, errors.New("Payload doesn't require a threshold signature")
+	}
+	if !bytes.Equal(tx.SerializeForSigning(), session.Message) {
+		return nil, errors.New("Session message is not matching the transaction")
+	}
+
+	return tx, nil
+}
+
+//combines the partial signatures of the co-signers and sets the signature in the tx. The tx is propagated once all the payloads are signed
+func (builder *TxsBuilder) CombineMultisig(session *TxBuilderMultisigSession, key *multisig.ThresholdKey, commitments []*multisig.Commitment, partials []*multisig.PartialSignature, propagateTx, awaitAnswer, awaitBroadcast bool, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, error) {
+
+	tx, err := session.GetTx(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	_, signature := getZetherPayloadSignature(tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads[session.PayloadIndex].Extra)
+	if *signature, err = multisig.Combine(key, session.Message, commitments, partials); err != nil {
+		return nil, err
+	}
+	statusCallback("Threshold signature combined")
+
+	if err = tx.BloomAll(); err != nil {
+		return nil, err
+	}
+
+	if len(GetMultisigSessions(tx)) > 0 {
+		statusCallback("Transaction requires more threshold signatures")
+		return tx, nil
+	}
+
+	if err = builder.txsValidator.ValidateTx(tx); err != nil {
+		return nil, err
+	}
+	statusCallback("Transaction Verified")
+
+	if propagateTx {
+
+		var chainHeight uint64
+		if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+			chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		if err = builder.mempool.AddTxToMempool(tx, chainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
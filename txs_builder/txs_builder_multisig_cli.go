@@ -0,0 +1,362 @@
+package txs_builder
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/cryptography/multisig"
+	"pandora-pay/gui"
+	"strconv"
+)
+
+//the shares and the nonces are secret
+func writeMultisigFile(filename string, obj any) error {
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0600)
+}
+
+func readMultisigFile(filename string, obj any) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, obj)
+}
+
+//the amounts of the transfers are encrypted and the recipient is hidden among the recipient ring members
+func showMultisigTx(tx *transaction.Transaction, payloadIndex byte) {
+
+	gui.GUI.OutputWrite("Tx Hash", base64.StdEncoding.EncodeToString(tx.HashManual()))
+
+	for t, payload := range tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads {
+
+		signed := ""
+		if byte(t) == payloadIndex {
+			signed = " (signed by you)"
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Payload %d%s: %s", t, signed, payload.PayloadScript))
+		gui.GUI.OutputWrite("   Asset", base64.StdEncoding.EncodeToString(payload.Asset))
+		gui.GUI.OutputWrite("   Fee", payload.Statement.Fee)
+		gui.GUI.OutputWrite("   Burn", payload.BurnValue)
+
+		for i, publicKey := range payload.Statement.Publickeylist {
+			if (i%2 == 0) != payload.Parity {
+				gui.GUI.OutputWrite("   Recipient ring member", base64.StdEncoding.EncodeToString(publicKey.EncodeCompressed()))
+			}
+		}
+
+		if payload.Extra != nil {
+			if data, err := json.Marshal(payload.Extra); err == nil {
+				gui.GUI.OutputWrite("   Extra", string(data))
+			}
+		}
+	}
+}
+
+func (builder *TxsBuilder) exportMultisigSessionsCLI(tx *transaction.Transaction) (err error) {
+
+	sessions := GetMultisigSessions(tx)
+	if len(sessions) == 0 {
+		return
+	}
+
+	filename := gui.GUI.OutputReadFilename("Path to export the Multisig Session for the co-signers", "multisigsession")
+
+	for _, session := range sessions {
+
+		sessionFilename := filename
+		if len(sessions) > 1 {
+			sessionFilename = filename + "." + strconv.Itoa(int(session.PayloadIndex))
+		}
+
+		if err = writeMultisigFile(sessionFilename, session); err != nil {
+			return
+		}
+		gui.GUI.Info("Multisig Session for payload", session.PayloadIndex, "exported successfully to: ", sessionFilename)
+	}
+
+	return
+}
+
+func (builder *TxsBuilder) initMultisigCLI() {
+
+	//every co-signer runs the key generation on its own device. The private key of the threshold key is never known by anyone
+	cliKeyGeneration := func(cmd string, ctx context.Context) (err error) {
+
+		total := gui.GUI.OutputReadInt("Number of co-signers", false, 0, func(value int) bool {
+			return value >= 1 && value <= 255
+		})
+		threshold := gui.GUI.OutputReadInt("Number of co-signers required to sign", false, 0, func(value int) bool {
+			return value >= 1 && value <= total
+		})
+		index := gui.GUI.OutputReadUint64("Your co-signer index (1...number of co-signers). Every co-signer uses a different index", false, 0, func(value uint64) bool {
+			return value >= 1 && value <= uint64(total)
+		})
+
+		var gen *multisig.KeyGeneration
+		var commitment *multisig.KeyGenerationCommitment
+		if gen, commitment, err = multisig.CreateKeyGeneration(index, threshold, total); err != nil {
+			return
+		}
+
+		filename := gui.GUI.OutputReadFilename("Path to export the Key Generation Commitment for all the co-signers", "multisigkeygen")
+		genFilename := filename + ".secret"
+
+		if err = writeMultisigFile(genFilename, gen); err != nil {
+			return
+		}
+		if err = writeMultisigFile(filename, commitment); err != nil {
+			return
+		}
+
+		for to := uint64(1); to <= uint64(total); to++ {
+			if to == index {
+				continue
+			}
+
+			var share *multisig.KeyGenerationShare
+			if share, err = gen.GetShare(to); err != nil {
+				return
+			}
+
+			shareFilename := gui.GUI.OutputReadFilename(fmt.Sprintf("Path to export the share for the co-signer %d. Send it only to this co-signer", to), "multisigkeygenshare")
+			if err = writeMultisigFile(shareFilename, share); err != nil {
+				return
+			}
+		}
+
+		gui.GUI.Info("Key Generation Commitment exported successfully to: ", filename, ". Keep secret: ", genFilename)
+		return
+	}
+
+	cliKeyGenerationCombine := func(cmd string, ctx context.Context) (err error) {
+
+		genFilename := gui.GUI.OutputReadString("Path to your Key Generation secret")
+		gen := &multisig.KeyGeneration{}
+		if err = readMultisigFile(genFilename, gen); err != nil {
+			return
+		}
+
+		commitments := make([]*multisig.KeyGenerationCommitment, 0)
+		for {
+			filename := gui.GUI.OutputReadString("Path to a Key Generation Commitment, including yours. Leave empty to stop")
+			if filename == "" {
+				break
+			}
+			commitment := &multisig.KeyGenerationCommitment{}
+			if err = readMultisigFile(filename, commitment); err != nil {
+				return
+			}
+			commitments = append(commitments, commitment)
+		}
+
+		shares := make([]*multisig.KeyGenerationShare, 0)
+		for {
+			filename := gui.GUI.OutputReadString("Path to a share received from a co-signer. Leave empty to stop")
+			if filename == "" {
+				break
+			}
+			share := &multisig.KeyGenerationShare{}
+			if err = readMultisigFile(filename, share); err != nil {
+				return
+			}
+			shares = append(shares, share)
+		}
+
+		var share *multisig.ThresholdKeyShare
+		if share, err = multisig.CombineKeyGeneration(gen, commitments, shares); err != nil {
+			return
+		}
+
+		filename := gui.GUI.OutputReadFilename("Path to export the Threshold Key", "multisigkey")
+		if err = writeMultisigFile(filename, share.Key); err != nil {
+			return
+		}
+
+		shareFilename := gui.GUI.OutputReadFilename("Path to export your Threshold Key Share", "multisigshare")
+		if shareFilename == filename {
+			return errors.New("Share path must be different than the Threshold Key path")
+		}
+		if err = writeMultisigFile(shareFilename, share); err != nil {
+			return
+		}
+
+		//the polynomial reveals the shares of the other co-signers
+		if err = os.Remove(genFilename); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Threshold Public Key: " + base64.StdEncoding.EncodeToString(share.Key.PublicKey))
+		gui.GUI.Info("Threshold Key exported successfully to: ", filename, ". Keep secret: ", shareFilename)
+		return
+	}
+
+	readShare := func() (share *multisig.ThresholdKeyShare, err error) {
+		share = &multisig.ThresholdKeyShare{}
+		if err = readMultisigFile(gui.GUI.OutputReadString("Path to your Threshold Key Share"), share); err != nil {
+			return
+		}
+		return share, share.Validate()
+	}
+
+	readSession := func() (session *TxBuilderMultisigSession, err error) {
+		session = &TxBuilderMultisigSession{}
+		err = readMultisigFile(gui.GUI.OutputReadFilename("Path to the Multisig Session", "multisigsession"), session)
+		return
+	}
+
+	readCommitments := func() (commitments []*multisig.Commitment, err error) {
+		for {
+			filename := gui.GUI.OutputReadString("Path to a Commitment of a co-signer. Leave empty to stop")
+			if filename == "" {
+				return
+			}
+			commitment := &multisig.Commitment{}
+			if err = readMultisigFile(filename, commitment); err != nil {
+				return
+			}
+			commitments = append(commitments, commitment)
+		}
+	}
+
+	cliMultisigCommit := func(cmd string, ctx context.Context) (err error) {
+
+		var share *multisig.ThresholdKeyShare
+		if share, err = readShare(); err != nil {
+			return
+		}
+
+		var nonce *multisig.Nonce
+		var commitment *multisig.Commitment
+		if nonce, commitment, err = multisig.CreateNonce(share); err != nil {
+			return
+		}
+
+		filename := gui.GUI.OutputReadFilename("Path to export the Commitment", "multisigcommit")
+		nonceFilename := filename + ".nonce"
+
+		if err = writeMultisigFile(nonceFilename, nonce); err != nil {
+			return
+		}
+		if err = writeMultisigFile(filename, commitment); err != nil {
+			return
+		}
+
+		gui.GUI.Info("Commitment exported successfully to: ", filename, ". Keep secret and don't reuse the nonce: ", nonceFilename)
+		return
+	}
+
+	cliMultisigPartialSign := func(cmd string, ctx context.Context) (err error) {
+
+		var share *multisig.ThresholdKeyShare
+		if share, err = readShare(); err != nil {
+			return
+		}
+
+		var session *TxBuilderMultisigSession
+		if session, err = readSession(); err != nil {
+			return
+		}
+
+		//the message is signed only after the co-signer checks the tx it is computed from
+		var tx *transaction.Transaction
+		if tx, err = session.GetTx(share.Key.PublicKey); err != nil {
+			return
+		}
+		showMultisigTx(tx, session.PayloadIndex)
+
+		if !gui.GUI.OutputReadBool("Sign the transaction? y/n", false, false) {
+			return errors.New("Signing was canceled")
+		}
+
+		nonceFilename := gui.GUI.OutputReadString("Path to your Commitment Nonce")
+		nonce := &multisig.Nonce{}
+		if err = readMultisigFile(nonceFilename, nonce); err != nil {
+			return
+		}
+
+		var commitments []*multisig.Commitment
+		if commitments, err = readCommitments(); err != nil {
+			return
+		}
+
+		var partial *multisig.PartialSignature
+		if partial, err = multisig.Sign(share, nonce, session.Message, commitments); err != nil {
+			return
+		}
+
+		//a nonce used twice reveals the share
+		if err = os.Remove(nonceFilename); err != nil {
+			return
+		}
+
+		filename := gui.GUI.OutputReadFilename("Path to export the Partial Signature", "multisigpartial")
+		if err = writeMultisigFile(filename, partial); err != nil {
+			return
+		}
+
+		gui.GUI.Info("Partial Signature exported successfully to: ", filename)
+		return
+	}
+
+	cliMultisigCombine := func(cmd string, ctx context.Context) (err error) {
+
+		key := &multisig.ThresholdKey{}
+		if err = readMultisigFile(gui.GUI.OutputReadFilename("Path to the Threshold Key", "multisigkey"), key); err != nil {
+			return
+		}
+
+		var session *TxBuilderMultisigSession
+		if session, err = readSession(); err != nil {
+			return
+		}
+
+		var commitments []*multisig.Commitment
+		if commitments, err = readCommitments(); err != nil {
+			return
+		}
+
+		partials := make([]*multisig.PartialSignature, 0)
+		for {
+			filename := gui.GUI.OutputReadString("Path to a Partial Signature. Leave empty to stop")
+			if filename == "" {
+				break
+			}
+			partial := &multisig.PartialSignature{}
+			if err = readMultisigFile(filename, partial); err != nil {
+				return
+			}
+			partials = append(partials, partial)
+		}
+
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CombineMultisig(session, key, commitments, partials, propagate, true, true, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+		return builder.exportMultisigSessionsCLI(tx)
+	}
+
+	gui.GUI.CommandDefineCallback("Multisig Key Generation", cliKeyGeneration, true)
+	gui.GUI.CommandDefineCallback("Multisig Key Generation Combine", cliKeyGenerationCombine, true)
+	gui.GUI.CommandDefineCallback("Multisig Commit", cliMultisigCommit, true)
+	gui.GUI.CommandDefineCallback("Multisig Partial Sign", cliMultisigPartialSign, true)
+	gui.GUI.CommandDefineCallback("Multisig Combine", cliMultisigCombine, true)
+
+}
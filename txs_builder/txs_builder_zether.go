@@ -18,7 +18,9 @@ import (
 	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
 	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_fees"
 	"pandora-pay/cryptography/bn256"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
@@ -252,6 +254,17 @@ func (builder *TxsBuilder) prebuild(txData *TxBuilderCreateZetherTxData, pending
 		if payload.Data == nil {
 			payload.Data = &wizard.WizardTransactionData{[]byte{}, false}
 		}
+
+		//the PaymentID of the recipient is sent as the beginning of the encrypted message
+		if payload.Recipient != "" {
+			recipientAddr, err := addresses.DecodeAddr(payload.Recipient)
+			if err != nil {
+				return nil, nil, nil, nil, nil, nil, 0, nil, err
+			}
+			if recipientAddr.IsIntegratedPaymentID() && !bytes.HasPrefix(payload.Data.Data, recipientAddr.PaymentID) {
+				payload.Data = &wizard.WizardTransactionData{append(helpers.CloneBytes(recipientAddr.PaymentID), payload.Data.Data...), true}
+			}
+		}
 		if payload.RingConfiguration == nil {
 			payload.RingConfiguration = &ZetherRingConfiguration{-1, &ZetherSenderRingType{false, nil, 0}, &ZetherRecipientRingType{false, nil, 0}}
 		}
@@ -276,8 +289,8 @@ func (builder *TxsBuilder) prebuild(txData *TxBuilderCreateZetherTxData, pending
 				return nil, nil, nil, nil, nil, nil, 0, nil, err
 			}
 
-			if addr.PrivateKey == nil {
-				return nil, nil, nil, nil, nil, nil, 0, nil, errors.New("Can't be used for transactions as the private key is missing")
+			if err = addr.CheckCanSpend(); err != nil {
+				return nil, nil, nil, nil, nil, nil, 0, nil, err
 			}
 
 			if sendersPrivateKeys[t], err = addresses.NewPrivateKey(addr.PrivateKey.Key); err != nil {
@@ -490,13 +503,15 @@ func (builder *TxsBuilder) prebuild(txData *TxBuilderCreateZetherTxData, pending
 				if sender {
 					if reg != nil && len(reg.SpendPublicKey) > 0 && payload.Extra == nil {
 						transfers[t].SenderSpendRequired = true
-						if sendersWalletAddresses[t].SpendPrivateKey == nil {
-							return errors.New("Spend Private Key is missing")
-						}
 						if !bytes.Equal(sendersWalletAddresses[t].SpendPublicKey, reg.SpendPublicKey) {
 							return errors.New("Wallet Spend Public Key is not matching")
 						}
-						transfers[t].SenderSpendPrivateKey = sendersWalletAddresses[t].SpendPrivateKey.Key
+						if sendersWalletAddresses[t].SpendPrivateKey == nil {
+							//threshold spend key, the signature is added later by the co-signers
+							transfers[t].SenderSpendThresholdKey = reg.SpendPublicKey
+						} else {
+							transfers[t].SenderSpendPrivateKey = sendersWalletAddresses[t].SpendPrivateKey.Key
+						}
 					}
 				}
 
@@ -575,6 +590,33 @@ func (builder *TxsBuilder) CreateZetherTx(txData *TxBuilderCreateZetherTxData, p
 		pendingTxs = builder.mempool.Txs.GetTxsOnlyList()
 	}
 
+	var replaceFeePerByte uint64
+	if len(txData.ReplaceTxHash) > 0 {
+
+		replaceTx := builder.mempool.Txs.Get(string(txData.ReplaceTxHash))
+		if replaceTx == nil {
+			return nil, errors.New("Transaction to be replaced was not found in mempool")
+		}
+		if _, ok := replaceTx.Tx.TransactionBaseInterface.(*transaction_zether.TransactionZether); !ok {
+			return nil, errors.New("Transaction to be replaced is not a zether transaction")
+		}
+		replaceFeePerByte = replaceTx.FeePerByte
+
+		//the balances are computed without the replaced txs
+		excluded := map[string]bool{replaceTx.Tx.Bloom.HashStr: true}
+		for _, conflict := range builder.mempool.GetConflictingTxs(replaceTx) {
+			excluded[conflict.Tx.Bloom.HashStr] = true
+		}
+
+		newPendingTxs := make([]*transaction.Transaction, 0, len(pendingTxs))
+		for _, tx := range pendingTxs {
+			if !excluded[tx.Bloom.HashStr] {
+				newPendingTxs = append(newPendingTxs, tx)
+			}
+		}
+		pendingTxs = newPendingTxs
+	}
+
 	builder.lock.Lock()
 	defer builder.lock.Unlock()
 
@@ -586,6 +628,11 @@ func (builder *TxsBuilder) CreateZetherTx(txData *TxBuilderCreateZetherTxData, p
 	feesFinal := make([]*wizard.WizardTransactionFee, len(txData.Payloads))
 	for t, payload := range txData.Payloads {
 		feesFinal[t] = payload.Fee.WizardTransactionFee
+		//the automatic fee is bumped above the replaced tx
+		if replaceFeePerByte > 0 && feesFinal[t].PerByteAuto && feesFinal[t].PerByte == 0 && feesFinal[t].Fixed == 0 {
+			feesFinal[t].PerByte = replaceFeePerByte + 1
+			feesFinal[t].PerByteExtraSpace = config_fees.FEE_PER_BYTE_EXTRA_SPACE
+		}
 	}
 
 	var tx *transaction.Transaction
@@ -593,12 +640,23 @@ func (builder *TxsBuilder) CreateZetherTx(txData *TxBuilderCreateZetherTxData, p
 		return nil, err
 	}
 
+	//the tx is not valid until the co-signers add the threshold signatures
+	if len(GetMultisigSessions(tx)) > 0 {
+		statusCallback("Transaction requires threshold signatures")
+		return tx, nil
+	}
+
 	if err = builder.txsValidator.MarkAsValidatedTx(tx); err != nil {
 		return nil, err
 	}
 
 	if propagateTx {
-		if err = builder.mempool.AddTxToMempool(tx, chainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
+		if len(txData.ReplaceTxHash) > 0 {
+			err = builder.mempool.ReplaceTxInMempool(tx, txData.ReplaceTxHash, chainHeight, awaitAnswer, awaitBroadcast, ctx)
+		} else {
+			err = builder.mempool.AddTxToMempool(tx, chainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx)
+		}
+		if err != nil {
 			return nil, err
 		}
 	}
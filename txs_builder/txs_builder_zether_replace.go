@@ -0,0 +1,150 @@
+package txs_builder
+
+import (
+	"context"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_data"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_registrations/transaction_zether_registration"
+	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/wallet"
+)
+
+//the address includes the registration of the unregistered ring members
+func getReplacedTxRingMember(payload *transaction_zether_payload.TransactionZetherPayload, publicKeyList [][]byte, index int) (string, error) {
+
+	var addr *addresses.Address
+	var err error
+
+	if reg := payload.Registrations.Registrations[index]; reg != nil && reg.RegistrationType == transaction_zether_registration.NOT_REGISTERED {
+		addr, err = addresses.CreateAddr(publicKeyList[index], reg.RegistrationStaked, reg.RegistrationSpendPublicKey, reg.RegistrationSignature, nil, 0, nil)
+	} else {
+		addr, err = addresses.CreateAddr(publicKeyList[index], false, nil, nil, nil, 0, nil)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return addr.EncodeAddr(), nil
+}
+
+//the transfers of the pending tx are decrypted by the wallet of the sender. The replacement keeps the same recipients, amounts, data and rings,
+//so the real sender is not revealed by intersecting the rings of the two txs
+func (builder *TxsBuilder) getReplacedTxPayloads(tx *transaction.Transaction) ([]*TxBuilderCreateZetherTxPayload, error) {
+
+	txBase, ok := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+	if !ok {
+		return nil, errors.New("Transaction to be replaced is not a zether transaction")
+	}
+
+	payloads := make([]*TxBuilderCreateZetherTxPayload, len(txBase.Payloads))
+	for t, payload := range txBase.Payloads {
+
+		if payload.PayloadScript != transaction_zether_payload_script.SCRIPT_TRANSFER && payload.PayloadScript != transaction_zether_payload_script.SCRIPT_SPEND {
+			return nil, errors.New("Only transfers can be replaced")
+		}
+
+		publicKeyList := txBase.Bloom.PublicKeyLists[t]
+
+		var decrypted *wallet.DecryptZetherPayloadOutput
+		var sender string
+		senderIndex := -1
+		for i, publicKey := range publicKeyList {
+			if (i%2 == 0) != payload.Parity {
+				continue
+			}
+			addr := builder.wallet.GetWalletAddressByPublicKey(publicKey, true)
+			if addr == nil {
+				continue
+			}
+			decryptedTx, err := builder.wallet.DecryptTx(tx, publicKey)
+			if err != nil {
+				return nil, err
+			}
+			if output := decryptedTx.ZetherTx.Payloads[t]; output != nil && output.WhisperSenderValid {
+				decrypted, sender, senderIndex = output, addr.AddressEncoded, i
+				break
+			}
+		}
+
+		if decrypted == nil {
+			return nil, errors.New("Transaction to be replaced was not sent by this wallet")
+		}
+		if decrypted.RecipientIndex < 0 {
+			return nil, errors.New("Recipient of the transaction to be replaced was not found")
+		}
+
+		recipient, err := getReplacedTxRingMember(payload, publicKeyList, decrypted.RecipientIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		ringConfiguration := &ZetherRingConfiguration{len(publicKeyList), &ZetherSenderRingType{false, []string{}, 0}, &ZetherRecipientRingType{false, []string{}, 0}}
+		for i := range publicKeyList {
+			if i == senderIndex || i == decrypted.RecipientIndex {
+				continue
+			}
+			member, err := getReplacedTxRingMember(payload, publicKeyList, i)
+			if err != nil {
+				return nil, err
+			}
+			if (i%2 == 0) == payload.Parity {
+				ringConfiguration.SenderRingType.IncludeMembers = append(ringConfiguration.SenderRingType.IncludeMembers, member)
+			} else {
+				ringConfiguration.RecipientRingType.IncludeMembers = append(ringConfiguration.RecipientRingType.IncludeMembers, member)
+			}
+		}
+
+		data := &wizard.WizardTransactionData{[]byte{}, false}
+		switch payload.DataVersion {
+		case transaction_data.TX_DATA_PLAIN_TEXT:
+			data = &wizard.WizardTransactionData{payload.Data, false}
+		case transaction_data.TX_DATA_ENCRYPTED:
+			if decrypted.Message == nil {
+				return nil, errors.New("Data of the transaction to be replaced could not be decrypted")
+			}
+			data = &wizard.WizardTransactionData{decrypted.Message, true}
+		}
+
+		payloads[t] = &TxBuilderCreateZetherTxPayload{
+			Sender:            sender,
+			Asset:             payload.Asset,
+			Amount:            decrypted.SentAmount - payload.Statement.Fee - payload.BurnValue,
+			Recipient:         recipient,
+			Burn:              payload.BurnValue,
+			RingConfiguration: ringConfiguration,
+			Data:              data,
+		}
+	}
+
+	return payloads, nil
+}
+
+//rebuilds the pending tx of the wallet with a higher fee. A nil fee is bumped automatically above the fee of the replaced tx
+func (builder *TxsBuilder) CreateZetherReplacementTx(replaceTxHash []byte, fee *wizard.WizardZetherTransactionFee, propagateTx, awaitAnswer, awaitBroadcast bool, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, error) {
+
+	replaceTx := builder.mempool.Txs.Get(string(replaceTxHash))
+	if replaceTx == nil {
+		return nil, errors.New("Transaction to be replaced was not found in mempool")
+	}
+
+	payloads, err := builder.getReplacedTxPayloads(replaceTx.Tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, payload := range payloads {
+		payload.Fee = fee
+		if payload.Fee == nil {
+			payload.Fee = &wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, true}, true, 0, 0}
+		}
+	}
+
+	statusCallback("Transaction to be replaced decrypted")
+
+	return builder.CreateZetherTx(&TxBuilderCreateZetherTxData{payloads, replaceTxHash}, nil, propagateTx, awaitAnswer, awaitBroadcast, false, ctx, statusCallback)
+}
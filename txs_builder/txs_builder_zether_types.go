@@ -36,5 +36,6 @@ type TxBuilderCreateZetherTxPayload struct {
 }
 
 type TxBuilderCreateZetherTxData struct {
-	Payloads []*TxBuilderCreateZetherTxPayload `json:"payloads" msgpack:"payloads"`
+	Payloads      []*TxBuilderCreateZetherTxPayload `json:"payloads" msgpack:"payloads"`
+	ReplaceTxHash []byte                            `json:"replaceTxHash,omitempty" msgpack:"replaceTxHash,omitempty"` //pending tx rebuilt with a higher fee
 }
@@ -1,9 +1,12 @@
 package wizard
 
 import (
+	"errors"
+	"pandora-pay/addresses"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/config/config_fees"
+	"pandora-pay/cryptography"
 	"pandora-pay/helpers"
 )
 
@@ -61,3 +64,18 @@ func bloomAllTx(tx *transaction.Transaction, statusCallback func(string)) (err e
 
 	return
 }
+
+//without a private key, the signature is set later by combining the partial signatures of the threshold key
+func getSignKeys(privateKey, thresholdKey []byte) (*addresses.PrivateKey, []byte, error) {
+	if len(privateKey) > 0 {
+		privKey, err := addresses.NewPrivateKey(privateKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return privKey, privKey.GeneratePublicKey(), nil
+	}
+	if len(thresholdKey) != cryptography.PublicKeySize {
+		return nil, nil, errors.New("Private Key or Threshold Key is missing")
+	}
+	return nil, thresholdKey, nil
+}
@@ -281,6 +281,18 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_SPEND
 
+				if len(transfer.SenderSpendPrivateKey) == 0 {
+					senderSpendPublicKey := new(bn256.G1)
+					if err = senderSpendPublicKey.DecodeCompressed(transfer.SenderSpendThresholdKey); err != nil {
+						return
+					}
+					payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend{
+						SenderSpendPublicKey: senderSpendPublicKey,
+						SenderSpendSignature: helpers.EmptyBytes(cryptography.SignatureSize),
+					}
+					break
+				}
+
 				if privateKeysForSign[t], err = addresses.NewPrivateKey(transfer.SenderSpendPrivateKey); err != nil {
 					return
 				}
@@ -290,19 +302,76 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 			case *WizardZetherPayloadExtraAssetSupplyIncrease:
 				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE
-				if privateKeysForSign[t], err = addresses.NewPrivateKey(payloadExtra.AssetSupplyPrivateKey); err != nil {
+				var assetSupplyPublicKey []byte
+				if privateKeysForSign[t], assetSupplyPublicKey, err = getSignKeys(payloadExtra.AssetSupplyPrivateKey, payloadExtra.AssetSupplyThresholdKey); err != nil {
 					return
 				}
 				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyIncrease{
 					AssetId:              payloadExtra.AssetId,
 					ReceiverPublicKey:    payloadExtra.ReceiverPublicKey,
 					Value:                payloadExtra.Value,
-					AssetSupplyPublicKey: privateKeysForSign[t].GeneratePublicKey(),
+					AssetSupplyPublicKey: assetSupplyPublicKey,
 					AssetSignature:       helpers.EmptyBytes(cryptography.SignatureSize),
 				}
 
 				spaceExtra += 1 + len(payloadExtra.ReceiverPublicKey) + 66
 
+			case *WizardZetherPayloadExtraAssetSupplyDecrease:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE
+				var assetSupplyPublicKey []byte
+				if privateKeysForSign[t], assetSupplyPublicKey, err = getSignKeys(payloadExtra.AssetSupplyPrivateKey, payloadExtra.AssetSupplyThresholdKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease{
+					AssetSupplyPublicKey: assetSupplyPublicKey,
+					AssetSignature:       helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+			case *WizardZetherPayloadExtraAssetPause:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_PAUSE
+				var assetUpdatePublicKey []byte
+				if privateKeysForSign[t], assetUpdatePublicKey, err = getSignKeys(payloadExtra.AssetUpdatePrivateKey, payloadExtra.AssetUpdateThresholdKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause{
+					AssetId:              payloadExtra.AssetId,
+					Paused:               payloadExtra.Paused,
+					AssetUpdatePublicKey: assetUpdatePublicKey,
+					AssetSignature:       helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+			case *WizardZetherPayloadExtraAssetFreezeSupply:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_FREEZE_SUPPLY
+				var assetUpdatePublicKey []byte
+				if privateKeysForSign[t], assetUpdatePublicKey, err = getSignKeys(payloadExtra.AssetUpdatePrivateKey, payloadExtra.AssetUpdateThresholdKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreezeSupply{
+					AssetId:              payloadExtra.AssetId,
+					AssetUpdatePublicKey: assetUpdatePublicKey,
+					AssetSignature:       helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+			case *WizardZetherPayloadExtraAssetUpdate:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_UPDATE
+				var assetUpdatePublicKey []byte
+				if privateKeysForSign[t], assetUpdatePublicKey, err = getSignKeys(payloadExtra.AssetUpdatePrivateKey, payloadExtra.AssetUpdateThresholdKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate{
+					AssetId:              payloadExtra.AssetId,
+					UpdateInfo:           payloadExtra.UpdateInfo,
+					Name:                 payloadExtra.Name,
+					Description:          payloadExtra.Description,
+					Data:                 payloadExtra.Data,
+					NewUpdatePublicKey:   payloadExtra.NewUpdatePublicKey,
+					NewSupplyPublicKey:   payloadExtra.NewSupplyPublicKey,
+					AssetUpdatePublicKey: assetUpdatePublicKey,
+					AssetSignature:       helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+				spaceExtra += len(payloadExtra.Name) + len(payloadExtra.Description) + len(payloadExtra.Data)
+
 			case *WizardZetherPayloadExtraPlainAccountFund:
 				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND
 				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraPlainAccountFund{
@@ -629,6 +698,14 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyIncrease).AssetSignature = signature
 			case transaction_zether_payload_script.SCRIPT_SPEND:
 				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend).SenderSpendSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE_SUPPLY:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreezeSupply).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease).AssetSignature = signature
 			}
 
 		}
@@ -642,7 +719,7 @@ func CreateZetherTx(transfers []*WizardZetherTransfer, emap map[string]map[strin
 
 	for i, transfer := range transfers {
 		if transfer.SenderSpendRequired {
-			if len(transfer.SenderSpendPrivateKey) != cryptography.PrivateKeySize {
+			if len(transfer.SenderSpendPrivateKey) != cryptography.PrivateKeySize && (len(transfer.SenderSpendPrivateKey) > 0 || len(transfer.SenderSpendThresholdKey) != cryptography.PublicKeySize) {
 				return nil, fmt.Errorf("SpendPrivateKey is invalid for payload %d", i)
 			}
 			if transfer.PayloadExtra != nil {
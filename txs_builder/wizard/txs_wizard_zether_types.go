@@ -28,6 +28,42 @@ type WizardZetherPayloadExtraAssetSupplyIncrease struct {
 	ReceiverPublicKey        []byte `json:"receiverPublicKey" msgpack:"receiverPublicKey"`
 	Value                    uint64 `json:"value" msgpack:"value"`
 	AssetSupplyPrivateKey    []byte `json:"assetSupplyPublicKey" msgpack:"assetSupplyPublicKey"`
+	AssetSupplyThresholdKey  []byte `json:"assetSupplyThresholdKey,omitempty" msgpack:"assetSupplyThresholdKey,omitempty"` //used when AssetSupplyPrivateKey is missing. The signature is set later by combining partial signatures
+}
+
+//the payload Asset and Burn are the asset and the amount burned
+type WizardZetherPayloadExtraAssetSupplyDecrease struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetSupplyPrivateKey    []byte `json:"assetSupplyPrivateKey" msgpack:"assetSupplyPrivateKey"`
+	AssetSupplyThresholdKey  []byte `json:"assetSupplyThresholdKey,omitempty" msgpack:"assetSupplyThresholdKey,omitempty"`
+}
+
+type WizardZetherPayloadExtraAssetPause struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetId                  []byte `json:"assetId" msgpack:"assetId"`
+	Paused                   bool   `json:"paused" msgpack:"paused"`
+	AssetUpdatePrivateKey    []byte `json:"assetUpdatePrivateKey" msgpack:"assetUpdatePrivateKey"`
+	AssetUpdateThresholdKey  []byte `json:"assetUpdateThresholdKey,omitempty" msgpack:"assetUpdateThresholdKey,omitempty"`
+}
+
+type WizardZetherPayloadExtraAssetFreezeSupply struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetId                  []byte `json:"assetId" msgpack:"assetId"`
+	AssetUpdatePrivateKey    []byte `json:"assetUpdatePrivateKey" msgpack:"assetUpdatePrivateKey"`
+	AssetUpdateThresholdKey  []byte `json:"assetUpdateThresholdKey,omitempty" msgpack:"assetUpdateThresholdKey,omitempty"`
+}
+
+type WizardZetherPayloadExtraAssetUpdate struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetId                  []byte `json:"assetId" msgpack:"assetId"`
+	UpdateInfo               bool   `json:"updateInfo" msgpack:"updateInfo"`
+	Name                     string `json:"name" msgpack:"name"`
+	Description              string `json:"description" msgpack:"description"`
+	Data                     []byte `json:"data" msgpack:"data"`
+	NewUpdatePublicKey       []byte `json:"newUpdatePublicKey" msgpack:"newUpdatePublicKey"`
+	NewSupplyPublicKey       []byte `json:"newSupplyPublicKey" msgpack:"newSupplyPublicKey"`
+	AssetUpdatePrivateKey    []byte `json:"assetUpdatePrivateKey" msgpack:"assetUpdatePrivateKey"`
+	AssetUpdateThresholdKey  []byte `json:"assetUpdateThresholdKey,omitempty" msgpack:"assetUpdateThresholdKey,omitempty"`
 }
 
 type WizardZetherPayloadExtraPlainAccountFund struct {
@@ -39,19 +75,20 @@ type WizardZetherPayloadExtra interface {
 }
 
 type WizardZetherTransfer struct {
-	Asset                  []byte                   `json:"asset" msgpack:"asset"`
-	SenderPrivateKey       []byte                   `json:"senderPrivateKey" msgpack:"senderPrivateKey"` //private key
-	SenderDecryptedBalance uint64                   `json:"senderDecryptedBalance" msgpack:"senderDecryptedBalance"`
-	SenderSpendRequired    bool                     `json:"senderSpendRequired" msgpack:"senderSpendRequired"`
-	SenderSpendPrivateKey  []byte                   `json:"senderSpendPrivateKey" msgpack:"senderSpendPrivateKey"`
-	Recipient              string                   `json:"recipient" msgpack:"recipient"`
-	Amount                 uint64                   `json:"amount" msgpack:"amount"`
-	Burn                   uint64                   `json:"burn" msgpack:"burn"`
-	FeeRate                uint64                   `json:"feeRate" msgpack:"feeRate"`
-	FeeLeadingZeros        byte                     `json:"feeLeadingZeros" msgpack:"feeLeadingZeros"`
-	Data                   *WizardTransactionData   `json:"data" msgpack:"data"`
-	PayloadExtra           WizardZetherPayloadExtra `json:"payloadExtra" msgpack:"payloadExtra"`
-	WitnessIndexes         []int                    `json:"witnessIndexes" msgpack:"witnessIndexes"`
+	Asset                   []byte                   `json:"asset" msgpack:"asset"`
+	SenderPrivateKey        []byte                   `json:"senderPrivateKey" msgpack:"senderPrivateKey"` //private key
+	SenderDecryptedBalance  uint64                   `json:"senderDecryptedBalance" msgpack:"senderDecryptedBalance"`
+	SenderSpendRequired     bool                     `json:"senderSpendRequired" msgpack:"senderSpendRequired"`
+	SenderSpendPrivateKey   []byte                   `json:"senderSpendPrivateKey" msgpack:"senderSpendPrivateKey"`
+	SenderSpendThresholdKey []byte                   `json:"senderSpendThresholdKey,omitempty" msgpack:"senderSpendThresholdKey,omitempty"` //used when SenderSpendPrivateKey is missing. The signature is set later by combining partial signatures
+	Recipient               string                   `json:"recipient" msgpack:"recipient"`
+	Amount                  uint64                   `json:"amount" msgpack:"amount"`
+	Burn                    uint64                   `json:"burn" msgpack:"burn"`
+	FeeRate                 uint64                   `json:"feeRate" msgpack:"feeRate"`
+	FeeLeadingZeros         byte                     `json:"feeLeadingZeros" msgpack:"feeLeadingZeros"`
+	Data                    *WizardTransactionData   `json:"data" msgpack:"data"`
+	PayloadExtra            WizardZetherPayloadExtra `json:"payloadExtra" msgpack:"payloadExtra"`
+	WitnessIndexes          []int                    `json:"witnessIndexes" msgpack:"witnessIndexes"`
 }
 
 type WizardZetherPublicKeyIndex struct {
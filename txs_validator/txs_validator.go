@@ -88,6 +88,17 @@ func (validator *TxsValidator) ValidateTxs(txs []*transaction.Transaction) error
 	return nil
 }
 
+//returns the number of transactions still waiting to be validated
+func (validator *TxsValidator) GetPendingCount() (count int) {
+	validator.all.Range(func(key string, work *txValidatedWork) bool {
+		if atomic.LoadInt32(&work.status) != TX_VALIDATED_PROCCESSED {
+			count += 1
+		}
+		return true
+	})
+	return
+}
+
 func (validator *TxsValidator) runRemoveExpiredTransactions() {
 
 	c := 0
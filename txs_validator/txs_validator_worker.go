@@ -44,7 +44,7 @@ func (worker *TxsValidatorWorker) verifyTx(foundWork *txValidatedWork) error {
 
 		for _, payload := range base.Payloads {
 			switch payload.PayloadScript {
-			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE, transaction_zether_payload_script.SCRIPT_SPEND:
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE, transaction_zether_payload_script.SCRIPT_SPEND, transaction_zether_payload_script.SCRIPT_ASSET_PAUSE, transaction_zether_payload_script.SCRIPT_ASSET_FREEZE_SUPPLY, transaction_zether_payload_script.SCRIPT_ASSET_UPDATE, transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
 				if payload.Extra.VerifyExtraSignature(hashForSignature, payload.Statement) == false {
 					return errors.New("Extra signature failed")
 				}
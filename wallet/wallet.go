@@ -8,6 +8,7 @@ import (
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/mempool"
 	"pandora-pay/wallet/wallet_address"
+	"pandora-pay/wallet/wallet_invoice"
 	"sync"
 )
 
@@ -22,6 +23,7 @@ type Wallet struct {
 	Addresses               []*wallet_address.WalletAddress `json:"addresses" msgpack:"addresses"`
 	Loaded                  bool                            `json:"loaded" msgpack:"loaded"`
 	DelegatesCount          int                             `json:"delegatesCount" msgpack:"delegatesCount"`
+	Invoices                []*wallet_invoice.WalletInvoice `json:"invoices" msgpack:"invoices"`
 	addressesMap            map[string]*wallet_address.WalletAddress
 	forging                 *forging.Forging
 	mempool                 *mempool.Mempool
@@ -52,6 +54,7 @@ func (wallet *Wallet) clearWallet() {
 	wallet.CountImportedIndex = 0
 	wallet.Addresses = make([]*wallet_address.WalletAddress, 0)
 	wallet.addressesMap = make(map[string]*wallet_address.WalletAddress)
+	wallet.Invoices = make([]*wallet_invoice.WalletInvoice, 0)
 	wallet.Encryption = createEncryption(wallet)
 	wallet.nonHardening = false
 	wallet.setLoaded(false)
@@ -90,5 +93,6 @@ func (wallet *Wallet) InitializeWallet(updateNewChainUpdate *multicast.Multicast
 
 	if config.CONSENSUS == config.CONSENSUS_TYPE_FULL {
 		wallet.processRefreshWallets()
+		wallet.processInvoices()
 	}
 }
@@ -28,6 +28,9 @@ type WalletAddress struct {
 
 func (addr *WalletAddress) DeriveSharedStaked() (*shared_staked.WalletAddressSharedStaked, error) {
 
+	if addr.IsWatchOnly() {
+		return nil, errors.New("Watch-only address can't stake")
+	}
 	if addr.PrivateKey == nil {
 		return nil, errors.New("Private Key is missing")
 	}
@@ -39,6 +42,21 @@ func (addr *WalletAddress) DeriveSharedStaked() (*shared_staked.WalletAddressSha
 
 }
 
+func (addr *WalletAddress) IsWatchOnly() bool {
+	return addr.Version == VERSION_WATCH_ONLY
+}
+
+//the private key of a watch-only address is used only as view key
+func (addr *WalletAddress) CheckCanSpend() error {
+	if addr.IsWatchOnly() {
+		return errors.New("Watch-only address can't be used for transactions")
+	}
+	if addr.PrivateKey == nil {
+		return errors.New("Can't be used for transactions as the private key is missing")
+	}
+	return nil
+}
+
 func (addr *WalletAddress) GetAddress(registered bool) string {
 	if registered {
 		return addr.AddressEncoded
@@ -54,6 +72,9 @@ func (addr *WalletAddress) DecryptMessage(message []byte) ([]byte, error) {
 }
 
 func (addr *WalletAddress) SignMessage(message []byte) ([]byte, error) {
+	if addr.IsWatchOnly() {
+		return nil, errors.New("Watch-only address can't sign")
+	}
 	if addr.PrivateKey == nil {
 		return nil, errors.New("Private Key is missing")
 	}
@@ -3,13 +3,16 @@ package wallet_address
 type Version int
 
 const (
-	VERSION_NORMAL Version = iota
+	VERSION_NORMAL     Version = iota
+	VERSION_WATCH_ONLY         //only the view key, it can't spend
 )
 
 func (e Version) String() string {
 	switch e {
 	case VERSION_NORMAL:
 		return "VERSION_NORMAL"
+	case VERSION_WATCH_ONLY:
+		return "VERSION_WATCH_ONLY"
 	default:
 		return "Unknown Wallet Address Version"
 	}
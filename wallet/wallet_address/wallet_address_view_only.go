@@ -0,0 +1,36 @@
+package wallet_address
+
+import (
+	"errors"
+	"pandora-pay/addresses"
+)
+
+//view-only export of an address used to create watch-only addresses
+//the view key is the account private key, so only SpendRequired addresses can be exported as they can't be spent by whoever holds the export
+type WalletAddressViewOnlyExported struct {
+	Name           string                `json:"name" msgpack:"name"`
+	ViewKey        *addresses.PrivateKey `json:"viewKey" msgpack:"viewKey"`
+	Staked         bool                  `json:"staked" msgpack:"staked"`
+	SpendRequired  bool                  `json:"spendRequired" msgpack:"spendRequired"`
+	SpendPublicKey []byte                `json:"spendPublicKey,omitempty" msgpack:"spendPublicKey,omitempty"`
+	AddressEncoded string                `json:"addressEncoded" msgpack:"addressEncoded"`
+}
+
+func (addr *WalletAddress) ExportViewOnly() (*WalletAddressViewOnlyExported, error) {
+
+	if addr.PrivateKey == nil {
+		return nil, errors.New("Private Key is missing")
+	}
+	if !addr.SpendRequired || len(addr.SpendPublicKey) == 0 {
+		return nil, errors.New("Only addresses that require a spend key can be exported as view-only. The view key of this address can spend it")
+	}
+
+	return &WalletAddressViewOnlyExported{
+		addr.Name,
+		addr.PrivateKey,
+		addr.Staked,
+		addr.SpendRequired,
+		addr.SpendPublicKey,
+		addr.AddressEncoded,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package wallet_address
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"testing"
+)
+
+func TestExportViewOnly(t *testing.T) {
+
+	privateKey := addresses.GenerateNewPrivateKey()
+	spendPrivateKey := addresses.GenerateNewPrivateKey()
+
+	addr := &WalletAddress{
+		Name:       "Addr",
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.GeneratePublicKey(),
+	}
+
+	//the view key of an address without a spend key can spend it
+	_, err := addr.ExportViewOnly()
+	assert.Error(t, err)
+
+	addr.SpendRequired = true
+	_, err = addr.ExportViewOnly()
+	assert.Error(t, err)
+
+	addr.SpendPrivateKey = spendPrivateKey
+	addr.SpendPublicKey = spendPrivateKey.GeneratePublicKey()
+
+	exported, err := addr.ExportViewOnly()
+	assert.NoError(t, err)
+	assert.True(t, exported.SpendRequired)
+	assert.Equal(t, addr.SpendPublicKey, exported.SpendPublicKey)
+
+	data, err := json.Marshal(exported)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), base64.StdEncoding.EncodeToString(spendPrivateKey.Key))
+}
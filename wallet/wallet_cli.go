@@ -11,11 +11,13 @@ import (
 	"pandora-pay/blockchain/data_storage"
 	"pandora-pay/blockchain/data_storage/accounts"
 	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/assets"
 	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
 	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
@@ -24,6 +26,7 @@ import (
 	"pandora-pay/wallet/wallet_address"
 	"pandora-pay/wallet/wallet_address/shared_staked"
 	"strconv"
+	"time"
 )
 
 func (wallet *Wallet) exportSharedStakedAddress(addr *wallet_address.WalletAddress, path string, print bool) (*shared_staked.WalletAddressSharedStakedAddressExported, error) {
@@ -76,6 +79,7 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 		publicKey     []byte
 		name          string
 		addressString string
+		watchOnly     bool
 	}
 
 	wallet.Lock.RLock()
@@ -89,7 +93,7 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 	addresses := make([]*Address, len(wallet.Addresses))
 
 	for i, walletAddress := range wallet.Addresses {
-		addresses[i] = &Address{publicKey: helpers.CloneBytes(walletAddress.PublicKey), name: walletAddress.Name, addressString: walletAddress.GetAddress(false)}
+		addresses[i] = &Address{publicKey: helpers.CloneBytes(walletAddress.PublicKey), name: walletAddress.Name, addressString: walletAddress.GetAddress(false), watchOnly: walletAddress.IsWatchOnly()}
 	}
 	wallet.Lock.RUnlock()
 
@@ -151,7 +155,11 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 	var decrypted uint64
 	for i, address := range addresses {
 
-		gui.GUI.OutputWrite(fmt.Sprintf("%d) %s :: %s", i, address.name, address.addressString))
+		if address.watchOnly {
+			gui.GUI.OutputWrite(fmt.Sprintf("%d) %s :: %s (watch-only)", i, address.name, address.addressString))
+		} else {
+			gui.GUI.OutputWrite(fmt.Sprintf("%d) %s :: %s", i, address.name, address.addressString))
+		}
 
 		if len(addresses[i].assetsList) == 0 && addresses[i].plainAcc == nil {
 			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "", "EMPTY"))
@@ -328,6 +336,142 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliExportAddressViewOnlyJSON := func(cmd string, ctx context.Context) (err error) {
+
+		addr, _, _, err := wallet.CliSelectAddress("Select Address to be Exported as View-Only", ctx)
+		if err != nil {
+			return
+		}
+
+		exported, err := addr.ExportViewOnly()
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("The view-only file reveals the balances and the transactions of the address, but it can't spend without the spend key")
+
+		filename := gui.GUI.OutputReadFilename("Path to export", "pandoraview")
+
+		var marshal []byte
+		if marshal, err = json.Marshal(exported); err != nil {
+			return errors.New("Error marshaling address")
+		}
+
+		if err = os.WriteFile(filename, marshal, 0600); err != nil {
+			return
+		}
+
+		gui.GUI.Info("Exported successfully to: ", filename)
+		return
+	}
+
+	cliImportWatchOnlyAddressJSON := func(cmd string, ctx context.Context) (err error) {
+
+		str := gui.GUI.OutputReadFilename("Path to import View-Only Address", "pandoraview")
+
+		data, err := os.ReadFile(str)
+		if err != nil {
+			return
+		}
+
+		var adr *wallet_address.WalletAddress
+		if adr, err = wallet.ImportWatchOnlyAddressJSON(data); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Watch-only address was imported: " + adr.AddressEncoded)
+		return
+	}
+
+	cliCreateInvoice := func(cmd string, ctx context.Context) (err error) {
+
+		addr, _, _, err := wallet.CliSelectAddress("Select Address to receive the payment", ctx)
+		if err != nil {
+			return
+		}
+
+		assetId := gui.GUI.OutputReadBytes("Asset. Leave empty for Native Asset", func(value []byte) bool {
+			return len(value) == 0 || len(value) == config_coins.ASSET_LENGTH
+		})
+		if len(assetId) == 0 {
+			assetId = config_coins.NATIVE_ASSET_FULL
+		}
+		amountFloat := gui.GUI.OutputReadFloat64("Amount. Leave empty for any amount", true, 0, func(value float64) bool {
+			return value >= 0
+		})
+
+		var amount uint64
+		if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+			var ast *asset.Asset
+			if ast, err = assets.NewAssets(reader).GetAsset(assetId); err != nil {
+				return
+			}
+			if ast == nil {
+				return errors.New("Asset was not found")
+			}
+			amount, err = ast.ConvertToUnits(amountFloat)
+			return
+		}); err != nil {
+			return
+		}
+
+		memo := gui.GUI.OutputReadString("Memo. Leave empty for none")
+		expiresIn := gui.GUI.OutputReadUint64("Expires in minutes. Leave empty for never", true, 0, nil)
+
+		var expiry uint64
+		if expiresIn > 0 {
+			expiry = uint64(time.Now().Unix()) + expiresIn*60
+		}
+
+		invoice, err := wallet.CreateInvoice(addr.PublicKey, amount, assetId, memo, expiry)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Invoice created")
+		gui.GUI.OutputWrite("   PaymentID", base64.StdEncoding.EncodeToString(invoice.PaymentID))
+		gui.GUI.OutputWrite("   URI", invoice.URI)
+		return
+	}
+
+	cliListInvoices := func(cmd string, ctx context.Context) (err error) {
+
+		now := uint64(time.Now().Unix())
+		invoices := wallet.GetInvoices()
+
+		gui.GUI.OutputWrite("Invoices: " + strconv.Itoa(len(invoices)))
+		for i, invoice := range invoices {
+			gui.GUI.OutputWrite(fmt.Sprintf("%d) %s %8s paid %d / %d %s", i, base64.StdEncoding.EncodeToString(invoice.PaymentID), invoice.GetStatus(now), invoice.Paid, invoice.Amount, invoice.Memo))
+		}
+		return
+	}
+
+	cliInvoiceStatus := func(cmd string, ctx context.Context) (err error) {
+
+		paymentID := gui.GUI.OutputReadBytes("Invoice PaymentID", func(value []byte) bool {
+			return len(value) == 8
+		})
+
+		invoice := wallet.GetInvoice(paymentID)
+		if invoice == nil {
+			return errors.New("Invoice was not found")
+		}
+
+		gui.GUI.OutputWrite("Status", invoice.GetStatus(uint64(time.Now().Unix())).String())
+		gui.GUI.OutputWrite("Asset", base64.StdEncoding.EncodeToString(invoice.GetAsset()))
+		gui.GUI.OutputWrite("Amount", invoice.Amount)
+		gui.GUI.OutputWrite("Paid", invoice.Paid)
+		gui.GUI.OutputWrite("Memo", invoice.Memo)
+		if invoice.Expiry > 0 {
+			gui.GUI.OutputWrite("Expiry", time.Unix(int64(invoice.Expiry), 0).String())
+		}
+		gui.GUI.OutputWrite("URI", invoice.URI)
+		for _, payment := range invoice.Payments {
+			gui.GUI.OutputWrite(fmt.Sprintf("   Tx %s amount %d block %d", base64.StdEncoding.EncodeToString(payment.TxHash), payment.Amount, payment.BlockHeight))
+		}
+		return
+	}
+
 	cliExportWalletJSON := func(cmd string, ctx context.Context) (err error) {
 
 		filename := gui.GUI.OutputReadFilename("Path to export", "pandorawallet")
@@ -384,7 +528,18 @@ func (wallet *Wallet) initWalletCLI() {
 		staked := gui.GUI.OutputReadBool("Staked address ? y/n. Leave empty for n", true, false)
 		spendRequired := gui.GUI.OutputReadBool("Spend Key required ? y/n. Leave empty for n", true, false)
 
-		if _, err = wallet.AddNewAddress(true, name, staked, spendRequired, true); err != nil {
+		var spendThresholdKey []byte
+		if spendRequired {
+			spendThresholdKey = gui.GUI.OutputReadBytes("Spend Threshold Public Key (multisig). Leave empty to generate a Spend Key", func(value []byte) bool {
+				return len(value) == 0 || len(value) == cryptography.PublicKeySize
+			})
+		}
+
+		if len(spendThresholdKey) > 0 {
+			if _, err = wallet.AddNewAddressThresholdSpend(true, name, staked, spendThresholdKey, true); err != nil {
+				return
+			}
+		} else if _, err = wallet.AddNewAddress(true, name, staked, spendRequired, true); err != nil {
 			return
 		}
 		return wallet.CliListAddresses(cmd, ctx)
@@ -590,8 +745,13 @@ func (wallet *Wallet) initWalletCLI() {
 	gui.GUI.CommandDefineCallback("Export Addresses", cliExportAddresses, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Address JSON", cliExportAddressJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Address JSON", cliImportAddressJSON, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Export Address View-Only JSON", cliExportAddressViewOnlyJSON, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Import Watch-Only Address JSON", cliImportWatchOnlyAddressJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Wallet JSON", cliExportWalletJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Wallet JSON", cliImportWalletJSON, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Create Invoice", cliCreateInvoice, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("List Invoices", cliListInvoices, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Invoice Status", cliInvoiceStatus, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Encrypt Wallet", cliEncryptWallet, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Remove Encryption", cliRemoveEncryption, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Decrypt Wallet", cliDecryptWallet, !wallet.Loaded)
@@ -0,0 +1,122 @@
+package wallet_invoice
+
+import (
+	"bytes"
+	"pandora-pay/config/config_coins"
+)
+
+const INVOICES_MAXIMUM = 10000
+
+type WalletInvoiceStatus int
+
+const (
+	INVOICE_OPEN WalletInvoiceStatus = iota
+	INVOICE_PAID
+	INVOICE_EXPIRED
+)
+
+func (e WalletInvoiceStatus) String() string {
+	switch e {
+	case INVOICE_OPEN:
+		return "OPEN"
+	case INVOICE_PAID:
+		return "PAID"
+	case INVOICE_EXPIRED:
+		return "EXPIRED"
+	default:
+		return "Unknown Invoice Status"
+	}
+}
+
+type WalletInvoicePayment struct {
+	TxHash      []byte `json:"txHash" msgpack:"txHash"`
+	Amount      uint64 `json:"amount" msgpack:"amount"`
+	BlockHeight uint64 `json:"blockHeight" msgpack:"blockHeight"`
+	Late        bool   `json:"late,omitempty" msgpack:"late,omitempty"` //included in a block after the expiry. It is kept to be refunded, but it doesn't pay the invoice
+}
+
+//payment request matched by its PaymentID against the received Zether payloads
+type WalletInvoice struct {
+	PaymentID []byte                  `json:"paymentID" msgpack:"paymentID"`
+	PublicKey []byte                  `json:"publicKey" msgpack:"publicKey"`
+	Amount    uint64                  `json:"amount" msgpack:"amount"` //0 accepts any amount
+	Asset     []byte                  `json:"asset" msgpack:"asset"`
+	Memo      string                  `json:"memo,omitempty" msgpack:"memo,omitempty"`
+	Expiry    uint64                  `json:"expiry,omitempty" msgpack:"expiry,omitempty"` //unix timestamp. 0 means it never expires
+	Created   uint64                  `json:"created" msgpack:"created"`
+	URI       string                  `json:"uri" msgpack:"uri"`
+	Paid      uint64                  `json:"paid" msgpack:"paid"` //sum of the payments that are not late
+	Payments  []*WalletInvoicePayment `json:"payments" msgpack:"payments"`
+}
+
+func (invoice *WalletInvoice) GetAsset() []byte {
+	if len(invoice.Asset) == 0 {
+		return config_coins.NATIVE_ASSET_FULL
+	}
+	return invoice.Asset
+}
+
+func (invoice *WalletInvoice) GetStatus(now uint64) WalletInvoiceStatus {
+	for _, payment := range invoice.Payments {
+		if !payment.Late && invoice.Paid >= invoice.Amount {
+			return INVOICE_PAID
+		}
+	}
+	if invoice.Expiry != 0 && now > invoice.Expiry {
+		return INVOICE_EXPIRED
+	}
+	return INVOICE_OPEN
+}
+
+//returns false if the tx was already included. The payments included in a block after the expiry are late
+func (invoice *WalletInvoice) AddPayment(txHash []byte, amount, blockHeight, blockTimestamp uint64) bool {
+	for _, payment := range invoice.Payments {
+		if bytes.Equal(payment.TxHash, txHash) {
+			return false
+		}
+	}
+	late := invoice.Expiry != 0 && blockTimestamp > invoice.Expiry
+	invoice.Payments = append(invoice.Payments, &WalletInvoicePayment{txHash, amount, blockHeight, late})
+	if !late {
+		invoice.Paid += amount
+	}
+	return true
+}
+
+//payments of the blocks removed by a fork
+func (invoice *WalletInvoice) RemovePayments(fromBlockHeight uint64) bool {
+
+	payments := make([]*WalletInvoicePayment, 0, len(invoice.Payments))
+	for _, payment := range invoice.Payments {
+		if payment.BlockHeight < fromBlockHeight {
+			payments = append(payments, payment)
+		} else if !payment.Late {
+			invoice.Paid -= payment.Amount
+		}
+	}
+
+	if len(payments) == len(invoice.Payments) {
+		return false
+	}
+	invoice.Payments = payments
+	return true
+}
+
+func (invoice *WalletInvoice) Clone() *WalletInvoice {
+	payments := make([]*WalletInvoicePayment, len(invoice.Payments))
+	for i, payment := range invoice.Payments {
+		payments[i] = &WalletInvoicePayment{payment.TxHash, payment.Amount, payment.BlockHeight, payment.Late}
+	}
+	return &WalletInvoice{
+		invoice.PaymentID,
+		invoice.PublicKey,
+		invoice.Amount,
+		invoice.Asset,
+		invoice.Memo,
+		invoice.Expiry,
+		invoice.Created,
+		invoice.URI,
+		invoice.Paid,
+		payments,
+	}
+}
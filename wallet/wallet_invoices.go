@@ -0,0 +1,267 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/config/globals"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/recovery"
+	"pandora-pay/wallet/wallet_invoice"
+	"time"
+)
+
+//the PaymentID is sent as the first 8 bytes of the payload message
+const invoicePaymentIDSize = 8
+
+//must be locked before
+func (wallet *Wallet) getInvoice(paymentID []byte) *wallet_invoice.WalletInvoice {
+	for _, invoice := range wallet.Invoices {
+		if bytes.Equal(invoice.PaymentID, paymentID) {
+			return invoice
+		}
+	}
+	return nil
+}
+
+func (wallet *Wallet) CreateInvoice(publicKey []byte, amount uint64, asset []byte, memo string, expiry uint64) (*wallet_invoice.WalletInvoice, error) {
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	if len(wallet.Invoices) >= wallet_invoice.INVOICES_MAXIMUM {
+		return nil, errors.New("INVOICES_MAXIMUM exceeded")
+	}
+
+	now := uint64(time.Now().Unix())
+	if expiry != 0 && expiry <= now {
+		return nil, errors.New("Expiry is in the past")
+	}
+
+	addr := wallet.addressesMap[string(publicKey)]
+	if addr == nil {
+		return nil, errors.New("Address was not found")
+	}
+	if addr.PrivateKey == nil {
+		return nil, errors.New("Private Key is missing")
+	}
+
+	var paymentID []byte
+	for paymentID == nil || wallet.getInvoice(paymentID) != nil {
+		paymentID = helpers.RandomBytes(invoicePaymentIDSize)
+	}
+
+	var spendPublicKey []byte
+	if addr.SpendRequired {
+		spendPublicKey = addr.SpendPublicKey
+	}
+
+	address, err := addr.PrivateKey.GenerateAddress(addr.Staked, spendPublicKey, true, paymentID, amount, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &addresses.PaymentRequest{Address: address, Memo: memo, Expiry: expiry}
+
+	invoice := &wallet_invoice.WalletInvoice{
+		PaymentID: paymentID,
+		PublicKey: addr.PublicKey,
+		Amount:    amount,
+		Asset:     asset,
+		Memo:      memo,
+		Expiry:    expiry,
+		Created:   now,
+		URI:       req.EncodeURI(),
+		Payments:  []*wallet_invoice.WalletInvoicePayment{},
+	}
+
+	wallet.Invoices = append(wallet.Invoices, invoice)
+
+	if err = wallet.saveWallet(0, 0, -1, false); err != nil {
+		return nil, err
+	}
+	globals.MainEvents.BroadcastEvent("wallet/invoice-created", invoice.Clone())
+
+	return invoice.Clone(), nil
+}
+
+func (wallet *Wallet) GetInvoices() []*wallet_invoice.WalletInvoice {
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	list := make([]*wallet_invoice.WalletInvoice, len(wallet.Invoices))
+	for i, invoice := range wallet.Invoices {
+		list[i] = invoice.Clone()
+	}
+	return list
+}
+
+func (wallet *Wallet) GetInvoice(paymentID []byte) *wallet_invoice.WalletInvoice {
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if invoice := wallet.getInvoice(paymentID); invoice != nil {
+		return invoice.Clone()
+	}
+	return nil
+}
+
+//matches the payloads received by the wallet addresses against the invoices
+func (wallet *Wallet) ProcessInvoicesTx(tx *transaction.Transaction, blockHeight, blockTimestamp uint64) error {
+
+	if tx == nil || tx.Version != transaction_type.TX_ZETHER {
+		return nil
+	}
+
+	wallet.Lock.RLock()
+	keys := make(map[string]bool)
+	for _, invoice := range wallet.Invoices {
+		keys[string(invoice.PublicKey)] = true
+	}
+	wallet.Lock.RUnlock()
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := tx.BloomAll(); err != nil {
+		return err
+	}
+	txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+
+	found := make(map[string]bool)
+	for _, publicKeys := range txBase.Bloom.PublicKeyLists {
+		for _, publicKey := range publicKeys {
+			if keys[string(publicKey)] {
+				found[string(publicKey)] = true
+			}
+		}
+	}
+
+	type invoicePayment struct {
+		publicKey []byte
+		paymentID []byte
+		asset     []byte
+		amount    uint64
+	}
+
+	var received []*invoicePayment
+	for publicKey := range found {
+
+		decrypted, err := wallet.DecryptTx(tx, []byte(publicKey))
+		if err != nil {
+			return err
+		}
+
+		for _, payload := range decrypted.ZetherTx.Payloads {
+			if payload == nil || !payload.WhisperRecipientValid || payload.ReceivedAmount == 0 || len(payload.Message) < invoicePaymentIDSize {
+				continue
+			}
+			received = append(received, &invoicePayment{[]byte(publicKey), payload.Message[:invoicePaymentIDSize], payload.Asset, payload.ReceivedAmount})
+		}
+	}
+
+	if len(received) == 0 {
+		return nil
+	}
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	now := uint64(time.Now().Unix())
+
+	var changed bool
+	var paid []*wallet_invoice.WalletInvoice
+
+	for _, payment := range received {
+
+		invoice := wallet.getInvoice(payment.paymentID)
+		if invoice == nil || !bytes.Equal(invoice.PublicKey, payment.publicKey) || !bytes.Equal(invoice.GetAsset(), payment.asset) {
+			continue
+		}
+
+		wasPaid := invoice.GetStatus(now) == wallet_invoice.INVOICE_PAID
+		if invoice.AddPayment(tx.Bloom.Hash, payment.amount, blockHeight, blockTimestamp) {
+			changed = true
+			if !wasPaid && invoice.GetStatus(now) == wallet_invoice.INVOICE_PAID {
+				paid = append(paid, invoice.Clone())
+			}
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := wallet.saveWallet(0, 0, -1, false); err != nil {
+		return err
+	}
+
+	for _, invoice := range paid {
+		gui.GUI.Info("Invoice paid", base64.StdEncoding.EncodeToString(invoice.PaymentID))
+		globals.MainEvents.BroadcastEvent("wallet/invoice-paid", invoice)
+	}
+
+	return nil
+}
+
+//the payments included in blocks starting with fromBlockHeight were removed by a fork
+func (wallet *Wallet) RemoveInvoicesPayments(fromBlockHeight uint64) error {
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	changed := false
+	for _, invoice := range wallet.Invoices {
+		if invoice.RemovePayments(fromBlockHeight) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return wallet.saveWallet(0, 0, -1, false)
+}
+
+func (wallet *Wallet) processInvoices() {
+	recovery.SafeGo(func() {
+
+		updateNewChainCn := wallet.updateNewChainUpdate.AddListener()
+		defer wallet.updateNewChainUpdate.RemoveChannel(updateNewChainCn)
+
+		for {
+			update, ok := <-updateNewChainCn
+			if !ok {
+				return
+			}
+
+			//a rollback has no inserted blocks and the chain height is the first removed block
+			removedHeight := update.BlockHeight
+			if len(update.InsertedBlocks) > 0 {
+				removedHeight = update.InsertedBlocks[0].Block.Height
+			}
+
+			if err := wallet.RemoveInvoicesPayments(removedHeight); err != nil {
+				gui.GUI.Error("Error removing invoices payments", err)
+			}
+
+			for _, blkComplete := range update.InsertedBlocks {
+				for _, tx := range blkComplete.Txs {
+					if err := wallet.ProcessInvoicesTx(tx, blkComplete.Block.Height, blkComplete.Block.Timestamp); err != nil {
+						gui.GUI.Error("Error processing invoices", err)
+					}
+				}
+			}
+		}
+	})
+}
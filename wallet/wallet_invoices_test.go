@@ -0,0 +1,189 @@
+package wallet_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/address_balance_decryptor"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/blockchain/genesis"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forging"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"pandora-pay/mempool"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/txs_builder"
+	"pandora-pay/txs_validator"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_address"
+	"pandora-pay/wallet/wallet_invoice"
+	"testing"
+	"time"
+)
+
+const testSenderBalance = 1000000000
+
+func createTestStore(t *testing.T, name string) *store.Store {
+	db, err := store_db_memory.CreateStoreDBMemory(name)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return &store.Store{Name: name, Opened: true, DB: db}
+}
+
+//creates a chain whose genesis funds the sender and registers the ring members. The payee is not registered
+func createTestWallet(t *testing.T) (*wallet.Wallet, *txs_builder.TxsBuilder, *wallet_address.WalletAddress, *wallet_address.WalletAddress) {
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.NoError(t, err)
+
+	config_forging.FORGING_ENABLED = false
+
+	store.StoreBlockchain = createTestStore(t, "blockchain")
+	store.StoreWallet = createTestStore(t, "wallet")
+	store.StoreSettings = createTestStore(t, "settings")
+	store.StoreMempool = createTestStore(t, "mempool")
+	store.StoreBalancesDecrypted = createTestStore(t, "balancesDecrypted")
+
+	txsValidator, err := txs_validator.NewTxsValidator()
+	assert.NoError(t, err)
+	addressBalanceDecryptor, err := address_balance_decryptor.NewAddressBalanceDecryptor()
+	assert.NoError(t, err)
+	mempool, err := mempool.CreateMempool(txsValidator)
+	assert.NoError(t, err)
+	mempool.OnBroadcastNewTransaction = func(txs []*transaction.Transaction, justCreated, awaitPropagation bool, exceptSocketUUID advanced_connection_types.UUID, ctx context.Context) []error {
+		return make([]error, len(txs)) //there are no peers
+	}
+	forging, err := forging.CreateForging(mempool, addressBalanceDecryptor)
+	assert.NoError(t, err)
+	chain, err := blockchain.CreateBlockchain(mempool, txsValidator)
+	assert.NoError(t, err)
+	w, err := wallet.CreateWallet(forging, mempool, addressBalanceDecryptor)
+	assert.NoError(t, err)
+
+	sender, err := w.AddNewAddress(true, "sender", false, false, false)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	payee, err := w.AddNewAddress(true, "payee", false, false, false)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	genesis.GenesisData = &genesis.GenesisDataType{
+		Hash:       helpers.RandomBytes(cryptography.HashSize),
+		KernelHash: helpers.RandomBytes(cryptography.HashSize),
+		Timestamp:  uint64(time.Now().Add(-time.Hour).Unix()),
+		Target:     helpers.DecodeHex("0000000000FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF"),
+		AirDrops:   []*genesis.GenesisDataAirDropType{{Address: sender.AddressRegistrationEncoded, Amount: testSenderBalance}},
+	}
+	for i := 0; i < 150; i++ {
+		addr, err := addresses.GenerateNewPrivateKey().GenerateAddress(true, nil, true, nil, 0, nil)
+		assert.NoError(t, err)
+		genesis.GenesisData.AirDrops = append(genesis.GenesisData.AirDrops, &genesis.GenesisDataAirDropType{Address: addr.EncodeAddr()})
+	}
+	genesis.Genesis, err = genesis.CreateNewGenesisBlock()
+	assert.NoError(t, err)
+
+	if !assert.NoError(t, chain.InitializeChain()) {
+		t.FailNow()
+	}
+
+	return w, txs_builder.TxsBuilderInit(w, mempool, txsValidator), sender, payee
+}
+
+func TestProcessInvoicesTx(t *testing.T) {
+
+	w, builder, sender, payee := createTestWallet(t)
+
+	now := uint64(time.Now().Unix())
+
+	invoice, err := w.CreateInvoice(payee.PublicKey, 20, nil, "order", 0)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	expiring, err := w.CreateInvoice(payee.PublicKey, 20, nil, "", now+3600)
+	assert.NoError(t, err)
+	otherAsset, err := w.CreateInvoice(payee.PublicKey, 20, helpers.RandomBytes(config_coins.ASSET_LENGTH), "", 0)
+	assert.NoError(t, err)
+
+	//the txs are not included, so every tx spends the same balance
+	pay := func(paymentID []byte, amount uint64) *transaction.Transaction {
+		recipient, err := payee.PrivateKey.GenerateAddress(false, nil, true, paymentID, 0, nil)
+		assert.NoError(t, err)
+		tx, err := builder.CreateZetherTx(&txs_builder.TxBuilderCreateZetherTxData{
+			Payloads: []*txs_builder.TxBuilderCreateZetherTxPayload{{
+				Sender:           sender.AddressEncoded,
+				Recipient:        recipient.EncodeAddr(),
+				Amount:           amount,
+				DecryptedBalance: testSenderBalance,
+			}},
+		}, nil, false, false, false, true, context.Background(), func(string) {})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return tx
+	}
+
+	//a partial payment
+	tx1 := pay(invoice.PaymentID, 5)
+	assert.NoError(t, w.ProcessInvoicesTx(tx1, 10, now))
+	invoice = w.GetInvoice(invoice.PaymentID)
+	assert.Equal(t, uint64(5), invoice.Paid)
+	assert.Equal(t, wallet_invoice.INVOICE_OPEN, invoice.GetStatus(now))
+
+	tx2 := pay(invoice.PaymentID, 15)
+	assert.NoError(t, w.ProcessInvoicesTx(tx2, 11, now))
+	assert.NoError(t, w.ProcessInvoicesTx(tx2, 11, now)) //the same tx is counted once
+	invoice = w.GetInvoice(invoice.PaymentID)
+	assert.Equal(t, uint64(20), invoice.Paid)
+	assert.Len(t, invoice.Payments, 2)
+	assert.Equal(t, wallet_invoice.INVOICE_PAID, invoice.GetStatus(now))
+
+	//unknown PaymentID
+	assert.NoError(t, w.ProcessInvoicesTx(pay(helpers.RandomBytes(8), 20), 11, now))
+	for _, other := range w.GetInvoices() {
+		if other.Paid != 0 {
+			assert.Equal(t, invoice.PaymentID, other.PaymentID)
+		}
+	}
+
+	//the invoice requested another asset
+	assert.NoError(t, w.ProcessInvoicesTx(pay(otherAsset.PaymentID, 20), 11, now))
+	otherAsset = w.GetInvoice(otherAsset.PaymentID)
+	assert.Empty(t, otherAsset.Payments)
+	assert.Equal(t, wallet_invoice.INVOICE_OPEN, otherAsset.GetStatus(now))
+
+	//included in a block after the expiry
+	assert.NoError(t, w.ProcessInvoicesTx(pay(expiring.PaymentID, 20), 12, expiring.Expiry+1))
+	expiring = w.GetInvoice(expiring.PaymentID)
+	if assert.Len(t, expiring.Payments, 1) {
+		assert.True(t, expiring.Payments[0].Late)
+	}
+	assert.Equal(t, uint64(0), expiring.Paid)
+	assert.Equal(t, wallet_invoice.INVOICE_OPEN, expiring.GetStatus(now))
+	assert.Equal(t, wallet_invoice.INVOICE_EXPIRED, expiring.GetStatus(expiring.Expiry+1))
+
+	//the blocks starting with 11 were removed by a fork
+	assert.NoError(t, w.RemoveInvoicesPayments(11))
+	invoice = w.GetInvoice(invoice.PaymentID)
+	assert.Equal(t, uint64(5), invoice.Paid)
+	assert.Len(t, invoice.Payments, 1)
+	assert.Equal(t, wallet_invoice.INVOICE_OPEN, invoice.GetStatus(now))
+
+	expiring = w.GetInvoice(expiring.PaymentID)
+	assert.Empty(t, expiring.Payments)
+	assert.Equal(t, uint64(0), expiring.Paid)
+
+	assert.NoError(t, w.RemoveInvoicesPayments(0))
+	assert.Empty(t, w.GetInvoice(invoice.PaymentID).Payments)
+	assert.Equal(t, uint64(0), w.GetInvoice(invoice.PaymentID).Paid)
+}
@@ -231,7 +231,7 @@ func (wallet *Wallet) AddAddress(addr *wallet_address.WalletAddress, staked, spe
 	addr.Registration = addr2.Registration
 	addr.PublicKey = publicKey
 
-	if addr.PrivateKey != nil {
+	if addr.PrivateKey != nil && !addr.IsWatchOnly() {
 		if addr.SharedStaked, err = addr.DeriveSharedStaked(); err != nil {
 			return
 		}
@@ -322,6 +322,18 @@ func (wallet *Wallet) GenerateKeys(seedIndex uint32, lock bool) ([]byte, []byte,
 }
 
 func (wallet *Wallet) AddNewAddress(lock bool, name string, staked, spendRequired, save bool) (*wallet_address.WalletAddress, error) {
+	return wallet.addNewAddress(lock, name, staked, spendRequired, nil, save)
+}
+
+//the spend public key is a threshold key and the spend signatures are produced by its co-signers
+func (wallet *Wallet) AddNewAddressThresholdSpend(lock bool, name string, staked bool, spendPublicKey []byte, save bool) (*wallet_address.WalletAddress, error) {
+	if len(spendPublicKey) != cryptography.PublicKeySize {
+		return nil, errors.New("Invalid Spend Public Key")
+	}
+	return wallet.addNewAddress(lock, name, staked, true, spendPublicKey, save)
+}
+
+func (wallet *Wallet) addNewAddress(lock bool, name string, staked, spendRequired bool, spendPublicKey []byte, save bool) (*wallet_address.WalletAddress, error) {
 
 	//avoid generating the same address twice
 	if lock {
@@ -364,6 +376,11 @@ func (wallet *Wallet) AddNewAddress(lock bool, name string, staked, spendRequire
 		IsMine:          true,
 	}
 
+	if spendPublicKey != nil {
+		addr.SpendPrivateKey = nil
+		addr.SpendPublicKey = spendPublicKey
+	}
+
 	if err = wallet.AddAddress(addr, staked, spendRequired, false, true, false, save); err != nil {
 		return nil, err
 	}
@@ -517,6 +534,54 @@ func (wallet *Wallet) ImportWalletAddressJSON(data []byte) (*wallet_address.Wall
 	return addr, nil
 }
 
+//watch-only address that decrypts balances and txs using only the view key
+func (wallet *Wallet) ImportWatchOnlyAddressJSON(data []byte) (*wallet_address.WalletAddress, error) {
+
+	exported := &wallet_address.WalletAddressViewOnlyExported{}
+	if err := json.Unmarshal(data, exported); err != nil {
+		return nil, errors.New("Error unmarshaling view-only address")
+	}
+
+	if exported.ViewKey == nil || len(exported.ViewKey.Key) != cryptography.PrivateKeySize {
+		return nil, errors.New("View Key is missing")
+	}
+	if !exported.SpendRequired || len(exported.SpendPublicKey) != cryptography.PublicKeySize {
+		return nil, errors.New("View-only address must require a spend key")
+	}
+
+	viewKey, err := addresses.NewPrivateKey(exported.ViewKey.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if exported.AddressEncoded != "" {
+		address, err := viewKey.GenerateAddress(exported.Staked, exported.SpendPublicKey, false, nil, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+		if address.EncodeAddr() != exported.AddressEncoded {
+			return nil, errors.New("View Key doesn't match the address")
+		}
+	}
+
+	addr := &wallet_address.WalletAddress{
+		Version:        wallet_address.VERSION_WATCH_ONLY,
+		Name:           exported.Name,
+		PrivateKey:     viewKey,
+		SpendPublicKey: exported.SpendPublicKey,
+		IsImported:     true,
+	}
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if err = wallet.AddAddress(addr, exported.Staked, exported.SpendRequired, false, false, exported.Name == "", true); err != nil {
+		return nil, err
+	}
+
+	return addr.Clone(), nil
+}
+
 func (wallet *Wallet) DecryptBalance(addr *wallet_address.WalletAddress, encryptedBalance, asset []byte, useNewPreviousValue bool, newPreviousValue uint64, store bool, ctx context.Context, statusCallback func(string)) (uint64, error) {
 
 	if len(encryptedBalance) == 0 {
@@ -46,14 +46,16 @@ func Initialize(startMainCb func()) {
 			"createNewWallet":           js.FuncOf(createNewWallet),
 			"importMnemonic":            js.FuncOf(importMnemonic),
 			"manager": js.ValueOf(map[string]interface{}{
-				"getWalletAddress":        js.FuncOf(getWalletAddress),
-				"addNewWalletAddress":     js.FuncOf(addNewWalletAddress),
-				"removeWalletAddress":     js.FuncOf(removeWalletAddress),
-				"renameWalletAddress":     js.FuncOf(renameWalletAddress),
-				"importWalletSecretKey":   js.FuncOf(importWalletSecretKey),
-				"importWalletJSON":        js.FuncOf(importWalletJSON),
-				"exportWalletJSON":        js.FuncOf(exportWalletJSON),
-				"importWalletAddressJSON": js.FuncOf(importWalletAddressJSON),
+				"getWalletAddress":                 js.FuncOf(getWalletAddress),
+				"addNewWalletAddress":              js.FuncOf(addNewWalletAddress),
+				"removeWalletAddress":              js.FuncOf(removeWalletAddress),
+				"renameWalletAddress":              js.FuncOf(renameWalletAddress),
+				"importWalletSecretKey":            js.FuncOf(importWalletSecretKey),
+				"importWalletJSON":                 js.FuncOf(importWalletJSON),
+				"exportWalletJSON":                 js.FuncOf(exportWalletJSON),
+				"importWalletAddressJSON":          js.FuncOf(importWalletAddressJSON),
+				"importWatchOnlyWalletAddressJSON": js.FuncOf(importWatchOnlyWalletAddressJSON),
+				"exportViewOnlyWalletAddressJSON":  js.FuncOf(exportViewOnlyWalletAddressJSON),
 				"encryption": js.ValueOf(map[string]interface{}{
 					"checkPasswordWallet":    js.FuncOf(checkPasswordWallet),
 					"encryptWallet":          js.FuncOf(encryptWallet),
@@ -69,12 +71,21 @@ func Initialize(startMainCb func()) {
 			"tryDecryptBalance":               js.FuncOf(tryDecryptBalance),
 			"getPrivateKeysWalletAddress":     js.FuncOf(getPrivateKeysWalletAddress),
 			"decryptTx":                       js.FuncOf(decryptTx),
+			"invoices": js.ValueOf(map[string]interface{}{
+				"createWalletInvoice":          js.FuncOf(createWalletInvoice),
+				"getWalletInvoices":            js.FuncOf(getWalletInvoices),
+				"getWalletInvoice":             js.FuncOf(getWalletInvoice),
+				"processWalletInvoicesTx":      js.FuncOf(processWalletInvoicesTx),
+				"removeWalletInvoicesPayments": js.FuncOf(removeWalletInvoicesPayments),
+			}),
 		}),
 		"addresses": js.ValueOf(map[string]interface{}{
-			"createAddress":      js.FuncOf(createAddress),
-			"decodeAddress":      js.FuncOf(decodeAddress),
-			"generateAddress":    js.FuncOf(generateAddress),
-			"generateNewAddress": js.FuncOf(generateNewAddress),
+			"createAddress":           js.FuncOf(createAddress),
+			"decodeAddress":           js.FuncOf(decodeAddress),
+			"generateAddress":         js.FuncOf(generateAddress),
+			"generateNewAddress":      js.FuncOf(generateNewAddress),
+			"encodePaymentRequestURI": js.FuncOf(encodePaymentRequestURI),
+			"decodePaymentRequestURI": js.FuncOf(decodePaymentRequestURI),
 		}),
 		"cryptography": js.ValueOf(map[string]interface{}{
 			"HASH_SIZE":            js.ValueOf(cryptography.HashSize),
@@ -102,6 +113,7 @@ func Initialize(startMainCb func()) {
 			"getNetworkTxPreview":                    js.FuncOf(getNetworkTxPreview),
 			"getNetworkAccount":                      js.FuncOf(getNetworkAccount),
 			"getNetworkAccountTxs":                   js.FuncOf(getNetworkAccountTxs),
+			"getNetworkAccountHistory":               js.FuncOf(getNetworkAccountHistory),
 			"getNetworkAccountMempool":               js.FuncOf(getNetworkAccountMempool),
 			"getNetworkAccountMempoolNonce":          js.FuncOf(getNetworkAccountMempoolNonce),
 			"getNetworkAssetInfo":                    js.FuncOf(getNetworkAssetInfo),
@@ -109,6 +121,7 @@ func Initialize(startMainCb func()) {
 			"getNetworkMempool":                      js.FuncOf(getNetworkMempool),
 			"postNetworkMempoolBroadcastTransaction": js.FuncOf(postNetworkMempoolBroadcastTransaction),
 			"getNetworkFeeLiquidity":                 js.FuncOf(getNetworkFeeLiquidity),
+			"getNetworkFeeEstimate":                  js.FuncOf(getNetworkFeeEstimate),
 			"subscribeNetwork":                       js.FuncOf(subscribeNetwork),
 			"unsubscribeNetwork":                     js.FuncOf(unsubscribeNetwork),
 		}),
@@ -146,6 +159,10 @@ func Initialize(startMainCb func()) {
 						"SCRIPT_ASSET_CREATE":          js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_CREATE)),
 						"SCRIPT_ASSET_SUPPLY_INCREASE": js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE)),
 						"SCRIPT_PLAIN_ACCOUNT_FUND":    js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND)),
+						"SCRIPT_ASSET_PAUSE":           js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_PAUSE)),
+						"SCRIPT_ASSET_FREEZE_SUPPLY":   js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_FREEZE_SUPPLY)),
+						"SCRIPT_ASSET_UPDATE":          js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_UPDATE)),
+						"SCRIPT_ASSET_SUPPLY_DECREASE": js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE)),
 					}),
 				}),
 			}),
@@ -109,3 +109,38 @@ func generateAddress(this js.Value, args []js.Value) interface{} {
 		})
 	})
 }
+
+func encodePaymentRequestURI(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		parameters := &struct {
+			Address string `json:"address"`
+			Memo    string `json:"memo"`
+			Expiry  uint64 `json:"expiry"`
+		}{}
+
+		if err := webassembly_utils.UnmarshalBytes(args[0], parameters); err != nil {
+			return nil, err
+		}
+
+		addr, err := addresses.DecodeAddr(parameters.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		return (&addresses.PaymentRequest{Address: addr, Memo: parameters.Memo, Expiry: parameters.Expiry}).EncodeURI(), nil
+	})
+}
+
+func decodePaymentRequestURI(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		req, err := addresses.DecodePaymentRequestURI(args[0].String())
+		if err != nil {
+			return nil, err
+		}
+		return webassembly_utils.ConvertJSONBytes([]interface{}{
+			req,
+			req.Address.EncodeAddr(),
+		})
+	})
+}
@@ -214,6 +214,18 @@ func getNetworkAccountTxs(this js.Value, args []js.Value) interface{} {
 	})
 }
 
+func getNetworkAccountHistory(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		request := &api_common.APIAccountHistoryRequest{}
+		if err := webassembly_utils.UnmarshalBytes(args[0], request); err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertToJSONBytes(connection.SendJSONAwaitAnswer[api_common.APIAccountHistoryReply](app.Network.Websockets.GetFirstSocket(), []byte("account/history"), request, nil, 0))
+	})
+}
+
 func getNetworkAccountMempool(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 
@@ -384,6 +396,18 @@ func getNetworkFeeLiquidity(this js.Value, args []js.Value) interface{} {
 	})
 }
 
+func getNetworkFeeEstimate(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		asset, err := base64.StdEncoding.DecodeString(args[0].String())
+		if err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertToJSONBytes(connection.SendJSONAwaitAnswer[api_common.APIFeeEstimateReply](app.Network.Websockets.GetFirstSocket(), []byte("blockchain/fee-estimate"), &api_common.APIFeeEstimateRequest{asset, uint64(args[1].Int())}, nil, 0))
+	})
+}
+
 func subscribeNetwork(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 
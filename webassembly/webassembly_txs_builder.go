@@ -59,8 +59,8 @@ func createSimpleTx(this js.Value, args []js.Value) interface{} {
 			return nil, err
 		}
 
-		if senderWalletAddr.PrivateKey.Key == nil {
-			return nil, errors.New("Can't be used for transactions as the private key is missing")
+		if err = senderWalletAddr.CheckCanSpend(); err != nil {
+			return nil, err
 		}
 
 		tx, err := wizard.CreateSimpleTx(&wizard.WizardTxSimpleTransfer{
@@ -2,6 +2,7 @@ package webassembly
 
 import (
 	"encoding/base64"
+	"errors"
 	"pandora-pay/app"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/helpers"
@@ -202,6 +203,41 @@ func importWalletAddressJSON(this js.Value, args []js.Value) interface{} {
 	})
 }
 
+func importWatchOnlyWalletAddressJSON(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return nil, err
+		}
+		adr, err := app.Wallet.ImportWatchOnlyAddressJSON([]byte(args[1].String()))
+		if err != nil {
+			return nil, err
+		}
+		return webassembly_utils.ConvertJSONBytes(adr)
+	})
+}
+
+func exportViewOnlyWalletAddressJSON(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return nil, err
+		}
+
+		adr, err := app.Wallet.GetWalletAddressByPublicKeyString(args[1].String(), true)
+		if err != nil {
+			return nil, err
+		}
+		if adr == nil {
+			return nil, errors.New("Address was not found")
+		}
+
+		exported, err := adr.ExportViewOnly()
+		if err != nil {
+			return nil, err
+		}
+		return webassembly_utils.ConvertJSONBytes(exported)
+	})
+}
+
 func checkPasswordWallet(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
@@ -250,7 +286,7 @@ func logoutWallet(this js.Value, args []js.Value) interface{} {
 	})
 }
 
-//signing not encrypting
+// signing not encrypting
 func signMessageWalletAddress(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
@@ -0,0 +1,84 @@
+package webassembly
+
+import (
+	"encoding/base64"
+	"errors"
+	"pandora-pay/app"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/helpers"
+	"pandora-pay/webassembly/webassembly_utils"
+	"syscall/js"
+)
+
+func createWalletInvoice(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return nil, err
+		}
+
+		parameters := &struct {
+			PublicKey []byte `json:"publicKey"`
+			Amount    uint64 `json:"amount"`
+			Asset     []byte `json:"asset"`
+			Memo      string `json:"memo"`
+			Expiry    uint64 `json:"expiry"`
+		}{}
+
+		if err := webassembly_utils.UnmarshalBytes(args[1], parameters); err != nil {
+			return nil, err
+		}
+
+		invoice, err := app.Wallet.CreateInvoice(parameters.PublicKey, parameters.Amount, parameters.Asset, parameters.Memo, parameters.Expiry)
+		if err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertJSONBytes(invoice)
+	})
+}
+
+func getWalletInvoices(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		return webassembly_utils.ConvertJSONBytes(app.Wallet.GetInvoices())
+	})
+}
+
+func getWalletInvoice(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		paymentID, err := base64.StdEncoding.DecodeString(args[0].String())
+		if err != nil {
+			return nil, err
+		}
+
+		invoice := app.Wallet.GetInvoice(paymentID)
+		if invoice == nil {
+			return nil, errors.New("Invoice was not found")
+		}
+
+		return webassembly_utils.ConvertJSONBytes(invoice)
+	})
+}
+
+//the light client processes the txs received from the subscriptions
+func processWalletInvoicesTx(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		tx := &transaction.Transaction{}
+		if err := tx.Deserialize(helpers.NewBufferReader(webassembly_utils.GetBytes(args[0]))); err != nil {
+			return nil, err
+		}
+
+		if err := app.Wallet.ProcessInvoicesTx(tx, uint64(args[1].Int()), uint64(args[2].Int())); err != nil {
+			return nil, err
+		}
+		return true, nil
+	})
+}
+
+func removeWalletInvoicesPayments(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		return true, app.Wallet.RemoveInvoicesPayments(uint64(args[0].Int()))
+	})
+}
@@ -101,6 +101,14 @@ func prepareData(txData *zetherTxDataBase) (transfers []*wizard.WizardZetherTran
 			payloadExtra = &wizard.WizardZetherPayloadExtraAssetSupplyIncrease{}
 		case transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND:
 			payloadExtra = &wizard.WizardZetherPayloadExtraPlainAccountFund{}
+		case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+			payloadExtra = &wizard.WizardZetherPayloadExtraAssetPause{}
+		case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE_SUPPLY:
+			payloadExtra = &wizard.WizardZetherPayloadExtraAssetFreezeSupply{}
+		case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE:
+			payloadExtra = &wizard.WizardZetherPayloadExtraAssetUpdate{}
+		case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+			payloadExtra = &wizard.WizardZetherPayloadExtraAssetSupplyDecrease{}
 		default:
 			err = errors.New("Invalid PayloadScriptType")
 			return